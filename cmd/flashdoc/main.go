@@ -1,213 +1,853 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"reflect"
 	"time"
 
 	"github.com/heidene/flashdoc/internal/browser"
 	"github.com/heidene/flashdoc/internal/builder"
+	"github.com/heidene/flashdoc/internal/checker"
 	"github.com/heidene/flashdoc/internal/cleanup"
 	"github.com/heidene/flashdoc/internal/cli"
+	"github.com/heidene/flashdoc/internal/doctor"
 	"github.com/heidene/flashdoc/internal/exporter"
+	"github.com/heidene/flashdoc/internal/ignore"
 	"github.com/heidene/flashdoc/internal/installer"
+	"github.com/heidene/flashdoc/internal/logging"
 	"github.com/heidene/flashdoc/internal/pkgmanager"
 	"github.com/heidene/flashdoc/internal/processor"
+	"github.com/heidene/flashdoc/internal/progress"
+	"github.com/heidene/flashdoc/internal/renderer"
+	"github.com/heidene/flashdoc/internal/runlog"
+	"github.com/heidene/flashdoc/internal/server"
 	"github.com/heidene/flashdoc/internal/shared"
 	"github.com/heidene/flashdoc/internal/signal"
 	"github.com/heidene/flashdoc/internal/staticserver"
 	"github.com/heidene/flashdoc/internal/template"
+	"github.com/heidene/flashdoc/internal/theme"
+	"github.com/heidene/flashdoc/internal/watcher"
 	"github.com/heidene/flashdoc/internal/workspace"
 )
 
 func main() {
 	// Parse CLI arguments
-	cfg, helpOrVersion, err := cli.Parse(os.Args[1:])
+	cmd, logCfg, err := cli.ParseWithLogging(os.Args[1:])
+	setupLogging(logCfg)
 	if err != nil {
 		// Error occurred during parsing
+		logging.Errorf("%v", err)
 		os.Exit(1)
 	}
-	if helpOrVersion {
+	if cmd == nil {
 		// --help or --version was used, exit successfully
 		os.Exit(0)
 	}
 
+	switch cfg := cmd.(type) {
+	case *cli.CheckConfig:
+		// check only needs the source files - no workspace, Node or dependencies
+		os.Exit(runCheck(cfg))
+	case *cli.CacheConfig:
+		os.Exit(runCache(cfg))
+	case *cli.DoctorConfig:
+		os.Exit(runDoctor(cfg))
+	case *cli.ServeConfig:
+		err = runServe(cfg)
+	case *cli.BuildConfig:
+		err = runBuild(cfg)
+	case *cli.ExportConfig:
+		err = runExport(cfg)
+	default:
+		err = fmt.Errorf("unknown command %q", cmd.Name())
+	}
+
+	if err != nil {
+		logging.Errorf("%v", err)
+		os.Exit(1)
+	}
+}
+
+// siteBuilder builds the static site into the workspace's dist directory
+type siteBuilder interface {
+	Build() error
+}
+
+// site is a processed workspace ready to be built or served
+type site struct {
+	cfg        *cli.SiteConfig
+	title      string
+	native     bool // Render with the native Go renderer instead of Astro
+	pm         pkgmanager.PackageManager
+	ws         *workspace.Workspace
+	ignore     *ignore.Matcher           // Source paths left out of the site
+	theme      theme.Theme               // Logo, favicon, stylesheets and accent color
+	starlight  *template.StarlightConfig // Options written to astro.config.mjs; nil when rendering natively
+	log        *runlog.Log               // Install and build output; nil when rendering natively
+	proc       *processor.Processor
+	cleanupMgr *cleanup.Manager
+	sigHandler *signal.Handler
+}
+
+// prepareSite installs dependencies if needed, creates the run workspace and processes the
+// source files into it. Close must be called to remove the workspace.
+func prepareSite(cfg *cli.SiteConfig) (*site, error) {
+	if cfg.ConfigFile != "" {
+		logging.Infof("⚙️  Config: %s", cfg.ConfigFile)
+	}
+	if cfg.MkDocs != nil {
+		logging.Infof("📘 MkDocs project: %s", cfg.MkDocs.Path)
+	}
+	if cfg.Book != nil {
+		logging.Infof("📖 Book summary: %s", cfg.Book.Summary)
+	}
+
+	s := &site{cfg: cfg}
+
 	// Create shared project manager
 	sharedMgr, err := shared.NewManager()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: failed to create shared manager: %v\n", err)
-		os.Exit(1)
+		return nil, fmt.Errorf("failed to create shared manager: %w", err)
 	}
 
 	// Ensure directories exist
 	if err := sharedMgr.EnsureDirectories(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: failed to create directories: %v\n", err)
-		os.Exit(1)
+		return nil, fmt.Errorf("failed to create directories: %w", err)
 	}
 
 	// Cleanup old runs (older than 24 hours)
 	if err := sharedMgr.CleanupOldRuns(24 * time.Hour); err != nil {
 		// Log warning but don't fail
-		fmt.Fprintf(os.Stderr, "Warning: failed to cleanup old runs: %v\n", err)
+		logging.Warnf("failed to cleanup old runs: %v", err)
+	}
+
+	// Pick the renderer; auto falls back to the native one when Node.js tooling is missing
+	s.native = cfg.Renderer == cli.RendererNative
+	if !s.native {
+		s.pm, err = pkgmanager.Select(cfg.PackageManager)
+		if err != nil {
+			// A package manager the user asked for must be there
+			if cfg.Renderer == cli.RendererAstro || (cfg.PackageManager != "" && cfg.PackageManager != pkgmanager.Auto) {
+				return nil, err
+			}
+			logging.Infof("ℹ️  No package manager found, using the native renderer (no search, install Node.js for the full Starlight site)")
+			s.native = true
+		} else {
+			logging.Debugf("using %s", s.pm)
+		}
+	}
+
+	// Generate unique run ID
+	runID := sharedMgr.GenerateRunID()
+	runDir := sharedMgr.GetRunDir(runID)
+
+	if !s.native {
+		// Capture install and build output so failures can be explained
+		s.log, err = runlog.Open(sharedMgr.GetLogPath(runID), logging.Default().Enabled(logging.LevelDebug))
+		if err != nil {
+			return nil, err
+		}
+		logging.Debugf("run log: %s", s.log.Path())
+		if err := installShared(sharedMgr, s.pm, s.log, cfg.ForceReinstall, cfg.BreakLock); err != nil {
+			_ = s.log.Close()
+			return nil, err
+		}
+	}
+
+	// Create workspace with symlinks to shared project
+	s.ws, err = workspace.New(runDir, sharedMgr.GetSharedDir())
+	if err != nil {
+		_ = s.log.Close()
+		return nil, fmt.Errorf("failed to create workspace: %w", err)
+	}
+
+	// Keep cache prune/clear in other terminals away from this run, already while it is
+	// being set up
+	if err := shared.MarkRunInUse(s.ws.Path); err != nil {
+		logging.Warnf("%v", err)
+	}
+
+	// Setup workspace structure (creates symlinks)
+	if err := s.ws.Setup(); err != nil {
+		_ = s.ws.Cleanup()
+		_ = s.log.Close()
+		return nil, fmt.Errorf("failed to setup workspace: %w", err)
+	}
+
+	// Setup cleanup manager
+	s.cleanupMgr = cleanup.New(s.ws)
+
+	// Setup signal handling
+	s.sigHandler = signal.New(s.cleanupMgr.Cleanup)
+	s.sigHandler.Setup()
+
+	// Log workspace path
+	logging.Infof("📦 Workspace: %s", s.ws.Path)
+
+	if err := s.setup(); err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// setup writes the Astro config (unless rendering natively) and processes the source files
+func (s *site) setup() error {
+	// Generate config with title
+	s.title = s.cfg.Title
+	if s.title == "" {
+		s.title = template.GenerateTitle(s.cfg.SourceDir)
+	}
+
+	// Files declared in the config win over conventional ones in the source directory
+	s.theme = theme.Discover(s.cfg.SourceDir, theme.Theme{Logo: s.cfg.Logo, Favicon: s.cfg.Favicon, CustomCSS: s.cfg.CustomCSS})
+	s.theme.Accent, _ = theme.ParseAccent(s.cfg.AccentColor) // Checked by Validate
+
+	if !s.native {
+		// Extract config files only (not package.json, which is symlinked)
+		if err := template.ExtractConfigOnly(s.ws.Path); err != nil {
+			return fmt.Errorf("failed to extract config: %w", err)
+		}
+
+		s.starlight = &template.StarlightConfig{
+			Title:  s.title,
+			Social: template.SocialLinks(s.cfg.Social),
+		}
+		if err := s.theme.Apply(s.ws.Path, s.starlight); err != nil {
+			return err
+		}
+	}
+
+	// Read .gitignore/.flashdocignore files and the exclude/include patterns
+	var err error
+	s.ignore, err = ignore.Load(s.cfg.SourceDir, ignore.Options{Exclude: s.cfg.Exclude, Include: s.cfg.Include})
+	if err != nil {
+		return err
 	}
 
+	// Process markdown files
+	opts := processor.Options{
+		Ignore:         s.ignore,
+		SidebarOrder:   s.cfg.SidebarOrder,
+		PublicDir:      s.ws.GetPublicDir(),
+		Titles:         s.cfg.Titles,
+		FixFrontmatter: s.cfg.FixFrontmatter,
+		Admonitions:    s.cfg.MkDocs != nil,
+		Includes:       s.cfg.Book != nil,
+	}
+	if s.cfg.Components != "" {
+		opts.Components = s.cfg.ComponentsRel()
+		if !s.native {
+			opts.ComponentsTarget = s.ws.GetComponentsDir()
+		}
+	}
+	s.proc = processor.NewWithOptions(s.cfg.SourceDir, s.ws.GetDocsDir(), opts)
+	if err := s.proc.Process(); err != nil {
+		return err
+	}
+	if s.log != nil {
+		// Point build errors at the source files instead of their workspace copies
+		s.log.SetLocator(s.proc.SourceLocation)
+	}
+
+	// The sidebar comes from the processed pages, so the config is written last
+	if s.starlight != nil {
+		s.starlight.Sidebar = s.sidebar()
+		if err := template.WriteConfig(s.ws.Path, s.starlight); err != nil {
+			return err
+		}
+	}
+
+	// Astro would fail the build on these with little detail, so stop with the report above
+	if invalid := len(s.proc.Invalid()); invalid > 0 && !s.native && !s.cfg.FixFrontmatter {
+		return fmt.Errorf("%d frontmatter value(s) would fail the Starlight build; fix them or run with --fix-frontmatter", invalid)
+	}
+	return nil
+}
+
+// sidebar returns the Starlight sidebar: the book's SUMMARY.md, the MkDocs nav if the
+// project has one, otherwise the one from directory metadata files (nil lets Starlight generate it)
+func (s *site) sidebar() []template.SidebarItem {
+	if s.cfg.Book != nil {
+		return s.cfg.Book.Sidebar(s.proc.PageLink)
+	}
+	if s.cfg.MkDocs != nil && len(s.cfg.MkDocs.Nav) > 0 {
+		return s.cfg.MkDocs.Sidebar(s.proc.PageLink)
+	}
+	return s.proc.Sidebar()
+}
+
+// builder returns the builder for the selected renderer
+func (s *site) builder() siteBuilder {
+	if s.native {
+		return renderer.New(s.ws.GetDocsDir(), s.ws.GetDistDir(), renderer.Options{
+			Title:     s.title,
+			Logo:      s.theme.Logo,
+			Favicon:   s.theme.Favicon,
+			CustomCSS: s.theme.CustomCSS,
+			Accent:    s.theme.Accent,
+			Social:    s.cfg.Social,
+			PublicDir: s.ws.GetPublicDir(),
+
+			Categories: s.proc.Categories(),
+		}, logging.Default())
+	}
+	return builder.NewBuilder(s.ws.Path, s.pm, s.log)
+}
+
+// Close stops the server and removes the workspace. The run log is kept.
+func (s *site) Close() {
+	_ = s.cleanupMgr.Cleanup()
+	_ = s.log.Close()
+}
+
+// installLockTimeout bounds how long a run waits for another flashdoc install to finish
+const installLockTimeout = 10 * time.Minute
+
+// installShared extracts the template and installs dependencies into the shared project
+// unless they are already current
+func installShared(sharedMgr *shared.Manager, pm pkgmanager.PackageManager, log *runlog.Log, force, breakLock bool) error {
 	// Get package hash for cache invalidation
 	packageHash, err := template.GetEmbeddedPackageHash()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: failed to get package hash: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("failed to get package hash: %w", err)
 	}
 
-	// Check if shared project is current
-	isCurrent, err := sharedMgr.IsSharedProjectCurrent(packageHash)
+	// Check if shared project is current and installed by the same package manager
+	isCurrent, err := sharedMgr.IsInstalledWith(packageHash, pm.String())
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: failed to check shared project: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("failed to check shared project: %w", err)
+	}
+	if isCurrent && !force {
+		logging.Debugf("dependencies in %s are current", sharedMgr.GetSharedDir())
+		return nil
 	}
 
-	// Install to shared directory if needed
-	if !isCurrent || cfg.ForceReinstall {
-		// Acquire lock to prevent concurrent installs
-		if err := sharedMgr.AcquireLock(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+	if breakLock {
+		logging.Warnf("breaking the install lock")
+		if err := sharedMgr.BreakLock(); err != nil {
+			return err
 		}
-		defer func() { _ = sharedMgr.ReleaseLock() }()
+	}
 
-		// Extract template to shared directory
-		if err := template.ExtractToShared(sharedMgr.GetSharedDir()); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: failed to extract template: %v\n", err)
-			os.Exit(1)
+	// Acquire lock to prevent concurrent installs
+	waited, err := lockInstall(sharedMgr)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = sharedMgr.ReleaseLock() }()
+
+	// The install we waited for has probably done the work already
+	if waited && !force {
+		if isCurrent, err := sharedMgr.IsInstalledWith(packageHash, pm.String()); err == nil && isCurrent {
+			return nil
 		}
+	}
 
-		// Detect package manager
-		pm, err := pkgmanager.Detect()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+	// Another package manager's node_modules layout and lockfile would confuse this one
+	if previous := sharedMgr.InstalledPackageManager(); previous != "" && previous != pm.String() {
+		logging.Infof("♻️  Switching from %s to %s, reinstalling dependencies", previous, pm)
+		if err := sharedMgr.CleanInstall(); err != nil {
+			return err
 		}
+	}
 
-		// Install dependencies to shared directory
-		if err := installer.InstallShared(sharedMgr.GetSharedDir(), pm); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+	// Extract template to shared directory
+	if err := template.ExtractToShared(sharedMgr.GetSharedDir()); err != nil {
+		return fmt.Errorf("failed to extract template: %w", err)
+	}
+
+	// Install dependencies to shared directory
+	if err := installer.InstallShared(sharedMgr.GetSharedDir(), pm, log); err != nil {
+		return err
+	}
+
+	// Save version hash
+	if err := sharedMgr.SaveVersion(packageHash); err != nil {
+		return fmt.Errorf("failed to save version: %w", err)
+	}
+	if err := sharedMgr.SavePackageManager(pm.String()); err != nil {
+		logging.Warnf("%v", err)
+	}
+
+	return nil
+}
+
+// lockInstall takes the install lock, showing a spinner while another install holds it.
+// It reports whether it had to wait.
+func lockInstall(sharedMgr *shared.Manager) (bool, error) {
+	locked, err := sharedMgr.TryLock()
+	if err != nil {
+		return false, err
+	}
+	if locked {
+		return false, nil
+	}
+
+	spin := progress.New("Waiting for another flashdoc install…")
+	spin.Start()
+	if err := sharedMgr.AcquireLock(installLockTimeout); err != nil {
+		spin.StopWithError("Another flashdoc install is still running")
+		if errors.Is(err, shared.ErrLockTimeout) {
+			return true, fmt.Errorf("%w after %s; if no install is running, rerun with --break-lock", err, installLockTimeout)
 		}
+		return true, err
+	}
+	spin.Stop("Other flashdoc install finished")
+	return true, nil
+}
 
-		// Save version hash
-		if err := sharedMgr.SaveVersion(packageHash); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: failed to save version: %v\n", err)
-			os.Exit(1)
+// runBuild builds the site to verify it, without serving or exporting it
+func runBuild(cfg *cli.BuildConfig) error {
+	s, err := prepareSite(&cfg.SiteConfig)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	if err := s.builder().Build(); err != nil {
+		return err
+	}
+
+	logging.Resultf("✅ Build succeeded")
+	return nil
+}
+
+// runExport builds the site and copies it to the output directory
+func runExport(cfg *cli.ExportConfig) error {
+	s, err := prepareSite(&cfg.SiteConfig)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	if err := s.builder().Build(); err != nil {
+		return err
+	}
+
+	exp := exporter.New(s.ws.GetDistDir(), cfg.OutputDir, logging.Default())
+	return exp.Export()
+}
+
+// runServe builds the site and serves it until interrupted
+func runServe(cfg *cli.ServeConfig) error {
+	if cfg.Dev && cfg.Renderer == cli.RendererAuto {
+		// Dev mode needs Astro, so don't silently fall back to the native renderer
+		cfg.Renderer = cli.RendererAstro
+	}
+
+	s, err := prepareSite(&cfg.SiteConfig)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	// Dev mode - Astro serves the workspace directly with hot module replacement
+	if cfg.Dev {
+		return runDevServer(cfg, s)
+	}
+
+	// Build static site
+	bldr := s.builder()
+	if err := bldr.Build(); err != nil {
+		return err
+	}
+
+	// Start the static server
+	srv := staticserver.NewServer(s.ws.GetDistDir(), cfg.Port, logging.Default())
+	if cfg.Watch {
+		srv.EnableLiveReload()
+	}
+
+	if err := srv.Start(); err != nil {
+		return err
+	}
+
+	// Register server for cleanup
+	s.cleanupMgr.RegisterServer(srv)
+
+	// Open browser unless --no-open flag is set
+	openBrowser(srv.GetURL(), cfg.NoOpen)
+
+	// Rebuild on source changes in watch mode
+	if cfg.Watch {
+		w, err := s.watch()
+		if err != nil {
+			return err
 		}
+		defer func() { _ = w.Close() }()
+
+		go s.watchAndRebuild(w, bldr, srv)
+		logging.Infof("👀 Watching %s for changes", cfg.SourceDir)
 	}
 
-	// Generate unique run ID
-	runID := sharedMgr.GenerateRunID()
-	runDir := sharedMgr.GetRunDir(runID)
+	// Wait for signals
+	logging.Infof("\nPress Ctrl+C to exit")
+	s.sigHandler.Wait()
+	return nil
+}
 
-	// Create workspace with symlinks to shared project
-	ws, err := workspace.New(runDir, sharedMgr.GetSharedDir())
+// openBrowser opens url unless noOpen is set
+func openBrowser(url string, noOpen bool) {
+	if noOpen {
+		logging.Infof("(browser not opened due to --no-open flag)")
+		return
+	}
+	if err := browser.Open(url); err != nil {
+		logging.Warnf("failed to open browser: %v", err)
+		logging.Resultf("Please open %s manually", url)
+	}
+}
+
+// runCheck checks links in the source directory and returns the process exit code
+func runCheck(cfg *cli.CheckConfig) int {
+	matcher, err := ignore.Load(cfg.SourceDir, ignore.Options{Exclude: cfg.Exclude, Include: cfg.Include})
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: failed to create workspace: %v\n", err)
-		os.Exit(1)
+		logging.Errorf("%v", err)
+		return 1
 	}
+	c := checker.New(cfg.SourceDir)
+	c.SetIgnore(matcher)
+	c.SetTitles(cfg.Titles)
+	c.SetIncludes(cfg.Includes)
+	c.SetAdmonitions(cfg.Admonitions)
 
-	// Setup workspace structure (creates symlinks)
-	if err := ws.Setup(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: failed to setup workspace: %v\n", err)
-		_ = ws.Cleanup()
-		os.Exit(1)
+	report, err := c.Run()
+	if err != nil {
+		logging.Errorf("%v", err)
+		return 1
 	}
 
-	// Setup cleanup manager
-	cleanupMgr := cleanup.New(ws)
-	defer func() { _ = cleanupMgr.Cleanup() }()
+	out := os.Stdout
+	if cfg.Output != "" {
+		f, err := os.Create(cfg.Output)
+		if err != nil {
+			logging.Errorf("failed to create report: %v", err)
+			return 1
+		}
+		defer f.Close()
+		out = f
+	}
 
-	// Setup signal handling
-	sigHandler := signal.New(cleanupMgr.Cleanup)
-	sigHandler.Setup()
+	if err := checker.Write(out, report, cfg.Format); err != nil {
+		logging.Errorf("failed to write report: %v", err)
+		return 1
+	}
 
-	// Log workspace path
-	fmt.Printf("📦 Workspace: %s\n", ws.Path)
+	if !report.OK() {
+		return 1
+	}
+	return 0
+}
 
-	// Extract config files only (not package.json, which is symlinked)
-	if err := template.ExtractConfigOnly(ws.Path); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: failed to extract config: %v\n", err)
-		os.Exit(1)
+// runCache runs a cache subcommand and returns the process exit code
+func runCache(cfg *cli.CacheConfig) int {
+	sharedMgr, err := shared.NewManager()
+	if err != nil {
+		logging.Errorf("failed to create shared manager: %v", err)
+		return 1
 	}
 
-	// Generate config with title
-	siteTitle := cfg.Title
-	if siteTitle == "" {
-		siteTitle = template.GenerateTitle(cfg.SourceDir)
+	switch cfg.Action {
+	case cli.CachePath:
+		dir := sharedMgr.GetStardocDir()
+		logging.Result(dir, map[string]interface{}{"path": dir})
+		return 0
+	case cli.CacheInfo:
+		err = printCacheInfo(sharedMgr)
+	case cli.CachePrune:
+		var removed []shared.RunInfo
+		removed, err = sharedMgr.PruneRuns(cfg.OlderThan)
+		logging.Resultf("🧹 Removed %d run(s) older than %s, freed %s", len(removed), cfg.OlderThan, shared.FormatSize(runsSize(removed)))
+	case cli.CacheClear:
+		var result *shared.ClearResult
+		result, err = sharedMgr.Clear()
+		if result != nil {
+			logging.Resultf("🧹 Removed %d run(s), freed %s", len(result.Removed), shared.FormatSize(runsSize(result.Removed)+result.SharedFreed))
+			if result.SharedKeptBy != "" {
+				logging.Resultf("Kept the shared install: %s", result.SharedKeptBy)
+			}
+		}
 	}
 
-	if err := template.GenerateConfig(ws.Path, siteTitle); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: failed to generate config: %v\n", err)
-		os.Exit(1)
+	if err != nil {
+		logging.Errorf("%v", err)
+		return 1
 	}
+	return 0
+}
 
-	// Process markdown files
-	targetDir := ws.GetDocsDir()
-	proc := processor.New(cfg.SourceDir, targetDir)
+// printCacheInfo logs the shared install and run directory summary
+func printCacheInfo(sharedMgr *shared.Manager) error {
+	info, err := sharedMgr.Info()
+	if err != nil {
+		return err
+	}
 
-	if err := proc.Process(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+	status := "not installed"
+	install := status
+	if info.Installed {
+		note := "outdated, reinstalled on next run"
+		status = "outdated"
+		if hash, err := template.GetEmbeddedPackageHash(); err == nil && hash == info.TemplateHash {
+			status, note = "current", "current"
+		}
+		pm := info.PackageManager
+		if pm == "" {
+			pm = "unknown package manager"
+		}
+		install = fmt.Sprintf("template %.12s, %s, %s", info.TemplateHash, pm, note)
+	}
+
+	inUse := 0
+	for _, run := range info.Runs {
+		if run.InUse {
+			inUse++
+		}
 	}
 
-	// Detect package manager for build command
-	pm, err := pkgmanager.Detect()
+	lastUsed := "never"
+	var lastUsedAt interface{}
+	if !info.LastUsed.IsZero() {
+		lastUsed = fmt.Sprintf("%s (%s ago)", info.LastUsed.Format("2006-01-02 15:04"), time.Since(info.LastUsed).Round(time.Minute))
+		lastUsedAt = info.LastUsed.Format(time.RFC3339)
+	}
+
+	text := fmt.Sprintf("📁 Cache: %s\n", info.Dir) +
+		fmt.Sprintf("   Shared install: %s (%s)\n", shared.FormatSize(info.SharedSize), install) +
+		fmt.Sprintf("   Runs:           %d, %s (%d in use)\n", len(info.Runs), shared.FormatSize(info.RunsSize()), inUse) +
+		fmt.Sprintf("   Last used:      %s", lastUsed)
+	logging.Result(text, map[string]interface{}{
+		"path":            info.Dir,
+		"shared_size":     info.SharedSize,
+		"install":         status,
+		"template_hash":   info.TemplateHash,
+		"package_manager": info.PackageManager,
+		"runs":            len(info.Runs),
+		"runs_size":       info.RunsSize(),
+		"runs_in_use":     inUse,
+		"last_used":       lastUsedAt,
+	})
+	return nil
+}
+
+// runsSize returns the total size of runs
+func runsSize(runs []shared.RunInfo) int64 {
+	var total int64
+	for _, run := range runs {
+		total += run.Size
+	}
+	return total
+}
+
+// runDoctor checks the environment and returns the process exit code
+func runDoctor(cfg *cli.DoctorConfig) int {
+	sharedMgr, err := shared.NewManager()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		logging.Errorf("failed to create shared manager: %v", err)
+		return 1
 	}
 
-	// Build static site
-	bldr := builder.NewBuilder(ws.Path, pm.String(), os.Stdout)
-	if err := bldr.Build(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+	results := doctor.New(sharedMgr, cfg.SourceDir).Run()
+	doctor.Write(os.Stdout, results)
+
+	if !doctor.Healthy(results) {
+		return 1
+	}
+	return 0
+}
+
+// runDevServer starts astro dev in the workspace and keeps the docs directory in sync with the source
+func runDevServer(cfg *cli.ServeConfig, s *site) error {
+	srv := server.New(s.ws.Path, s.pm, cfg.Port)
+	if err := srv.Start(); err != nil {
+		return err
 	}
 
-	// Check if export mode is enabled
-	if cfg.ExportPath != "" {
-		// Export mode - copy built files and exit
-		distPath := ws.GetDistDir()
-		exp := exporter.New(distPath, cfg.ExportPath, os.Stdout)
+	// Register server for cleanup so the child process is killed on exit
+	s.cleanupMgr.RegisterServer(srv)
+
+	// Astro picks another port if the requested one is taken, so use the URL it reports
+	serverURL, err := srv.WaitReady(30 * time.Second)
+	if err != nil {
+		return err
+	}
 
-		if err := exp.Export(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	// Exit if the dev server dies underneath us
+	go func() {
+		<-srv.Done()
+		if srv.Crashed() {
+			logging.Errorf("dev server crashed unexpectedly: %s", srv.CrashReason())
+			s.Close()
 			os.Exit(1)
 		}
+	}()
 
-		// Exit successfully after export
-		os.Exit(0)
+	// Sync source edits into the workspace; Astro's HMR picks them up from there
+	w, err := s.watch()
+	if err != nil {
+		return err
 	}
+	defer func() { _ = w.Close() }()
+
+	go func() {
+		for {
+			select {
+			case changes, ok := <-w.Changes():
+				if !ok {
+					return
+				}
+				s.syncChanges(changes)
+			case err := <-w.Errors():
+				logging.Warnf("file watcher: %v", err)
+			}
+		}
+	}()
+	logging.Infof("👀 Watching %s for changes", cfg.SourceDir)
 
-	// Normal mode - start dev server
-	distPath := ws.GetDistDir()
-	srv := staticserver.NewServer(distPath, cfg.Port, os.Stdout)
+	openBrowser(serverURL, cfg.NoOpen)
 
-	if err := srv.Start(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+	logging.Infof("\nPress Ctrl+C to exit")
+	s.sigHandler.Wait()
+	return nil
+}
+
+// setupLogging configures the default logger from the output flags. An invalid log format
+// falls back to text so the error about it can be shown.
+func setupLogging(cfg *cli.LogConfig) {
+	opts := logging.Options{Level: cfg.Level(), Format: cfg.Format, Timestamps: cfg.Timestamps}
+	if !logging.ValidFormat(opts.Format) {
+		opts.Format = logging.FormatText
 	}
+	logging.SetDefault(logging.New(opts))
+}
 
-	// Register server for cleanup
-	cleanupMgr.RegisterServer(srv)
+// watch starts watching the source directory, and a book's SUMMARY.md and book.toml
+func (s *site) watch() (*watcher.Watcher, error) {
+	w, err := watcher.New(s.cfg.SourceDir)
+	if err != nil {
+		return nil, err
+	}
+	w.SetIgnore(s.ignore)
+	for _, path := range s.bookFiles() {
+		if err := w.WatchFile(path); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Start(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
 
-	// Open browser unless --no-open flag is set
-	serverURL := srv.GetURL()
-	if !cfg.NoOpen {
-		if err := browser.Open(serverURL); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to open browser: %v\n", err)
-			fmt.Printf("Please open %s manually\n", serverURL)
+// bookFiles returns the paths of the book's SUMMARY.md and book.toml, which aren't pages
+func (s *site) bookFiles() []string {
+	if s.cfg.Book == nil {
+		return nil
+	}
+	files := []string{s.cfg.Book.Summary}
+	if s.cfg.Book.Path != "" {
+		files = append(files, s.cfg.Book.Path)
+	}
+	return files
+}
+
+// isBookFile reports whether a changed source-relative path is SUMMARY.md or book.toml
+func (s *site) isBookFile(path string) bool {
+	abs, err := filepath.Abs(filepath.Join(s.cfg.SourceDir, path))
+	if err != nil {
+		return false
+	}
+	for _, file := range s.bookFiles() {
+		if file == abs {
+			return true
 		}
-	} else {
-		fmt.Println("(browser not opened due to --no-open flag)")
 	}
+	return false
+}
 
-	// Wait for signals
-	fmt.Println("\nPress Ctrl+C to exit")
-	sigHandler.Wait()
+// reloadBook re-reads the book after SUMMARY.md or book.toml changed, so the sidebar
+// follows it. When the page order came from the summary, the pages are re-processed in the
+// new order. The title and the pages --summary-only publishes are kept until a restart.
+func (s *site) reloadBook() error {
+	pages := s.cfg.Book.Pages()
+	if err := s.cfg.Book.Reload(); err != nil {
+		return err
+	}
+	logging.Infof("📖 Reloaded %s", s.cfg.Book.Summary)
+
+	if !reflect.DeepEqual(s.cfg.SidebarOrder, pages) || reflect.DeepEqual(pages, s.cfg.Book.Pages()) {
+		return nil
+	}
+	s.cfg.SidebarOrder = s.cfg.Book.Pages()
+	s.proc.SetSidebarOrder(s.cfg.SidebarOrder)
+	return s.proc.Sync(".")
+}
+
+// syncChanges re-processes changed source paths into the workspace, reporting whether all
+// succeeded. The Astro config is rewritten when the changes moved pages in the sidebar.
+func (s *site) syncChanges(changes []string) bool {
+	ok := true
+	for _, path := range changes {
+		var err error
+		if s.isBookFile(path) {
+			err = s.reloadBook()
+		} else {
+			err = s.proc.Sync(path)
+		}
+		if err != nil {
+			logging.Errorf("%v", err)
+			ok = false
+		}
+	}
+
+	if s.starlight != nil {
+		if sidebar := s.sidebar(); !reflect.DeepEqual(sidebar, s.starlight.Sidebar) {
+			s.starlight.Sidebar = sidebar
+			if err := s.rewriteConfig(); err != nil {
+				logging.Errorf("%v", err)
+				ok = false
+			}
+		}
+	}
+	return ok
+}
+
+// rewriteConfig writes the Starlight options into a fresh astro.config.mjs, since writing
+// them replaces the marker line of the one in the workspace
+func (s *site) rewriteConfig() error {
+	if err := template.ExtractConfigOnly(s.ws.Path); err != nil {
+		return fmt.Errorf("failed to extract config: %w", err)
+	}
+	return template.WriteConfig(s.ws.Path, s.starlight)
+}
+
+// watchAndRebuild re-processes changed files, rebuilds the site and reloads open browser tabs
+func (s *site) watchAndRebuild(w *watcher.Watcher, bldr siteBuilder, srv *staticserver.Server) {
+	for {
+		select {
+		case changes, ok := <-w.Changes():
+			if !ok {
+				return
+			}
+
+			logging.Infof("🔄 %d file(s) changed, rebuilding...", len(changes))
+			if !s.syncChanges(changes) {
+				continue
+			}
+
+			// Report build failures but keep watching so the next save can fix them
+			if err := bldr.Build(); err != nil {
+				logging.Errorf("%v", err)
+				continue
+			}
+			srv.Reload()
+
+		case err := <-w.Errors():
+			logging.Warnf("file watcher: %v", err)
+		}
+	}
 }
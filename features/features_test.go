@@ -39,6 +39,29 @@ func InitializeScenario(sc *godog.ScenarioContext) {
 	steps.RegisterBrowserSteps(sc, testCtx)
 	steps.RegisterServerSteps(sc, testCtx)
 	steps.RegisterExportSteps(sc, testCtx)
+
+	// Phase 5: Advanced
+	steps.RegisterWatchSteps(sc, testCtx)
+	steps.RegisterDevSteps(sc, testCtx)
+	steps.RegisterConfigFileSteps(sc, testCtx)
+	steps.RegisterAssetsSteps(sc, testCtx)
+	steps.RegisterCheckSteps(sc, testCtx)
+	steps.RegisterRendererSteps(sc, testCtx)
+	steps.RegisterCommandSteps(sc, testCtx)
+	steps.RegisterCacheSteps(sc, testCtx)
+	steps.RegisterLockSteps(sc, testCtx)
+	steps.RegisterIgnoreSteps(sc, testCtx)
+	steps.RegisterMDXSteps(sc, testCtx)
+	steps.RegisterTitleSteps(sc, testCtx)
+	steps.RegisterSchemaSteps(sc, testCtx)
+	steps.RegisterRunLogSteps(sc, testCtx)
+	steps.RegisterLoggingSteps(sc, testCtx)
+	steps.RegisterPkgManagerSelectionSteps(sc, testCtx)
+	steps.RegisterStarlightConfigSteps(sc, testCtx)
+	steps.RegisterThemeSteps(sc, testCtx)
+	steps.RegisterSidebarMetadataSteps(sc, testCtx)
+	steps.RegisterMkDocsSteps(sc, testCtx)
+	steps.RegisterMdBookSteps(sc, testCtx)
 }
 
 // runPhase is a helper function to run tests for a specific phase
@@ -78,6 +101,11 @@ func TestPhase4(t *testing.T) {
 	runPhase(t, "phase4-server")
 }
 
+// TestPhase5 runs Phase 5: Advanced tests
+func TestPhase5(t *testing.T) {
+	runPhase(t, "phase5-advanced")
+}
+
 // TestFeatures runs all phases together
 func TestFeatures(t *testing.T) {
 	suite := godog.TestSuite{
@@ -89,6 +117,7 @@ func TestFeatures(t *testing.T) {
 				"phase2-markdown",
 				"phase3-starlight",
 				"phase4-server",
+				"phase5-advanced",
 			},
 			TestingT: t,
 			Strict:   false, // Set to false to allow pending steps
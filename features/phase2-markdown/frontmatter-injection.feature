@@ -18,10 +18,9 @@ Feature: Frontmatter Injection
     Then the output should have frontmatter:
       """
       ---
-      title: Guide
+      title: My Guide
+      description: This is the content.
       ---
-      # My Guide
-
       This is the content.
       """
 
@@ -55,7 +54,7 @@ Feature: Frontmatter Injection
     Then the frontmatter should include:
       """
       ---
-      title: Guide
+      title: Content
       description: A helpful guide
       ---
       """
@@ -129,6 +128,7 @@ Feature: Frontmatter Injection
       """
       ---
       title: Broken
+      description: Content
       ---
       """
     And the original malformed content should be preserved as body content
@@ -144,7 +144,7 @@ Feature: Frontmatter Injection
     Then the frontmatter should be populated with:
       """
       ---
-      title: Empty
+      title: Content
       ---
       """
 
@@ -32,8 +32,8 @@ Feature: Template Extraction
     When the template is extracted
     And I read the "astro.config.mjs" file
     Then it should import "starlight" integration
-    And it should have a placeholder for site title
-    And the placeholder should be "{{SITE_TITLE}}"
+    And it should have a placeholder for the Starlight options
+    And the placeholder should be "{{STARLIGHT_OPTIONS}}"
 
   Scenario: Template directory structure matches Starlight conventions
     When the template is extracted
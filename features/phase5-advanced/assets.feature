@@ -0,0 +1,117 @@
+Feature: Static Assets
+  As a flashdoc user
+  I want images and downloads referenced by my docs to be carried over
+  So that the built site has no broken images or links
+
+  Background:
+    Given the stardoc CLI is available
+    And a source directory "./docs" exists
+    And a temp workspace exists at "/tmp/stardoc-assets"
+
+  Scenario: Markdown images are copied next to the page
+    Given a source asset "guides/img/arch.png"
+    And the source file "guides/setup.md" is changed to:
+      """
+      # Setup
+
+      ![Architecture](./img/arch.png)
+      """
+    When files are processed with a public directory
+    Then the asset "guides/img/arch.png" should be copied next to the pages
+    And the target file "guides/setup.md" should contain "![Architecture](./img/arch.png)"
+
+  Scenario: Downloads are served from public and linked by absolute path
+    Given a source asset "files/guide v2.pdf"
+    And the source file "guides/setup.md" is changed to:
+      """
+      # Setup
+
+      Grab the [PDF](../files/guide%20v2.pdf#page=2).
+      """
+    When files are processed with a public directory
+    Then the asset "files/guide v2.pdf" should be copied to the public directory
+    And the target file "guides/setup.md" should contain "[PDF](/files/guide%20v2.pdf#page=2)"
+
+  Scenario: HTML images and reference definitions are handled
+    Given a source asset "img/logo.svg"
+    And a source asset "img/diagram.png"
+    And the source file "index.md" is changed to:
+      """
+      # Home
+
+      <img src='img/logo.svg' alt='Logo' width='120'>
+
+      ![Diagram][diagram]
+
+      [diagram]: ./img/diagram.png 'The diagram'
+      """
+    When files are processed with a public directory
+    Then the asset "img/logo.svg" should be copied to the public directory
+    And the asset "img/diagram.png" should be copied to the public directory
+    And the target file "index.md" should contain "<img src='/img/logo.svg'"
+    And the target file "index.md" should contain "[diagram]: /img/diagram.png 'The diagram'"
+
+  Scenario: Images nested in links are found
+    Given a source asset "badge.svg"
+    And the source file "index.md" is changed to:
+      """
+      # Home
+
+      [![Build](badge.svg)](https://ci.example.com)
+      """
+    When files are processed with a public directory
+    Then the asset "badge.svg" should be copied next to the pages
+    And the target file "index.md" should contain "[![Build](badge.svg)](https://ci.example.com)"
+
+  Scenario: References inside code are left alone
+    Given the source file "index.md" is changed to:
+      """
+      # Home
+
+      Use `![alt](missing.png)` to embed an image:
+
+      ```markdown
+      ![Screenshot](./screenshot.png)
+      ```
+      """
+    When files are processed with a public directory
+    Then no missing assets should be reported
+
+  Scenario: Missing files are reported with page and line
+    Given the source file "guides/setup.md" is changed to:
+      """
+      # Setup
+
+      ![Missing](./img/nope.png)
+
+      See the [spec](../spec.pdf).
+      """
+    When files are processed with a public directory
+    Then a missing asset "./img/nope.png" should be reported at "guides/setup.md:3"
+    And a missing asset "../spec.pdf" should be reported at "guides/setup.md:5"
+    And the target file "guides/setup.md" should contain "![Missing](/guides/img/nope.png)"
+
+  Scenario: External links and pages are not treated as assets
+    Given the source file "guides/setup.md" is changed to:
+      """
+      # Setup
+      """
+    And the source file "index.md" is changed to:
+      """
+      # Home
+
+      [Setup](guides/setup.md), [site](https://example.com/logo.png), [mail](mailto:docs@example.com), [top](#home)
+      """
+    When files are processed with a public directory
+    Then no missing assets should be reported
+
+  Scenario: Changed assets are re-copied in watch mode
+    Given a source asset "img/arch.png"
+    And the source file "index.md" is changed to:
+      """
+      ![Architecture](img/arch.png)
+      """
+    And files are processed with a public directory
+    When the source asset "img/arch.png" is replaced with "new image data"
+    And the change to "img/arch.png" is synced by the same processor
+    Then the target file "img/arch.png" should contain "new image data"
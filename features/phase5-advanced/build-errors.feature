@@ -0,0 +1,113 @@
+Feature: Build Error Reporting
+  As a flashdoc user
+  I want failed installs and builds to show why they failed
+  So that I can fix my docs without rerunning Astro by hand
+
+  Background:
+    Given the stardoc CLI is available
+    And a source directory "./docs" exists
+    And a temp workspace exists at "/tmp/stardoc-build-errors"
+
+  Scenario: The failure shows the relevant lines and the log file
+    Given "npm run build" wrote to the build log:
+      """
+      \e[32m12:00:00\e[39m [content] Syncing content
+      12:00:01 [types] Generated 120ms
+      12:00:02 [build] output: "static"
+      12:00:03 [ERROR] [InvalidContentEntryFrontmatterError] docs → guide.md frontmatter does not match collection schema.
+      title: Required
+        Hint:
+          Make sure the frontmatter matches the schema.
+      """
+    When the build fails
+    Then the build error should contain "build failed: exit status 1"
+    And the build error should contain "[ERROR] [InvalidContentEntryFrontmatterError]"
+    And the build error should contain "title: Required"
+    And the build error should contain "Full log: "
+    And the build error should not contain "Syncing content"
+    And the build log file should contain "==> npm run build"
+    And the build log file should contain "[types] Generated 120ms"
+
+  Scenario: Color codes are stripped from the excerpt
+    Given "npm run build" wrote to the build log:
+      """
+      \e[31m[ERROR]\e[39m Could not resolve module
+      """
+    When the build fails
+    Then the build error should contain "[ERROR] Could not resolve module"
+
+  Scenario: Without an error line the last lines are shown
+    Given "npm install" wrote to the build log:
+      """
+      added 1 package
+      Killed
+      """
+    When the build fails
+    Then the build error should contain "added 1 package"
+    And the build error should contain "Killed"
+
+  Scenario: Only the failed step is excerpted
+    Given "npm install" wrote to the build log:
+      """
+      npm ERR! peer dependency warning
+      """
+    And "npm run build" wrote to the build log:
+      """
+      [ERROR] Expected a closing tag
+      """
+    When the build fails
+    Then the build error should contain "Expected a closing tag"
+    And the build error should not contain "peer dependency warning"
+    And the build log file should contain "peer dependency warning"
+
+  Scenario: Workspace paths point at the source file and line
+    Given the source file "guide/01-setup.md" is changed to:
+      """
+      # Setup
+
+      Install the tools.
+
+      <Broken
+      """
+    When files are processed with a public directory
+    And the astro build fails on "<Broken" in "guide/setup.md" with "Unexpected end of file in expression"
+    Then the build error should point at "guide/01-setup.md:5:1"
+    And the build error should not contain "src/content/docs"
+
+  Scenario: Lines after existing frontmatter are mapped
+    Given the source file "README.md" is changed to:
+      """
+      ---
+      title: Home
+      ---
+
+      Welcome.
+
+      {broken
+      """
+    When files are processed with a public directory
+    And the astro build fails on "{broken" in "index.md" with "Could not parse expression with acorn"
+    Then the build error should point at "README.md:7:1"
+
+  Scenario: Content collection entries point at the source file
+    Given the source file "02-faq.md" is changed to:
+      """
+      # FAQ
+      """
+    When files are processed with a public directory
+    And "npm run build" wrote to the build log:
+      """
+      [ERROR] [InvalidContentEntryFrontmatterError] docs → faq.md frontmatter does not match collection schema.
+      """
+    And the build fails
+    Then the build error should contain "docs → "
+    And the build error should point at "02-faq.md"
+
+  Scenario: Unknown pages are left alone
+    Given "npm run build" wrote to the build log:
+      """
+      [ERROR] Failed to parse src/content/docs/missing.md:3:1
+      """
+    When the build fails
+    Then the build error should contain "src/content/docs/missing.md:3:1"
+
@@ -0,0 +1,64 @@
+Feature: Cache Management
+  As a flashdoc user
+  I want to see and manage the shared install and run directories under ~/.stardoc
+  So that I can reclaim disk space without breaking a running flashdoc
+
+  Background:
+    Given the stardoc CLI is available
+    And a temp workspace exists at "/tmp/stardoc-cache"
+
+  Scenario: Cache info shows the install and runs
+    Given the shared install was done with "pnpm"
+    And the cache has a run "old" last used 48 hours ago
+    And the cache has a run "serving" last used 1 hour ago
+    And the run "serving" is in use
+    When the cache info is collected
+    Then the cache info should show 2 runs with 1 in use
+    And the cache info should show a current install by "pnpm"
+
+  Scenario: Prune removes old runs only
+    Given the cache has a run "old" last used 48 hours ago
+    And the cache has a run "recent" last used 2 hours ago
+    When runs older than 24 hours are pruned
+    Then the run "old" should be removed
+    And the run "recent" should still exist
+
+  Scenario: Runs in use are never pruned
+    Given the cache has a run "long-session" last used 72 hours ago
+    And the run "long-session" is in use
+    And the cache has a run "crashed" last used 72 hours ago
+    And the run "crashed" belonged to a process that has exited
+    When runs older than 24 hours are pruned
+    Then the run "long-session" should still exist
+    And the run "crashed" should be removed
+
+  Scenario: Clear removes idle runs and the shared install
+    Given the shared install was done with "npm"
+    And the cache has a run "recent" last used 1 hour ago
+    When the cache is cleared
+    Then the run "recent" should be removed
+    And the shared install should be removed
+
+  Scenario: Clear keeps the shared install while a run uses it
+    Given the shared install was done with "npm"
+    And the cache has a run "idle" last used 1 hour ago
+    And the cache has a run "serving" last used 1 hour ago
+    And the run "serving" is in use
+    When the cache is cleared
+    Then the run "idle" should be removed
+    And the run "serving" should still exist
+    And the shared install should still exist
+
+  Scenario: Clear keeps the shared install during an install
+    Given the shared install was done with "npm"
+    And an install holds the lock
+    When the cache is cleared
+    Then the shared install should still exist
+
+  Scenario: Cache commands are parsed from the command line
+    When stardoc is run with arguments "cache prune --older-than 2h"
+    Then the parsed cache command should be "prune" older than "2h"
+    When stardoc is run with arguments "cache prune"
+    Then the parsed cache command should be "prune" older than "24h"
+    And running stardoc with arguments "cache prune --older-than -1h" should fail with "--older-than must be positive"
+    And running stardoc with arguments "cache info extra" should fail with "unknown command"
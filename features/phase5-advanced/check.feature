@@ -0,0 +1,132 @@
+Feature: Link Checker
+  As a docs maintainer
+  I want to check links, images and anchors without building the site
+  So that broken references can fail CI before they are merged
+
+  Background:
+    Given the stardoc CLI is available
+    And a source directory "./docs" exists
+    And the source file "README.md" is changed to:
+      """
+      # Overview
+
+      ## Getting `started`
+
+      ## Getting `started`
+      """
+    And the source file "guides/setup.md" is changed to:
+      """
+      ---
+      title: Setup
+      ---
+
+      Install Node
+      ------------
+
+      See the [overview](../README.md#getting-started) and [again](../README.md#getting-started-1).
+      """
+
+  Scenario: Valid links pass
+    When the docs are checked
+    Then the check should pass
+
+  Scenario: Broken links, images and anchors are reported with file and line
+    Given the source file "guides/broken.md" is changed to:
+      """
+      # Broken
+
+      [Missing page](missing.md)
+      ![Diagram](img/diagram.png)
+      [Bad anchor](setup.md#uninstall)
+      [Local anchor](#nope)
+      [Outside](../../secrets.md)
+      """
+    When the docs are checked
+    Then the check should report "guides/broken.md:3" as "broken-link"
+    And the check should report "guides/broken.md:4" as "missing-image"
+    And the check should report "guides/broken.md:5" as "broken-anchor"
+    And the check should report "guides/broken.md:6" as "broken-anchor"
+    And the check should report "guides/broken.md:7" as "outside-source"
+
+  Scenario: Heading anchors, explicit ids and directory links resolve
+    Given the source file "guides/anchors.md" is changed to:
+      """
+      # Anchors
+
+      <a id="custom-spot"></a>
+
+      [Setup heading](setup.md#install-node), [custom](#custom-spot), [top](#_top), [guides](../guides/), [home](../)
+      """
+    And the source file "guides/README.md" is changed to:
+      """
+      # Guides
+      """
+    When the docs are checked
+    Then the check should pass
+
+  Scenario: The H1 that becomes the page title has no anchor
+    Given the source file "guides/top.md" is changed to:
+      """
+      # Top
+
+      Back to the [overview](../README.md#overview).
+      """
+    When the docs are checked
+    Then the check should report "guides/top.md:3" as "broken-anchor"
+
+  Scenario: Links from the site root are checked against the docs root
+    Given the source file "guides/rooted.md" is changed to:
+      """
+      # Rooted
+
+      [Setup](/guides/setup/#install-node), [file](/guides/setup.md), [home](/)
+      [Missing](/guides/uninstall/)
+      [Bad anchor](/guides/setup/#uninstall)
+      ![Logo](/img/logo.png)
+      """
+    When the docs are checked
+    Then the check should report "guides/rooted.md:4" as "broken-link"
+    And the check should report "guides/rooted.md:5" as "broken-anchor"
+    And the check should report "guides/rooted.md:6" as "missing-image"
+    And the check should report 3 problems
+
+  Scenario: Links inside code blocks are ignored
+    Given the source file "guides/code.md" is changed to:
+      """
+      # Code
+
+      ```md
+      [Not a link](missing.md)
+      ```
+      """
+    When the docs are checked
+    Then the check should pass
+
+  Scenario: JSON report
+    Given the source file "guides/broken.md" is changed to:
+      """
+      [Missing page](missing.md)
+      """
+    When the docs are checked
+    And the check report is written as "json"
+    Then the check output should contain ""file": "guides/broken.md""
+    And the check output should contain ""kind": "broken-link""
+
+  Scenario: JUnit report
+    Given the source file "guides/broken.md" is changed to:
+      """
+      [Missing page](missing.md)
+      """
+    When the docs are checked
+    And the check report is written as "junit"
+    Then the check output should contain "<testsuite name="flashdoc check" tests="3" failures="1">"
+    And the check output should contain "<failure message="broken link: missing.md" type="broken-link">"
+
+  Scenario: Human report summary
+    When the docs are checked
+    And the check report is written as "human"
+    Then the check output should contain "no broken references"
+
+  Scenario: The check command is parsed from the command line
+    When stardoc is run with check arguments "--format junit -o report.xml"
+    Then the parsed command should be "check" with format "junit"
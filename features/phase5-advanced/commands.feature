@@ -0,0 +1,65 @@
+Feature: Subcommands
+  As a flashdoc user
+  I want one subcommand per task
+  So that serving, building, exporting and maintenance each have their own flags
+
+  Background:
+    Given the stardoc CLI is available
+    And a source directory "./docs" exists
+
+  Scenario: A bare directory is shorthand for serve
+    When stardoc is run on the source directory with "--port 5000 --no-open"
+    Then the parsed command should be "serve"
+    And the parsed source directory should be the source directory
+    And the resolved port should be 5000
+
+  Scenario: Serve subcommand
+    When stardoc "serve" is run on the source directory with "--title Handbook --watch"
+    Then the parsed command should be "serve"
+    And the resolved title should be "Handbook"
+
+  Scenario: Flag values before the directory are not mistaken for it
+    When stardoc "serve" is run on the source directory with "--renderer native"
+    Then the parsed source directory should be the source directory
+    And the resolved renderer should be "native"
+
+  Scenario: Export to an explicit directory
+    When stardoc "export" is run on the source directory with "./public-site --title Docs"
+    Then the parsed command should be "export"
+    And the resolved export path should end with "public-site"
+    And the resolved title should be "Docs"
+
+  Scenario: Export defaults to ./export-doc
+    When stardoc "export" is run on the source directory with ""
+    Then the resolved export path should end with "export-doc"
+
+  Scenario: Build without serving
+    When stardoc "build" is run on the source directory with "--renderer native"
+    Then the parsed command should be "build"
+    And the parsed source directory should be the source directory
+
+  Scenario: Serving flags belong to serve only
+    Then stardoc with "--export ./site" should fail with "unknown flag: --export"
+    And stardoc with "--port 80" should fail with "port must be between 1024 and 65535"
+
+  Scenario: Cache and doctor commands
+    When stardoc is run with arguments "cache path"
+    Then the parsed command should be "cache"
+    When stardoc is run with arguments "doctor"
+    Then the parsed command should be "doctor"
+
+  Scenario: Help shows usage without running a command
+    When stardoc is run with arguments "--help"
+    Then no command should be parsed
+
+  Scenario: Doctor validates the cache directory and project config
+    Given a temp workspace exists at "/tmp/stardoc-doctor"
+    And a project config file ".flashdoc.yaml" with:
+      """
+      prot: 5000
+      """
+    When the doctor is run on the source directory
+    Then the doctor should report "Cache directory" as "ok"
+    And the doctor should report "Starlight install" as "warning"
+    And the doctor should report "Project config" as "failed"
+    And the doctor should find problems
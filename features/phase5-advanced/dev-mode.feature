@@ -0,0 +1,53 @@
+Feature: Dev Mode
+  As a flashdoc user
+  I want to preview my docs through the Astro dev server
+  So that edits show up instantly through hot module replacement
+
+  Background:
+    Given the stardoc CLI is available
+
+  Scenario: Detect the ready URL from Astro output
+    Given the dev server starts and logs:
+      """
+       astro  v5.1.0 ready in 412 ms
+
+      ┃ Local    http://localhost:4321/
+      ┃ Network  use --host to expose
+      """
+    When stardoc parses the output
+    Then it should extract "http://localhost:4321/" as the server URL
+
+  Scenario: Detect the fallback port when the requested port is taken
+    Given the dev server starts and logs:
+      """
+      [@astrojs/starlight] Port 4321 is in use, trying another one...
+       astro  v5.1.0 ready in 398 ms
+
+      ┃ Local    http://localhost:4322/
+      """
+    When stardoc parses the output
+    Then it should extract "http://localhost:4322/" as the server URL
+    And the requested port 4321 should be reported as in use
+
+  Scenario: Ignore terminal colors in Astro output
+    Given the dev server starts and logs with colors:
+      """
+      ┃ Local    http://localhost:4400/
+      """
+    When stardoc parses the output
+    Then it should extract "http://localhost:4400/" as the server URL
+
+  Scenario: Output without a ready line is not mistaken for readiness
+    Given the dev server starts and logs:
+      """
+      Installing dependencies...
+      Network  use --host to expose
+      """
+    When stardoc parses the output
+    Then no server URL should be detected
+
+  Scenario: Cleanup stops the registered dev server
+    Given a dev server is registered with the cleanup manager
+    When cleanup is performed
+    Then the dev server should be stopped
+    And the workspace should be removed
@@ -0,0 +1,150 @@
+Feature: Frontmatter Round-Trip
+  As a flashdoc user with hand-written frontmatter
+  I want flashdoc to only add the keys it needs
+  So that my comments, key order, quoting and values reach Starlight untouched
+
+  Background:
+    Given the stardoc CLI is available
+    And a source directory "./docs" exists
+    And a temp workspace exists at "/tmp/stardoc-roundtrip"
+    And the source file "index.md" is changed to:
+      """
+      # Home
+      """
+
+  Scenario: Comments, key order and quoting are kept
+    Given the source file "guide.md" is changed to:
+      """
+      ---
+      # Reviewed by the docs team
+      tags: ["setup", 'install']
+      draft: false   # publish with the release
+      ---
+      # Setup
+      """
+    When files are processed with a public directory
+    Then the target file "guide.md" should start with:
+      """
+      ---
+      # Reviewed by the docs team
+      tags: ["setup", 'install']
+      draft: false   # publish with the release
+      title: Setup
+      ---
+      """
+
+  Scenario: Anchors, multi-line strings and dates are kept
+    Given the source file "02-release.md" is changed to:
+      """
+      ---
+      defaults: &defaults
+        badge: New
+      sidebar:
+        <<: *defaults
+      lastUpdated: 2024-01-05
+      notes: |
+        First line
+          indented line
+      summary: >
+        Folded
+        text
+      ---
+      Release notes.
+      """
+    When files are processed with a public directory
+    Then the target file "release.md" should start with:
+      """
+      ---
+      defaults: &defaults
+        badge: New
+      sidebar:
+        order: 2
+        <<: *defaults
+      lastUpdated: 2024-01-05
+      notes: |
+        First line
+          indented line
+      summary: >
+        Folded
+        text
+      title: Release
+      description: Release notes.
+      ---
+      Release notes.
+      """
+
+  Scenario: Blank keys are filled in place
+    Given the source file "03-faq.md" is changed to:
+      """
+      ---
+      title:
+      sidebar:
+      author: Docs Team
+      ---
+      Questions.
+      """
+    When files are processed with a public directory
+    Then the target file "faq.md" should start with:
+      """
+      ---
+      title: Faq
+      sidebar:
+        order: 3
+      author: Docs Team
+      description: Questions.
+      ---
+      """
+
+  Scenario: Null keys are filled in place
+    Given the source file "faq.md" is changed to:
+      """
+      ---
+      title: ~
+      description: null
+      author: Docs Team
+      ---
+      # FAQ
+
+      Questions.
+      """
+    When files are processed with a public directory
+    Then the target file "faq.md" should start with:
+      """
+      ---
+      title: FAQ
+      description: Questions.
+      author: Docs Team
+      ---
+      """
+
+  Scenario: Titles that need quoting are quoted
+    Given the source file "faq.md" is changed to:
+      """
+      # FAQ: what's new
+      """
+    When files are processed with a public directory
+    Then the target file "faq.md" should start with:
+      """
+      ---
+      title: 'FAQ: what''s new'
+      ---
+      """
+
+  Scenario: Complete frontmatter is left byte-identical
+    Given the source file "guide.md" is changed to:
+      """
+      ---
+      title:   "Guide"   # shown in the browser tab
+      description: 'All about it'
+      ---
+      Body.
+      """
+    When files are processed with a public directory
+    Then the target file "guide.md" should start with:
+      """
+      ---
+      title:   "Guide"   # shown in the browser tab
+      description: 'All about it'
+      ---
+      Body.
+      """
@@ -0,0 +1,108 @@
+Feature: Starlight Frontmatter Validation
+  As a flashdoc user
+  I want frontmatter Starlight would reject reported per page and key
+  So that I can fix it instead of reading "Build failed"
+
+  Background:
+    Given the stardoc CLI is available
+    And a source directory "./docs" exists
+    And a temp workspace exists at "/tmp/stardoc-schema"
+    And the source file "index.md" is changed to:
+      """
+      # Home
+      """
+
+  Scenario: Wrongly typed values are reported with a fix
+    Given the source file "guide.md" is changed to:
+      """
+      ---
+      title: 2024
+      sidebar: Getting started
+      template: Splash
+      tableOfContents:
+        maxHeadingLevel: 9
+      ---
+      """
+    When files are processed with a public directory
+    Then invalid frontmatter "title" should be reported for "guide.md" with "must be a string, got number 2024"
+    And invalid frontmatter "sidebar" should be reported for "guide.md" with "to set the sidebar label, write sidebar: { label:"
+    And invalid frontmatter "template" should be reported for "guide.md" with "did you mean"
+    And invalid frontmatter "tableOfContents.maxHeadingLevel" should be reported for "guide.md" with "from 1 to 6"
+
+  Scenario: Nested values are checked
+    Given the source file "guide.md" is changed to:
+      """
+      ---
+      title: Guide
+      sidebar:
+        order: "2"
+        badge:
+          text: New
+          variant: info
+      head:
+        - tag: meta
+          attrs: { name: robots, content: noindex }
+        - tag: body
+      prev:
+        link: /intro/
+        title: Intro
+      ---
+      """
+    When files are processed with a public directory
+    Then invalid frontmatter "sidebar.order" should be reported for "guide.md" with "remove the quotes: order: 2"
+    And invalid frontmatter "sidebar.badge.variant" should be reported for "guide.md" with "must be one of note, danger, success, caution, tip, default"
+    And invalid frontmatter "head[1].tag" should be reported for "guide.md" with "must be one of title, base, link"
+    And invalid frontmatter "prev.title" should be reported for "guide.md" with "only link and label can be set"
+
+  Scenario: Valid Starlight frontmatter passes
+    Given the source file "guide.md" is changed to:
+      """
+      ---
+      title: Guide
+      description: All about it
+      template: splash
+      lastUpdated: 2024-01-05
+      editUrl: https://github.com/example/docs/edit/main/guide.md
+      hero:
+        tagline: Fast docs
+        image:
+          file: ../../assets/hero.png
+        actions:
+          - text: Get started
+            link: /intro/
+            variant: primary
+      sidebar:
+        label: The Guide
+        badge: New
+        hidden: false
+      prev: false
+      next: Next page
+      pagefind: true
+      custom: anything
+      ---
+      """
+    When files are processed with a public directory
+    Then no invalid frontmatter should be reported
+
+  Scenario: Fixing repairs values and drops the rest
+    Given the source file "guide.md" is changed to:
+      """
+      ---
+      title: Guide
+      sidebar: Getting started
+      template: Splash
+      pagefind: "false"
+      draft: maybe
+      ---
+      """
+    When files are processed with frontmatter fixing
+    Then invalid frontmatter "draft" should be reported for "guide.md" with "(use true or false), removed"
+    And invalid frontmatter "pagefind" should be reported for "guide.md" with "repaired"
+    And the target file "guide.md" should contain "label: Getting started"
+    And the target file "guide.md" should contain "template: splash"
+    And the target file "guide.md" should contain "pagefind: false"
+    And the target file "guide.md" should not contain "draft"
+
+  Scenario: Fixing is enabled with a flag
+    When stardoc "build" is run on the source directory with "--fix-frontmatter"
+    Then the parsed site config should fix frontmatter
@@ -0,0 +1,141 @@
+Feature: TOML and JSON Frontmatter
+  As a flashdoc user with Hugo content
+  I want +++ TOML and JSON frontmatter understood
+  So that my pages keep their settings instead of showing them as text
+
+  Background:
+    Given the stardoc CLI is available
+    And a source directory "./docs" exists
+    And a temp workspace exists at "/tmp/stardoc-hugo"
+    And the source file "index.md" is changed to:
+      """
+      # Home
+      """
+
+  Scenario: TOML frontmatter is converted to YAML
+    Given the source file "install.md" is changed to:
+      """
+      +++
+      title = "Installing"
+      weight = 3
+      linkTitle = "Install"
+      draft = true
+      date = 2024-01-05
+      tags = ["setup"]
+      +++
+
+      Run the installer.
+      """
+    When files are processed with a public directory
+    Then the target file "install.md" should start with:
+      """
+      ---
+      title: Installing
+      description: Run the installer.
+      date: 2024-01-05T00:00:00Z
+      draft: true
+      sidebar:
+        label: Install
+        order: 3
+      tags:
+        - setup
+      ---
+
+      Run the installer.
+      """
+    And the target file "install.md" should not contain "weight"
+
+  Scenario: JSON frontmatter is converted to YAML
+    Given the source file "api.md" is changed to:
+      """
+      {
+        "title": "API",
+        "description": "Endpoints",
+        "weight": 20
+      }
+      # Endpoints
+      """
+    When files are processed with a public directory
+    Then the target file "api.md" should start with:
+      """
+      ---
+      title: API
+      description: Endpoints
+      sidebar:
+        order: 20
+      ---
+      # Endpoints
+      """
+
+  Scenario: Hugo keys in YAML frontmatter set the sidebar
+    Given the source file "guide.md" is changed to:
+      """
+      ---
+      title: Guide
+      weight: 5
+      linkTitle: Start here
+      ---
+      """
+    When files are processed with a public directory
+    Then the target file "guide.md" should start with:
+      """
+      ---
+      title: Guide
+      weight: 5
+      linkTitle: Start here
+      sidebar:
+        label: Start here
+        order: 5
+      ---
+      """
+
+  Scenario: Starlight sidebar settings win over Hugo keys
+    Given the source file "guide.md" is changed to:
+      """
+      +++
+      weight = 5
+      [sidebar]
+      order = 1
+      +++
+      """
+    When files are processed with a public directory
+    Then the target file "guide.md" should contain "order: 1"
+    And the target file "guide.md" should not contain "order: 5"
+
+  Scenario: MDX pages with TOML frontmatter keep their imports below it
+    Given the source file "cards.mdx" is changed to:
+      """
+      +++
+      title = "Cards"
+      +++
+      import { Card } from '@astrojs/starlight/components';
+
+      <Card title="Fast">Builds in seconds.</Card>
+      """
+    When files are processed with a public directory
+    Then the target file "cards.mdx" should contain in order:
+      """
+      ---
+      title: Cards
+      ---
+      import { Card } from '@astrojs/starlight/components';
+      """
+
+  Scenario: The native renderer shows converted pages without their frontmatter
+    Given the source file "install.md" is changed to:
+      """
+      +++
+      title = "Installing"
+      +++
+      Run the installer.
+      """
+    When files are processed with a public directory
+    And the processed docs are rendered natively
+    Then the rendered page "install/index.html" should contain:
+      """
+      <h1 id="_top">Installing</h1>
+      """
+    And the rendered page "install/index.html" should not contain:
+      """
+      +++
+      """
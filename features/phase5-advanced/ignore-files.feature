@@ -0,0 +1,163 @@
+Feature: Ignore Files
+  As a flashdoc user
+  I want flashdoc to honor .gitignore, a .flashdocignore file and --include/--exclude
+  So that generated notes stay private and real docs in folders like build/ get published
+
+  Background:
+    Given the stardoc CLI is available
+    And a source directory "./docs" exists
+    And a temp workspace exists at "/tmp/stardoc-ignore"
+    And the source file "index.md" is changed to:
+      """
+      # Home
+      """
+
+  Scenario: Paths in .gitignore are not published
+    Given the source file ".gitignore" is changed to:
+      """
+      # generated notes
+      tmp/
+      *.log.md
+      """
+    And the source file "tmp/notes.md" is changed to:
+      """
+      # Notes
+      """
+    And the source file "release.log.md" is changed to:
+      """
+      # Log
+      """
+    When files are processed honoring ignore files
+    Then the target path "tmp/notes.md" should not exist
+    And the target path "release.log.md" should not exist
+    And the target file "index.md" should contain "Home"
+
+  Scenario: Nested .gitignore files apply below their directory and support negation
+    Given the source file "guides/.gitignore" is changed to:
+      """
+      *.draft.md
+      !keep.draft.md
+      """
+    And the source file "guides/wip.draft.md" is changed to:
+      """
+      # WIP
+      """
+    And the source file "guides/keep.draft.md" is changed to:
+      """
+      # Keep
+      """
+    And the source file "other.draft.md" is changed to:
+      """
+      # Other
+      """
+    When files are processed honoring ignore files
+    Then the target path "guides/wip.draft.md" should not exist
+    And the target file "guides/keep.draft.md" should contain "Keep"
+    And the target file "other.draft.md" should contain "Other"
+
+  Scenario: Double-star patterns match at any depth
+    Given the source file ".flashdocignore" is changed to:
+      """
+      **/generated/**
+      api/**/internal.md
+      """
+    And the source file "a/b/generated/page.md" is changed to:
+      """
+      # Generated
+      """
+    And the source file "api/v1/users/internal.md" is changed to:
+      """
+      # Internal
+      """
+    And the source file "api/v1/users/public.md" is changed to:
+      """
+      # Public
+      """
+    When files are processed honoring ignore files
+    Then the target path "a/b/generated/page.md" should not exist
+    And the target path "api/v1/users/internal.md" should not exist
+    And the target file "api/v1/users/public.md" should contain "Public"
+
+  Scenario: .flashdocignore publishes a folder ignored by default
+    Given the source file ".flashdocignore" is changed to:
+      """
+      !build/
+      """
+    And the source file "build/pipeline.md" is changed to:
+      """
+      # Pipeline
+      """
+    And the source file "node_modules/pkg/README.md" is changed to:
+      """
+      # Package
+      """
+    When files are processed honoring ignore files
+    Then the target file "build/pipeline.md" should contain "Pipeline"
+    And the target path "node_modules/pkg/index.md" should not exist
+
+  Scenario: .flashdocignore publishes a path .gitignore ignores
+    Given the source file ".gitignore" is changed to:
+      """
+      generated/
+      """
+    And the source file ".flashdocignore" is changed to:
+      """
+      !generated/
+      """
+    And the source file "generated/api.md" is changed to:
+      """
+      # API
+      """
+    When files are processed honoring ignore files
+    Then the target file "generated/api.md" should contain "API"
+
+  Scenario: --include and --exclude override defaults and ignore files
+    Given the source file ".gitignore" is changed to:
+      """
+      tmp/
+      """
+    And the source file "build/pipeline.md" is changed to:
+      """
+      # Pipeline
+      """
+    And the source file "tmp/notes.md" is changed to:
+      """
+      # Notes
+      """
+    And the source file "drafts/idea.md" is changed to:
+      """
+      # Idea
+      """
+    When stardoc "build" is run on the source directory with "--include build --include tmp --exclude drafts/"
+    And files are processed with the parsed ignore settings
+    Then the target file "build/pipeline.md" should contain "Pipeline"
+    And the target file "tmp/notes.md" should contain "Notes"
+    And the target path "drafts/idea.md" should not exist
+
+  Scenario: --exclude flags are added after the config file excludes
+    Given a project config file ".flashdoc.yaml" with:
+      """
+      exclude:
+        - drafts/
+      """
+    When stardoc "check" is run on the source directory with "--exclude *.wip.md --include drafts/keep.md"
+    Then the parsed excludes should be "drafts/, *.wip.md"
+    And the parsed includes should be "drafts/keep.md"
+
+  Scenario: Dot-entries stay ignored by default
+    Given the source file ".github/CONTRIBUTING.md" is changed to:
+      """
+      # Contributing
+      """
+    When files are processed honoring ignore files
+    Then the target path ".github/CONTRIBUTING.md" should not exist
+
+  Scenario: Files inside ignored directories cannot be re-included
+    Given the source file ".gitignore" is changed to:
+      """
+      tmp/
+      !tmp/keep.md
+      """
+    Then the path "tmp/keep.md" should be ignored
+    And the path ".obsidian/workspace.md" should be ignored
+    And the path "guides/setup.md" should not be ignored
@@ -0,0 +1,46 @@
+Feature: Install Lock
+  As a flashdoc user
+  I want concurrent runs to wait for each other's install and killed installs not to block me
+  So that flashdoc never fails with a lock nobody holds
+
+  Background:
+    Given the stardoc CLI is available
+    And a source directory "./docs" exists
+    And a temp workspace exists at "/tmp/stardoc-lock"
+
+  Scenario: A lock file left by a killed install does not block
+    Given a lock file was left behind by a killed install
+    When another install acquires the lock with a 1 second timeout
+    Then the lock should be acquired without waiting
+    And the install lock should be held
+
+  Scenario: A concurrent install waits for the running one
+    Given an install holds the lock
+    And the running install finishes after 300 milliseconds
+    When another install acquires the lock with a 5 second timeout
+    Then the lock should be acquired after waiting
+
+  Scenario: Waiting for a hung install times out
+    Given an install holds the lock
+    When another install acquires the lock with a 1 second timeout
+    Then the lock attempt should time out
+
+  Scenario: Breaking the lock lets a new install proceed
+    Given an install holds the lock
+    When the install lock is broken
+    And another install acquires the lock with a 1 second timeout
+    Then the lock should be acquired without waiting
+
+  Scenario: The lock is free once the install releases it
+    Given an install holds the lock
+    When the running install finishes
+    Then the install lock should not be held
+
+  Scenario: Doctor reports a held lock
+    Given an install holds the lock
+    When the doctor is run on the source directory
+    Then the doctor should report "Install lock" as "warning"
+
+  Scenario: Parse --break-lock
+    When stardoc "build" is run on the source directory with "--break-lock"
+    Then the parsed site config should break the lock
@@ -0,0 +1,85 @@
+Feature: Link Rewriting
+  As a flashdoc user
+  I want links between my markdown files to keep working in the built site
+  So that docs written for GitHub navigate correctly in Starlight
+
+  Background:
+    Given the stardoc CLI is available
+    And a source directory "./docs" exists
+    And a temp workspace exists at "/tmp/stardoc-links"
+    And the source file "README.md" is changed to:
+      """
+      # Overview
+      """
+    And the source file "guides/Getting Started.md" is changed to:
+      """
+      # Getting Started
+      """
+    And the source file "guides/README.md" is changed to:
+      """
+      # Guides
+      """
+
+  Scenario Outline: Relative page links map to Starlight routes
+    Given the source file "guides/setup.md" is changed to:
+      """
+      # Setup
+
+      See <link>.
+      """
+    When files are processed with a public directory
+    Then the target file "guides/setup.md" should contain "<rewritten>"
+
+    Examples:
+      | link                                        | rewritten                              |
+      | [Overview](../README.md)                    | [Overview](/)                          |
+      | [Start](Getting%20Started.md)               | [Start](/guides/getting-started/)      |
+      | [Start](<Getting Started.md>)               | [Start](</guides/getting-started/>)    |
+      | [Guides](./README.md)                       | [Guides](/guides/)                     |
+      | [Guides](../guides/)                        | [Guides](/guides/)                     |
+      | [Install](Getting%20Started.md#install-node) | [Install](/guides/getting-started/#install-node) |
+
+  Scenario: Reference-style link definitions are rewritten
+    Given the source file "guides/setup.md" is changed to:
+      """
+      # Setup
+
+      Read the [overview][home] first.
+
+      [home]: ../README.md#goals
+      """
+    When files are processed with a public directory
+    Then the target file "guides/setup.md" should contain "[home]: /#goals"
+
+  Scenario: Anchors, external links and code are left untouched
+    Given the source file "guides/setup.md" is changed to:
+      """
+      # Setup
+
+      [Top](#setup), [Docs](https://example.com/README.md), `[code](../README.md)`
+      """
+    When files are processed with a public directory
+    Then the target file "guides/setup.md" should contain "[Top](#setup), [Docs](https://example.com/README.md), `[code](../README.md)`"
+
+  Scenario: Links to missing pages are reported and kept
+    Given the source file "guides/setup.md" is changed to:
+      """
+      # Setup
+
+      [Old page](old.md)
+      """
+    When files are processed with a public directory
+    Then a missing asset "old.md" should be reported at "guides/setup.md:3"
+    And the target file "guides/setup.md" should contain "[Old page](old.md)"
+
+  Scenario Outline: Routes follow Astro's slugs
+    When the route for "<path>" is computed
+    Then the route should be "<route>"
+
+    Examples:
+      | path                      | route                      |
+      | README.md                 | /                          |
+      | index.md                  | /                          |
+      | guides/README.md          | /guides/                   |
+      | API Reference/Auth_Flow.md | /api-reference/auth_flow/ |
+      | notes/v1.2 (draft).md     | /notes/v12-draft/          |
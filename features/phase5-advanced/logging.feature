@@ -0,0 +1,123 @@
+Feature: Logging
+  As a flashdoc user
+  I want to choose how much flashdoc prints and in which format
+  So that it reads well in a terminal and parses well in CI
+
+  Background:
+    Given the stardoc CLI is available
+    And a source directory "./docs" exists
+
+  Scenario: Progress goes to stdout, problems to stderr
+    Given a logger with the flags ""
+    When flashdoc logs info "📦 Workspace: /tmp/run"
+    And flashdoc logs debug "using npm"
+    And flashdoc logs warning "cannot access notes.md"
+    And flashdoc logs error "build failed"
+    Then stdout should be:
+      """
+      📦 Workspace: /tmp/run
+      """
+    And stderr should be:
+      """
+      Warning: cannot access notes.md
+      Error: build failed
+      """
+
+  Scenario: Quiet mode only shows results and problems
+    Given a logger with the flags "--quiet"
+    When flashdoc logs info "Processing 12 files..."
+    And flashdoc logs success "Dependencies installed"
+    And flashdoc logs result "🚀 Server started at http://localhost:4321"
+    And flashdoc logs warning "port 4321 was busy"
+    Then stdout should be:
+      """
+      🚀 Server started at http://localhost:4321
+      """
+    And stderr should be:
+      """
+      Warning: port 4321 was busy
+      """
+
+  Scenario: Verbose mode shows debug messages
+    Given a logger with the flags "--verbose"
+    When flashdoc logs debug "processed 12 files in 35ms"
+    Then stdout should be:
+      """
+      debug: processed 12 files in 35ms
+      """
+
+  Scenario: Timestamps prefix every message
+    Given a logger with the flags "--timestamps"
+    When flashdoc logs info "Found 12 markdown files"
+    And flashdoc logs result "✅ Build succeeded"
+    Then every stdout line should match "^\[\d{2}:\d{2}:\d{2}\] "
+
+  Scenario: JSON lines for machines
+    Given a logger with the flags "--log-format json"
+    When flashdoc logs info "📦 Workspace: /tmp/run"
+    And flashdoc logs result "✅ Exported to ./site"
+    And flashdoc logs error "build failed"
+    Then stdout line 1 should be JSON with level "info" and msg "Workspace: /tmp/run"
+    And stdout line 2 should be JSON with level "result" and msg "Exported to ./site"
+    And stderr line 1 should be JSON with level "error" and msg "build failed"
+
+  Scenario: Results carry their data as JSON fields
+    Given a logger with the flags "--log-format json"
+    When flashdoc logs the result "📁 Cache: /tmp/cache\n   Runs: 2" with the fields:
+      """
+      {"path": "/tmp/cache", "runs": 2}
+      """
+    Then stdout line 1 should be JSON with level "result" and msg "Cache: /tmp/cache"
+    And stdout line 1 should have the field "path" set to "/tmp/cache"
+    And stdout line 1 should have the field "runs" set to 2
+    And every stdout line should match "^\{"
+
+  Scenario: Results with data are shown line by line as text
+    Given a logger with the flags "--quiet --timestamps"
+    When flashdoc logs the result "📁 Cache: /tmp/cache\n   Runs: 2" with the fields:
+      """
+      {"path": "/tmp/cache", "runs": 2}
+      """
+    Then every stdout line should match "^\[\d{2}:\d{2}:\d{2}\] (📁 Cache: /tmp/cache|   Runs: 2)$"
+
+  Scenario: Spinners fall back to a single line outside a terminal
+    Given a logger with the flags ""
+    When a progress spinner for "Installing" finishes with "Dependencies installed"
+    Then stderr should contain "✓ Dependencies installed ("
+    And stderr should not contain color codes
+
+  Scenario: Spinners are silent in quiet mode
+    Given a logger with the flags "--quiet"
+    When a progress spinner for "Installing" finishes with "Dependencies installed"
+    Then stderr should be empty
+
+  Scenario: Colors on a color terminal
+    Given a logger with colors forced on
+    When flashdoc logs warning "cannot access notes.md"
+    Then stderr should contain color codes
+
+  Scenario: NO_COLOR turns colors off
+    Given the environment variable "NO_COLOR" is "1"
+    And a logger with colors forced on
+    When flashdoc logs warning "cannot access notes.md"
+    Then stderr should not contain color codes
+
+  Scenario: Redirected output is not interactive
+    Given a logger writing to a pipe
+    Then the logger should not be interactive
+
+  Scenario: Output flags are accepted by every command
+    When stardoc "build" is run on the source directory with "--quiet --timestamps --log-format json"
+    Then the parsed log config should be quiet
+    And the parsed log config should be timestamped
+    And the parsed log format should be "json"
+
+  Scenario: Verbose mode is parsed
+    When stardoc "export" is run on the source directory with "--verbose"
+    Then the parsed log config should be verbose
+
+  Scenario: Quiet and verbose conflict
+    Then running stardoc with arguments "cache path --quiet --verbose" should fail with "--quiet and --verbose cannot be used together"
+
+  Scenario: Unknown log formats are rejected
+    Then running stardoc with arguments "cache info --log-format yaml" should fail with "invalid log format"
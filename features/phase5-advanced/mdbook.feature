@@ -0,0 +1,242 @@
+Feature: mdBook and GitBook Navigation
+  As a flashdoc user with an mdBook or GitBook book
+  I want flashdoc to follow SUMMARY.md
+  So that the sidebar reads in the book's order instead of alphabetically
+
+  Background:
+    Given the stardoc CLI is available
+    And a source directory "./docs" exists
+    And a temp workspace exists at "/tmp/stardoc-mdbook"
+    And the Starlight template has been extracted
+    And the source directory has a file "README.md" with:
+      """
+      # Introduction
+      """
+    And the source directory has a file "guide/install.md" with:
+      """
+      # Installation
+      """
+    And the source directory has a file "guide/configure.md" with:
+      """
+      # Configuration
+      """
+    And the source directory has a file "notes/scratch.md" with:
+      """
+      # Scratch
+      """
+    And the source directory has a file "SUMMARY.md" with:
+      """
+      # Summary
+
+      [Introduction](README.md)
+
+      # User Guide
+
+      - [Getting Started](guide/install.md)
+          - [Configuring](guide/configure.md)
+          - [Plugins]()
+      - [Issues](https://github.com/acme/book/issues)
+
+      ---
+
+      [Scratchpad](./notes/scratch.md)
+      """
+
+  Scenario: The book is built from the src directory of book.toml
+    Given the project root has a book config "book.toml" with:
+      """
+      [book]
+      title = "Acme Book"
+      src = "docs"
+
+      [output.html]
+      git-repository-url = "https://github.com/acme/book"
+      """
+    When stardoc "build" is run on the project root with ""
+    Then the site should be built from the source directory
+    And the resolved title should be "Acme Book"
+
+  Scenario: Flags win over book.toml
+    Given the project root has a book config "book.toml" with:
+      """
+      [book]
+      title = "Acme Book"
+      src = "docs"
+      """
+    When stardoc "serve" is run on the source directory with "--title Preview"
+    Then the resolved title should be "Preview"
+
+  Scenario: SUMMARY.md becomes the sidebar and is hidden as a page
+    When stardoc "build" is run on the source directory with ""
+    And the book is processed
+    Then astro.config.mjs should contain:
+      """
+            sidebar: [
+              { label: 'Introduction', link: '/' },
+              {
+                label: 'User Guide',
+                items: [
+                  {
+                    label: 'Getting Started',
+                    items: [
+                      { label: 'Getting Started', link: '/guide/install/' },
+                      { label: 'Configuring', link: '/guide/configure/' },
+                    ],
+                  },
+                  { label: 'Issues', link: 'https://github.com/acme/book/issues' },
+                ],
+              },
+              { label: 'Scratchpad', link: '/notes/scratch/' },
+            ],
+      """
+    And astro.config.mjs should not contain "Plugins"
+    And the target path "SUMMARY.md" should not exist
+    And the target file "guide/configure.md" should contain "order: 3"
+
+  Scenario: Only the pages SUMMARY.md lists are published when asked
+    Given the source directory has a file "notes/ideas.md" with:
+      """
+      # Ideas
+      """
+    When stardoc "build" is run on the source directory with "--summary-only --exclude guide/configure.md"
+    And the book is processed
+    Then the target path "notes/ideas.md" should not exist
+    And the target path "guide/configure.md" should not exist
+    And the target file "notes/scratch.md" should contain "title: Scratch"
+
+  Scenario: Include directives are expanded
+    Given the source directory has a file "guide/snippets/example.rs" with:
+      """
+      use acme::Client;
+
+      // ANCHOR: connect
+      let client = Client::connect("localhost")?;
+      // ANCHOR_END: connect
+      client.close();
+      """
+    And the source directory has a file "guide/install.md" with:
+      """
+      # Installation
+
+      {{#include snippets/intro.md}}
+
+      ```rust
+      {{#include snippets/example.rs:connect}}
+      ```
+
+      ```rust
+      {{#include snippets/example.rs:1:1}}
+      ```
+
+      Write \{{#include file.md}} to include a file.
+
+      {{#include snippets/missing.md}}
+      """
+    And the source directory has a file "guide/snippets/intro.md" with:
+      """
+      Install the [CLI](configure.md) first.
+      """
+    When stardoc "build" is run on the source directory with ""
+    And the book is processed
+    Then the target file "guide/install.md" should contain in order:
+      """
+      Install the [CLI](/guide/configure/) first.
+      let client = Client::connect("localhost")?;
+      use acme::Client;
+      Write {{#include file.md}} to include a file.
+      {{#include snippets/missing.md}}
+      """
+    And the target file "guide/install.md" should not contain "ANCHOR"
+    And a missing asset "snippets/missing.md" should be reported at "guide/install.md:15"
+
+  Scenario: Build errors below an include point at the source line
+    Given the source directory has a file "guide/snippets/steps.md" with:
+      """
+      1. Download the archive
+      2. Unpack it
+      3. Run the installer
+      """
+    And the source directory has a file "guide/install.md" with:
+      """
+      # Installation
+
+      {{#include snippets/steps.md}}
+
+      [Setup](setup.md)
+
+      <Broken
+      """
+    When stardoc "build" is run on the source directory with ""
+    And the book is processed
+    And the astro build fails on "<Broken" in "guide/install.md" with "Unexpected end of file in expression"
+    Then the build error should point at "guide/install.md:7:1"
+    And a missing asset "setup.md" should be reported at "guide/install.md:5"
+
+  Scenario: Checks expand includes like the build
+    Given the project root has a book config "book.toml" with:
+      """
+      [book]
+      src = "docs"
+      """
+    And the source directory has a file "guide/snippets/next.md" with:
+      """
+      Continue with [plugins](plugins.md).
+      """
+    And the source directory has a file "guide/install.md" with:
+      """
+      # Installation
+
+      {{#include snippets/next.md}}
+
+      {{#include snippets/gone.md}}
+
+      [Uninstall](uninstall.md)
+      """
+    When stardoc "check" is run on the project root with ""
+    And the parsed check is run
+    Then the check should report "guide/install.md:3" as "broken-link"
+    And the check should report "guide/install.md:5" as "missing-file"
+    And the check should report "guide/install.md:7" as "broken-link"
+
+  Scenario: Edits to SUMMARY.md and book.toml are picked up while serving
+    Given the project root has a book config "book.toml" with:
+      """
+      [book]
+      src = "docs"
+      """
+    When stardoc "serve" is run on the source directory with ""
+    And the book is processed
+    And the book is being watched
+    And the source directory has a file "SUMMARY.md" with:
+      """
+      # Summary
+
+      - [Configuring](guide/configure.md)
+      - [Getting Started](guide/install.md)
+      """
+    Then the watcher should report a change covering "SUMMARY.md"
+    When the book is reloaded
+    Then astro.config.mjs should contain:
+      """
+            sidebar: [
+              { label: 'Configuring', link: '/guide/configure/' },
+              { label: 'Getting Started', link: '/guide/install/' },
+            ],
+      """
+    And the target file "guide/configure.md" should contain "order: 1"
+    When the project root has a book config "book.toml" with:
+      """
+      [book]
+      title = "Acme Book"
+      src = "docs"
+      """
+    Then the watcher should report a change covering "../book.toml"
+
+  Scenario: Invalid SUMMARY.md entries are reported
+    Given the source directory has a file "SUMMARY.md" with:
+      """
+      # Summary
+
+      - Introduction
+      """
+    Then stardoc with "" should fail with "SUMMARY.md:3: list items must be links like [Title](page.md)"
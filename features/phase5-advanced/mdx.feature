@@ -0,0 +1,160 @@
+Feature: MDX Pages
+  As a flashdoc user with component-rich docs
+  I want .mdx pages published alongside plain markdown
+  So that pages importing Starlight or my own components work
+
+  Background:
+    Given the stardoc CLI is available
+    And a source directory "./docs" exists
+    And a temp workspace exists at "/tmp/stardoc-mdx"
+    And the source file "index.md" is changed to:
+      """
+      # Home
+      """
+
+  Scenario: MDX pages get frontmatter above their imports
+    Given the source file "guides/cards.mdx" is changed to:
+      """
+      import { Card, CardGrid } from '@astrojs/starlight/components';
+      export const features = ['fast', 'simple'];
+
+      <CardGrid>
+        <Card title="Fast">Builds in seconds.</Card>
+      </CardGrid>
+      """
+    When files are processed with a public directory
+    Then the target file "guides/cards.mdx" should contain in order:
+      """
+      ---
+      title: Cards
+      ---
+      import { Card, CardGrid } from '@astrojs/starlight/components';
+      export const features = ['fast', 'simple'];
+      <CardGrid>
+      """
+
+  Scenario: Imports written above the frontmatter are moved below it
+    Given the source file "tabs.mdx" is changed to:
+      """
+      import { Tabs, TabItem } from '@astrojs/starlight/components';
+
+      ---
+      title: Install
+      ---
+
+      <Tabs><TabItem label="npm">npm i</TabItem></Tabs>
+      """
+    When files are processed with a public directory
+    Then the target file "tabs.mdx" should contain in order:
+      """
+      ---
+      title: Install
+      ---
+      import { Tabs, TabItem } from '@astrojs/starlight/components';
+      <Tabs>
+      """
+
+  Scenario: README.mdx becomes the directory index
+    Given the source file "guides/README.mdx" is changed to:
+      """
+      # Guides
+      """
+    When files are processed with a public directory
+    Then the target file "guides/index.mdx" should contain "title: Guides"
+
+  Scenario: Components are copied and their imports rewritten
+    Given the source file "components/Card.astro" is changed to:
+      """
+      <div class="card"><slot /></div>
+      """
+    And the source file "components/README.md" is changed to:
+      """
+      # Component docs
+      """
+    And the source file "guides/cards.mdx" is changed to:
+      """
+      import {
+        Aside,
+      } from '@astrojs/starlight/components';
+      import Card from '../components/Card.astro';
+
+      <Card>Hello</Card>
+      """
+    When files are processed with the components directory "components"
+    Then the copied component "Card.astro" should contain "card"
+    And the target file "guides/cards.mdx" should contain "import Card from '../../../components/Card.astro';"
+    And the target file "guides/cards.mdx" should contain "from '@astrojs/starlight/components';"
+    And the target path "components/index.md" should not exist
+    And no missing assets should be reported
+
+  Scenario: Relative imports outside the components directory are reported
+    Given the source file "guides/chart.mdx" is changed to:
+      """
+      import Chart from '../widgets/Chart.astro';
+
+      <Chart />
+      """
+    When files are processed with the components directory "components"
+    Then a missing asset "../widgets/Chart.astro" should be reported at "guides/chart.mdx:1"
+
+  Scenario: Imports of pages follow them to their published path
+    Given the source file "01-guides/02-setup.mdx" is changed to:
+      """
+      # Setup
+      """
+    And the source file "reference/all.mdx" is changed to:
+      """
+      import Setup from '../01-guides/02-setup.mdx';
+      import Old from '../guides/old.mdx';
+
+      <Setup />
+      """
+    When files are processed with a public directory
+    Then the target file "reference/all.mdx" should contain "import Setup from '../guides/setup.mdx';"
+    And a missing asset "../guides/old.mdx" should be reported at "reference/all.mdx:2"
+
+  Scenario: Relative imports without a components directory are reported
+    Given the source file "guides/chart.mdx" is changed to:
+      """
+      import { Aside } from '@astrojs/starlight/components';
+      import Chart from '../components/Chart.astro';
+
+      <Chart />
+      """
+    When files are processed with a public directory
+    Then a missing asset "../components/Chart.astro" should be reported at "guides/chart.mdx:2"
+
+  Scenario: The native renderer renders MDX without its imports
+    Given the source file "guides/cards.mdx" is changed to:
+      """
+      import { Card } from '@astrojs/starlight/components';
+
+      ## Overview
+
+      <Card title="Fast">Builds in seconds.</Card>
+      """
+    When files are processed with a public directory
+    And the processed docs are rendered natively
+    Then the rendered page "guides/cards/index.html" should contain:
+      """
+      <h2 id="overview">Overview</h2>
+      """
+    And the rendered page "guides/cards/index.html" should not contain:
+      """
+      @astrojs/starlight/components
+      """
+
+  Scenario: The components directory comes from the config file
+    Given the source file "ui/Card.astro" is changed to:
+      """
+      <div />
+      """
+    And a project config file ".flashdoc.yaml" with:
+      """
+      components: ui
+      """
+    When stardoc "build" is run on the source directory with ""
+    Then the parsed components directory should be "ui"
+
+  Scenario: The components directory must be inside the source directory
+    Then running stardoc with arguments "build . --components /tmp" should fail with "must be inside"
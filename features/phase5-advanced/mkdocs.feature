@@ -0,0 +1,205 @@
+Feature: MkDocs Import
+  As a flashdoc user with MkDocs projects
+  I want flashdoc to read mkdocs.yml
+  So that `flashdoc .` previews an MkDocs repo the way MkDocs would build it
+
+  Background:
+    Given the stardoc CLI is available
+    And a source directory "./docs" exists
+    And a temp workspace exists at "/tmp/stardoc-mkdocs"
+    And the Starlight template has been extracted
+    And the source directory has a file "index.md" with:
+      """
+      # Home
+      """
+    And the source directory has a file "guide/install.md" with:
+      """
+      # Installation
+      """
+    And the source directory has a file "guide/configure.md" with:
+      """
+      # Configuration
+      """
+    And the source directory has a file "css/brand.css" with:
+      """
+      :root { --brand: teal; }
+      """
+
+  Scenario: The project root is built from its docs_dir
+    Given the project root has an MkDocs config "mkdocs.yml" with:
+      """
+      site_name: Acme Handbook
+      repo_url: https://github.com/acme/handbook
+      extra_css:
+        - css/brand.css
+        - https://fonts.example.com/inter.css
+      markdown_extensions:
+        - pymdownx.emoji:
+            emoji_index: !!python/name:material.extensions.emoji.twemoji
+      """
+    When stardoc "build" is run on the project root with ""
+    Then the site should be built from the source directory
+    And the resolved title should be "Acme Handbook"
+    And the parsed stylesheets should be "css/brand.css"
+    And the parsed social link "github" should be "https://github.com/acme/handbook"
+
+  Scenario: Environment variables in mkdocs.yml are resolved
+    Given the environment variable "ACME_SITE_NAME" is "Acme Internal Handbook"
+    And the project root has an MkDocs config "mkdocs.yml" with:
+      """
+      site_name: !ENV ACME_SITE_NAME
+      repo_url: !ENV [ACME_REPO_URL, "https://github.com/acme/handbook"]
+      """
+    When stardoc "build" is run on the project root with ""
+    Then the resolved title should be "Acme Internal Handbook"
+    And the parsed social link "github" should be "https://github.com/acme/handbook"
+
+  Scenario: A custom docs_dir
+    Given the project root has an MkDocs config "mkdocs.yaml" with:
+      """
+      site_name: Acme Handbook
+      docs_dir: docs/guide
+      """
+    When stardoc "build" is run on the project root with ""
+    Then the site should be built from "guide" in the source directory
+
+  Scenario: The config in the parent of the docs directory is used
+    Given the project root has an MkDocs config "mkdocs.yml" with:
+      """
+      site_name: Acme Handbook
+      """
+    When stardoc "serve" is run on the source directory with ""
+    Then the resolved title should be "Acme Handbook"
+
+  Scenario: Flags and the project config win over mkdocs.yml
+    Given the project root has an MkDocs config "mkdocs.yml" with:
+      """
+      site_name: Acme Handbook
+      """
+    And a project config file ".flashdoc.yaml" with:
+      """
+      title: Team Handbook
+      """
+    When stardoc "build" is run on the project root with ""
+    Then the resolved title should be "Team Handbook"
+    When stardoc "build" is run on the project root with "--title Preview"
+    Then the resolved title should be "Preview"
+
+  Scenario: The nav becomes the sidebar
+    Given the project root has an MkDocs config "mkdocs.yml" with:
+      """
+      site_name: Acme Handbook
+      nav:
+        - index.md
+        - User Guide:
+          - Setup: guide/install.md
+          - guide/configure.md
+          - guide/missing.md
+        - Issues: https://github.com/acme/handbook/issues
+      """
+    When stardoc "build" is run on the project root with ""
+    And the MkDocs site is processed
+    Then astro.config.mjs should contain:
+      """
+            sidebar: [
+              { label: 'Home', link: '/' },
+              {
+                label: 'User Guide',
+                items: [
+                  { label: 'Setup', link: '/guide/install/' },
+                  { label: 'Configuration', link: '/guide/configure/' },
+                ],
+              },
+              { label: 'Issues', link: 'https://github.com/acme/handbook/issues' },
+            ],
+      """
+
+  Scenario: Invalid nav entries are reported
+    Given the project root has an MkDocs config "mkdocs.yml" with:
+      """
+      nav:
+        - Guide: 42
+      """
+    Then running stardoc on the project root should fail with "mkdocs.yml: nav: Guide: must be a page, a link or a list of entries"
+
+  Scenario: Admonitions become asides
+    Given the project root has an MkDocs config "mkdocs.yml" with:
+      """
+      site_name: Acme Handbook
+      """
+    And the source directory has a file "guide/upgrade.md" with:
+      """
+      # Upgrading
+
+      !!! note
+          Back up your data first.
+
+      !!! warning "Breaking change"
+          The `--out` flag was renamed, see [configuration](configure.md).
+
+          ??? example
+              Run `acme migrate`.
+
+      Done.
+
+      ```markdown
+      !!! tip
+          Left alone inside code.
+      ```
+      """
+    When stardoc "build" is run on the project root with ""
+    And the MkDocs site is processed
+    Then the target file "guide/upgrade.md" should contain in order:
+      """
+      :::note
+      Back up your data first.
+      :::
+      ::::caution[Breaking change]
+      The `--out` flag was renamed, see [configuration](/guide/configure/).
+      :::tip[Example]
+      Run `acme migrate`.
+      :::
+      ::::
+      Done.
+      !!! tip
+          Left alone inside code.
+      """
+
+  Scenario: Build errors below an admonition point at the source line
+    Given the project root has an MkDocs config "mkdocs.yml" with:
+      """
+      site_name: Acme Handbook
+      """
+    And the source directory has a file "guide/upgrade.md" with:
+      """
+      # Upgrading
+
+      !!! note
+          Back up your data first.
+      Then run the upgrade.
+
+      {broken
+      """
+    When stardoc "build" is run on the project root with ""
+    And the MkDocs site is processed
+    And the astro build fails on "{broken" in "guide/upgrade.md" with "Could not parse expression with acorn"
+    Then the build error should point at "guide/upgrade.md:7:1"
+
+  Scenario: Checks look inside admonitions like the build
+    Given the project root has an MkDocs config "mkdocs.yml" with:
+      """
+      site_name: Acme Handbook
+      """
+    And the source directory has a file "guide/upgrade.md" with:
+      """
+      # Upgrading
+
+      !!! note
+          Back up your data first.
+
+          See [backups](backups.md).
+      """
+    When stardoc "check" is run on the project root with ""
+    And the parsed check is run
+    Then the check should report "guide/upgrade.md:6" as "broken-link"
+    And the check should report 1 problem
@@ -0,0 +1,144 @@
+Feature: Native Renderer
+  As a flashdoc user without Node.js installed
+  I want flashdoc to render my docs with a built-in renderer
+  So that I still get a browsable site instead of a "no package manager found" error
+
+  Background:
+    Given the stardoc CLI is available
+    And a source directory "./docs" exists
+    And a temp workspace exists at "/tmp/stardoc-native"
+
+  Scenario: Pages are rendered to their Starlight routes
+    Given the source directory contains:
+      """
+      docs/
+      ├── index.md
+      └── guides/
+          └── setup.md
+      """
+    When files are processed with a public directory
+    And the processed docs are rendered natively
+    Then the rendered file "index.html" should exist
+    And the rendered file "guides/setup/index.html" should exist
+    And the rendered file "404.html" should exist
+    And the rendered file "_flashdoc/style.css" should exist
+
+  Scenario: GFM tables and highlighted code blocks
+    Given the source file "reference.md" is changed to:
+      """
+      # Reference
+
+      | Flag | Default |
+      |------|---------|
+      | port | 4321    |
+
+      ```go
+      func main() {}
+      ```
+      """
+    When files are processed with a public directory
+    And the processed docs are rendered natively
+    Then the rendered page "reference/index.html" should contain:
+      """
+      <table>
+      <th>Flag</th>
+      <td>4321</td>
+      <pre class="chroma">
+      """
+
+  Scenario: The page title heading is not rendered twice
+    Given the source file "reference.md" is changed to:
+      """
+      # Reference
+
+      Some text.
+      """
+    When files are processed with a public directory
+    And the processed docs are rendered natively
+    Then the rendered page "reference/index.html" should contain:
+      """
+      <h1 id="_top">Reference</h1>
+      """
+    And the rendered page "reference/index.html" should not contain:
+      """
+      <h1 id="reference">
+      """
+
+  Scenario: Each page has a table of contents
+    Given the source file "guides/setup.md" is changed to:
+      """
+      # Setup
+
+      ## Install the CLI
+
+      ### On macOS
+
+      ## Configure
+      """
+    When files are processed with a public directory
+    And the processed docs are rendered natively
+    Then the rendered page "guides/setup/index.html" should contain:
+      """
+      <h2 id="install-the-cli">Install the CLI</h2>
+      <li class="level-2"><a href="#install-the-cli">Install the CLI</a></li>
+      <li class="level-3"><a href="#on-macos">On macOS</a></li>
+      <li class="level-2"><a href="#configure">Configure</a></li>
+      """
+
+  Scenario: The sidebar mirrors the directory tree and sidebar order
+    Given the source file "index.md" is changed to:
+      """
+      # Welcome
+      """
+    And the source file "guides/zebra.md" is changed to:
+      """
+      ---
+      title: Zebra
+      sidebar:
+        order: 1
+      ---
+      """
+    And the source file "guides/alpha.md" is changed to:
+      """
+      # Alpha
+      """
+    When files are processed with a public directory
+    And the processed docs are rendered natively
+    Then the sidebar of "index.html" should list "Welcome, Guides, Zebra, Alpha" in order
+    And the rendered page "guides/alpha/index.html" should contain:
+      """
+      <a href="/guides/alpha/" aria-current="page">Alpha</a>
+      <a class="prev" href="/guides/zebra/">
+      """
+
+  Scenario: Images and page links work from the rendered routes
+    Given a source asset "guides/img/arch.png"
+    And the source file "guides/setup.md" is changed to:
+      """
+      # Setup
+
+      ![Architecture](./img/arch.png)
+
+      Back to the [introduction](../intro.md).
+      """
+    And the source file "intro.md" is changed to:
+      """
+      # Introduction
+      """
+    When files are processed with a public directory
+    And the processed docs are rendered natively
+    Then the rendered file "guides/img/arch.png" should exist
+    And the rendered page "guides/setup/index.html" should contain:
+      """
+      <img src="/guides/img/arch.png" alt="Architecture">
+      <a href="/intro/">introduction</a>
+      """
+
+  Scenario: Selecting the native renderer
+    When stardoc "export" is run on the source directory with "./site --renderer native"
+    Then the resolved renderer should be "native"
+    And the resolved export path should end with "site"
+
+  Scenario: Rejecting unknown renderers and dev mode without Astro
+    Then stardoc with "--renderer hugo" should fail with "invalid renderer"
+    And stardoc with "--renderer native --dev" should fail with "--dev requires the astro renderer"
@@ -0,0 +1,102 @@
+Feature: Package Manager Selection
+  As a flashdoc user
+  I want to pick the package manager that installs and builds the Starlight site
+  So that flashdoc works with the tools my team already uses
+
+  Background:
+    Given the stardoc CLI is available
+    And a source directory "./docs" exists
+    And a temp workspace exists at "/tmp/stardoc-package-managers"
+
+  Scenario Outline: Every package manager has install, build and dev commands
+    Then the install command for "<manager>" should be "<install>"
+    And the build command for "<manager>" should be "<build>"
+    And the dev command for "<manager>" should be "<dev>"
+
+    Examples:
+      | manager    | install      | build           | dev                          |
+      | pnpm       | pnpm install | pnpm run build  | pnpm run dev -- --port 4321  |
+      | bun        | bun install  | bun run build   | bun run dev --port 4321      |
+      | npm        | npm install  | npm run build   | npm run dev -- --port 4321   |
+      | yarn       | yarn install | yarn run build  | yarn run dev --port 4321     |
+      | yarn-berry | yarn install | yarn run build  | yarn run dev --port 4321     |
+      | deno       | deno install | deno task build | deno task dev --port 4321    |
+
+  Scenario: Yarn berry installs a node_modules directory
+    Then the commands for "yarn-berry" should set "YARN_NODE_LINKER=node-modules"
+    And the commands for "yarn" should not set any environment variables
+
+  Scenario: Yarn 1.x is Yarn classic
+    Given only "yarn" version "1.22.22" is installed
+    When the package manager "yarn" is selected
+    Then the selected package manager should be "yarn"
+
+  Scenario: Later Yarn versions are Yarn berry
+    Given only "yarn" version "4.1.0" is installed
+    When the package manager "yarn" is selected
+    Then the selected package manager should be "yarn-berry"
+
+  Scenario: Detection falls back to Deno
+    Given only "deno" version "2.1.4" is installed
+    When the package manager "auto" is selected
+    Then the selected package manager should be "deno"
+
+  Scenario: A package manager chosen explicitly must be installed
+    Given only "npm" version "10.8.0" is installed
+    When the package manager "pnpm" is selected
+    Then selecting the package manager should fail with "package manager pnpm not found in PATH"
+
+  Scenario: The package manager comes from the command line
+    When stardoc "build" is run on the source directory with "--package-manager deno"
+    Then the parsed package manager should be "deno"
+
+  Scenario: The package manager comes from the config file
+    Given a project config file ".flashdoc.yaml" with:
+      """
+      packageManager: yarn
+      """
+    When stardoc "build" is run on the source directory with ""
+    Then the parsed package manager should be "yarn"
+
+  Scenario: The flag overrides the environment and the config file
+    Given a project config file ".flashdoc.yaml" with:
+      """
+      packageManager: yarn
+      """
+    And the environment variable "FLASHDOC_PACKAGE_MANAGER" is "bun"
+    When stardoc "serve" is run on the source directory with "--package-manager npm"
+    Then the parsed package manager should be "npm"
+
+  Scenario: The environment overrides the config file
+    Given a project config file ".flashdoc.yaml" with:
+      """
+      packageManager: yarn
+      """
+    And the environment variable "FLASHDOC_PACKAGE_MANAGER" is "bun"
+    When stardoc "export" is run on the source directory with ""
+    Then the parsed package manager should be "bun"
+
+  Scenario: Unknown package managers are rejected
+    Then running stardoc with arguments "build . --package-manager pip" should fail with "unsupported package manager: pip (supported: pnpm, bun, npm, yarn, deno)"
+
+  Scenario: Unknown package managers in the config file are reported
+    Given a project config file ".flashdoc.yaml" with:
+      """
+      packageManager: pip
+      """
+    When the project config is loaded
+    Then loading the project config should fail with ".flashdoc.yaml:1: packageManager: must be one of auto, pnpm, bun, npm, yarn, deno"
+
+  Scenario: The shared install is reused by the same package manager
+    Given the shared install was done with "pnpm"
+    Then the shared install should be current for "pnpm"
+
+  Scenario: Switching package managers reinstalls from scratch
+    Given the shared install was done with "npm"
+    And the shared project has a file "package.json"
+    And the shared project has a file "package-lock.json"
+    Then the shared install should not be current for "pnpm"
+    When the shared install is cleaned
+    Then the shared project should not have "node_modules"
+    And the shared project should not have "package-lock.json"
+    And the shared project should have "package.json"
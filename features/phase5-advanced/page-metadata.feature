@@ -0,0 +1,152 @@
+Feature: Page Descriptions and Ordering
+  As a flashdoc user who numbers pages and writes an introduction
+  I want the sidebar to follow my numbering and pages to carry a description
+  So that the site reads in order and previews well in search results
+
+  Background:
+    Given the stardoc CLI is available
+    And a source directory "./docs" exists
+    And a temp workspace exists at "/tmp/stardoc-metadata"
+    And the source file "index.md" is changed to:
+      """
+      # Home
+      """
+
+  Scenario: Number prefixes set the sidebar order and are dropped from the slug
+    Given the source file "guides/02-configure.md" is changed to:
+      """
+      # Configure
+      """
+    And the source file "guides/10-deploy.md" is changed to:
+      """
+      # Deploy
+
+      Back to [configuration](02-configure.md#options).
+      """
+    When files are processed with a public directory
+    Then the target file "guides/configure.md" should contain in order:
+      """
+      title: Configure
+      sidebar:
+        order: 2
+      """
+    And the target file "guides/deploy.md" should contain "order: 10"
+    And the target file "guides/deploy.md" should contain "[configuration](/guides/configure/#options)"
+    And the target path "guides/02-configure.md" should not exist
+
+  Scenario: An explicit sidebar order wins over the prefix
+    Given the source file "03-faq.md" is changed to:
+      """
+      ---
+      sidebar:
+        order: 7
+      ---
+      """
+    When files are processed with a public directory
+    Then the target file "faq.md" should contain "order: 7"
+
+  Scenario: Pages listed in the config sidebar come before prefixed pages
+    Given the source file "intro.md" is changed to:
+      """
+      # Intro
+      """
+    And the source file "01-install.md" is changed to:
+      """
+      # Install
+      """
+    And a project config file ".flashdoc.yaml" with:
+      """
+      sidebar:
+        - intro.md
+      """
+    When the project config is loaded
+    And files are processed with the project config
+    Then the target file "intro.md" should contain "order: 1"
+    And the target file "install.md" should contain "order: 2"
+
+  Scenario: Numbered directories set the order and are dropped from the slug
+    Given the source file "02-reference/api.md" is changed to:
+      """
+      # API
+
+      See the [setup](../01-guides/setup.md) first.
+      """
+    And the source file "01-guides/setup.md" is changed to:
+      """
+      # Setup
+
+      ![Screenshot](shot.png)
+      """
+    And the source file "01-guides/shot.png" is changed to:
+      """
+      png
+      """
+    When files are processed with a public directory
+    Then the target file "guides/setup.md" should contain "![Screenshot](shot.png)"
+    And the target file "guides/shot.png" should contain "png"
+    And the target file "reference/api.md" should contain "[setup](/guides/setup/)"
+    And the target path "01-guides" should not exist
+    And the sidebar should list "Guides, Reference, Home"
+
+  Scenario: Numbered directories order the native sidebar
+    Given the source file "02-reference/api.md" is changed to:
+      """
+      # API
+      """
+    And the source file "01-guides/setup.md" is changed to:
+      """
+      # Setup
+      """
+    When files are processed with a public directory
+    And the processed docs are rendered natively with the directory metadata
+    Then the sidebar of "index.html" should list "Guides, Setup, Reference, API, Home" in order
+
+  Scenario: Names that only differ by a number prefix are reported
+    Given the source file "guides/01-intro.md" is changed to:
+      """
+      # Intro
+      """
+    And the source file "guides/intro.md" is changed to:
+      """
+      # Introduction
+      """
+    Then processing the files should fail with "guides/01-intro.md and guides/intro.md are both published as guides/intro.md"
+
+  Scenario: The first paragraph becomes the description
+    Given the source file "guide.md" is changed to:
+      """
+      # Guide
+
+      ![Diagram](diagram.png)
+
+      ```sh
+      flashdoc ./docs
+      ```
+
+      Learn how to **publish** your docs with
+      [flashdoc](https://example.com) in `one command`.
+
+      More details.
+      """
+    When files are processed with a public directory
+    Then the target file "guide.md" should contain "description: Learn how to publish your docs with flashdoc in one command."
+
+  Scenario: Long descriptions are cut at a word boundary
+    Given the source file "guide.md" is changed to:
+      """
+      This sentence is repeated to make a very long introduction. This sentence is repeated to make a very long introduction. This sentence is repeated to make a very long introduction.
+      """
+    When files are processed with a public directory
+    Then the target file "guide.md" should contain "description: This sentence is repeated to make a very long introduction. This sentence is repeated to make a very long introduction. This sentence is repeated to make a…"
+
+  Scenario: An existing description is kept
+    Given the source file "guide.md" is changed to:
+      """
+      ---
+      description: Hand-written summary
+      ---
+      Generated summary.
+      """
+    When files are processed with a public directory
+    Then the target file "guide.md" should contain "description: Hand-written summary"
+    And the target file "guide.md" should not contain "description: Generated summary."
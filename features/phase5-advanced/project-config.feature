@@ -0,0 +1,131 @@
+Feature: Project Config File
+  As a team committing docs to a repository
+  I want flashdoc settings in a .flashdoc.yaml next to the docs
+  So that everyone builds the site the same way without remembering flags
+
+  Background:
+    Given the stardoc CLI is available
+    And a source directory "./docs" exists
+
+  Scenario: Load settings from .flashdoc.yaml
+    Given a project config file ".flashdoc.yaml" with:
+      """
+      title: Team Handbook
+      port: 5000
+      exclude:
+        - drafts/
+      sidebar:
+        - intro.md
+        - guides/setup.md
+      logo: assets/logo.svg
+      social:
+        github: https://github.com/example/handbook
+      export: ../site
+      """
+    When the project config is loaded
+    Then the project config should have title "Team Handbook"
+    And the project config should have port 5000
+    And the project config should exclude "drafts/"
+    And the project config should have social link "github" to "https://github.com/example/handbook"
+
+  Scenario: Load settings from flashdoc.toml
+    Given a project config file "flashdoc.toml" with:
+      """
+      title = "Team Handbook"
+      port = 5001
+      sidebar = ["intro.md"]
+
+      [social]
+      github = "https://github.com/example/handbook"
+      """
+    When the project config is loaded
+    Then the project config should have title "Team Handbook"
+    And the project config should have port 5001
+
+  Scenario: A directory without a config file uses defaults
+    When the project config is loaded
+    Then the project config should have title ""
+    And the project config should have port 0
+
+  Scenario: Unknown keys point to the line and suggest a fix
+    Given a project config file ".flashdoc.yaml" with:
+      """
+      title: Team Handbook
+      prot: 5000
+      """
+    When the project config is loaded
+    Then loading the project config should fail with ".flashdoc.yaml:2: prot: unknown key"
+    And the project config error should suggest "port"
+
+  Scenario: Invalid values point to the key and line
+    Given a project config file "flashdoc.toml" with:
+      """
+      title = "Team Handbook"
+
+      port = 80
+      """
+    When the project config is loaded
+    Then loading the project config should fail with "flashdoc.toml:3: port:"
+
+  Scenario: Nested values report their full key
+    Given a project config file ".flashdoc.yaml" with:
+      """
+      social:
+        github: not-a-url
+      """
+    When the project config is loaded
+    Then loading the project config should fail with ".flashdoc.yaml:2: social.github:"
+
+  Scenario: Environment variables override the config file
+    Given a project config file ".flashdoc.yaml" with:
+      """
+      title: Team Handbook
+      port: 5000
+      """
+    And the environment variable "FLASHDOC_PORT" is "5002"
+    When the project config is loaded
+    Then the project config should have title "Team Handbook"
+    And the project config should have port 5002
+
+  Scenario: Flags override environment variables and the config file
+    Given a project config file ".flashdoc.yaml" with:
+      """
+      title: Team Handbook
+      port: 5000
+      """
+    And the environment variable "FLASHDOC_TITLE" is "From Env"
+    When stardoc is run on the source directory with "--port 6000"
+    Then the resolved title should be "From Env"
+    And the resolved port should be 6000
+
+  Scenario: Export without an output directory uses the export path from the config file
+    Given a project config file ".flashdoc.yaml" with:
+      """
+      export: site
+      """
+    When stardoc "export" is run on the source directory with ""
+    Then the resolved export path should end with "docs/site"
+
+  Scenario: Excluded files and sidebar order are applied when processing
+    Given a temp workspace exists at "/tmp/stardoc-config"
+    And the source directory contains:
+      """
+      ./docs/
+      ├── intro.md
+      ├── setup.md
+      └── drafts/
+          └── wip.md
+      """
+    And a project config file ".flashdoc.yaml" with:
+      """
+      exclude:
+        - drafts/
+      sidebar:
+        - setup.md
+        - intro.md
+      """
+    When the project config is loaded
+    And files are processed with the project config
+    Then the target path "drafts/wip.md" should not exist
+    And the target file "setup.md" should contain "order: 1"
+    And the target file "intro.md" should contain "order: 2"
@@ -0,0 +1,195 @@
+Feature: Directory Metadata
+  As a flashdoc user
+  I want to label, order and collapse sidebar groups with a file in each directory
+  So that the sidebar reads like a table of contents instead of a list of folder names
+
+  Background:
+    Given the stardoc CLI is available
+    And a source directory "./docs" exists
+    And a temp workspace exists at "/tmp/stardoc-sidebar-metadata"
+    And the Starlight template has been extracted
+    And the source directory has a file "index.md" with:
+      """
+      # Welcome
+      """
+    And the source directory has a file "getting-started/install.md" with:
+      """
+      # Install
+      """
+    And the source directory has a file "getting-started/01-requirements.md" with:
+      """
+      # Requirements
+      """
+    And the source directory has a file "reference/cli.md" with:
+      """
+      # CLI
+      """
+
+  Scenario: Without metadata files Starlight generates the sidebar
+    When files are processed with a public directory
+    And the sidebar is written to the Starlight config
+    Then astro.config.mjs should not contain "sidebar:"
+
+  Scenario: Labels, order, collapsing and badges
+    Given the source directory has a file "getting-started/_meta.yaml" with:
+      """
+      label: Getting Started
+      order: 1
+      """
+    And the source directory has a file "reference/_meta.yaml" with:
+      """
+      order: 2
+      collapsed: true
+      badge:
+        text: Stable
+        variant: success
+      """
+    When files are processed with a public directory
+    And the sidebar is written to the Starlight config
+    Then astro.config.mjs should contain:
+      """
+            sidebar: [
+              {
+                label: 'Getting Started',
+                items: [
+                  { label: 'Requirements', link: '/getting-started/requirements/' },
+                  { label: 'Install', link: '/getting-started/install/' },
+                ],
+              },
+              {
+                label: 'Reference',
+                badge: { text: 'Stable', variant: 'success' },
+                collapsed: true,
+                items: [
+                  { label: 'CLI', link: '/reference/cli/' },
+                ],
+              },
+              { label: 'Welcome', link: '/' },
+            ],
+      """
+
+  Scenario: Docusaurus category files
+    Given the source directory has a file "reference/_category_.json" with:
+      """
+      { "label": "API Reference", "position": 1, "collapsed": false }
+      """
+    When files are processed with a public directory
+    Then the sidebar should list "API Reference, Welcome, Getting Started"
+
+  Scenario: Zero and negative orders sort before items without one
+    Given the source directory has a file "getting-started/_meta.yaml" with:
+      """
+      order: 0
+      """
+    And the source directory has a file "reference/_meta.yaml" with:
+      """
+      position: -1
+      """
+    When files are processed with a public directory
+    Then the sidebar should list "Reference, Getting Started, Welcome"
+
+  Scenario: Page frontmatter sets labels, badges and hides pages
+    Given the source directory has a file "reference/_meta.yaml" with:
+      """
+      label: Reference
+      """
+    And the source directory has a file "reference/flags.md" with:
+      """
+      ---
+      title: Command-line flags
+      sidebar:
+        label: Flags
+        badge: New
+      ---
+      """
+    And the source directory has a file "reference/internals.md" with:
+      """
+      ---
+      title: Internals
+      sidebar:
+        hidden: true
+      ---
+      """
+    When files are processed with a public directory
+    And the sidebar is written to the Starlight config
+    Then astro.config.mjs should contain:
+      """
+                  { label: 'CLI', link: '/reference/cli/' },
+                  {
+                    label: 'Flags',
+                    badge: { text: 'New' },
+                    link: '/reference/flags/',
+                  },
+                ],
+      """
+    And astro.config.mjs should not contain "Internals"
+
+  Scenario: Editing a metadata file updates the sidebar
+    Given the source directory has a file "reference/_meta.yaml" with:
+      """
+      label: Reference
+      """
+    When files are processed with a public directory
+    And the source directory has a file "reference/_meta.yaml" with:
+      """
+      label: Commands
+      """
+    And the change to "reference/_meta.yaml" is synced by the same processor
+    Then the sidebar should list "Welcome, Commands, Getting Started"
+
+  Scenario: Invalid metadata files are reported
+    Given the source directory has a file "reference/_meta.yaml" with:
+      """
+      badge:
+        text: Beta
+        variant: shiny
+      """
+    Then processing the files should fail with "reference/_meta.yaml: badge variant must be one of default, note, tip, success, caution, danger"
+
+  Scenario: The native renderer uses the directory metadata
+    Given the source directory has a file "reference/_meta.yaml" with:
+      """
+      label: Commands
+      order: 1
+      collapsed: true
+      """
+    When files are processed with a public directory
+    And the processed docs are rendered natively with the directory metadata
+    Then the sidebar of "index.html" should list "Commands, Welcome, Getting Started" in order
+    And the rendered page "index.html" should contain:
+      """
+      <details><summary>Commands</summary>
+      """
+    And the rendered page "reference/cli/index.html" should contain:
+      """
+      <details open><summary>Commands</summary>
+      """
+
+  Scenario: The native renderer shows group badges
+    Given the source directory has a file "reference/_meta.yaml" with:
+      """
+      badge: Stable
+      """
+    When files are processed with a public directory
+    And the processed docs are rendered natively with the directory metadata
+    Then the rendered page "index.html" should contain:
+      """
+      <summary>Reference <span class="badge">Stable</span></summary>
+      """
+
+  Scenario: The native renderer sorts negative orders first
+    Given the source directory has a file "changelog.md" with:
+      """
+      ---
+      title: Changelog
+      sidebar:
+        order: -1
+      ---
+      """
+    And the source directory has a file "reference/_meta.yaml" with:
+      """
+      order: 1
+      """
+    When files are processed with a public directory
+    And the processed docs are rendered natively with the directory metadata
+    Then the sidebar of "index.html" should list "Changelog, Reference, Welcome, Getting Started" in order
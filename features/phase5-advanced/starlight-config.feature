@@ -0,0 +1,128 @@
+Feature: Starlight Config Generation
+  As a flashdoc user
+  I want astro.config.mjs to be generated from the site's settings
+  So that any title works and Starlight options can be set without breaking the config
+
+  Background:
+    Given the stardoc CLI is available
+    And a temp workspace exists at "/tmp/stardoc-starlight-config"
+    And the Starlight template has been extracted
+
+  Scenario: A title alone
+    Given a Starlight config titled "Team Handbook"
+    When the Starlight config is written
+    Then astro.config.mjs should be:
+      """
+      // Learn more: https://starlight.astro.build/
+      import { defineConfig } from 'astro/config';
+      import starlight from '@astrojs/starlight';
+
+      export default defineConfig({
+        integrations: [
+          starlight({
+            title: 'Team Handbook',
+          }),
+        ],
+      });
+      """
+
+  Scenario: Quotes and backslashes in the title are escaped
+    Given a Starlight config titled "Bob's API \ v2"
+    When the Starlight config is written
+    Then astro.config.mjs should contain:
+      """
+      title: 'Bob\'s API \\ v2',
+      """
+
+  Scenario: Site options
+    Given a Starlight config titled "Handbook" with:
+      | description     | Everything about working here                |
+      | logo            | ./src/assets/logo.svg                        |
+      | favicon         | /favicon.svg                                 |
+      | social          | github https://github.com/acme/handbook      |
+      | customCss       | ./src/styles/brand.css                       |
+      | editLink        | https://github.com/acme/handbook/edit/main/  |
+      | pagination      | false                                        |
+      | tableOfContents | 2-4                                          |
+    When the Starlight config is written
+    Then astro.config.mjs should contain:
+      """
+          starlight({
+            title: 'Handbook',
+            description: 'Everything about working here',
+            logo: { src: './src/assets/logo.svg' },
+            favicon: '/favicon.svg',
+            social: [
+              { icon: 'github', label: 'GitHub', href: 'https://github.com/acme/handbook' },
+            ],
+            customCss: ['./src/styles/brand.css'],
+            editLink: { baseUrl: 'https://github.com/acme/handbook/edit/main/' },
+            pagination: false,
+            tableOfContents: { minHeadingLevel: 2, maxHeadingLevel: 4 },
+          }),
+      """
+
+  Scenario: The table of contents can be turned off
+    Given a Starlight config titled "Handbook" with:
+      | tableOfContents | false |
+    When the Starlight config is written
+    Then astro.config.mjs should contain:
+      """
+      tableOfContents: false,
+      """
+
+  Scenario: Sidebar links, groups and autogenerated groups
+    Given a Starlight config titled "Handbook"
+    And the sidebar links "Welcome" to "/"
+    And the sidebar has a group "Guides" generated from "guides"
+    And the sidebar has a group "Reference" linking "API" to "/reference/api/"
+    When the Starlight config is written
+    Then astro.config.mjs should contain:
+      """
+            sidebar: [
+              { label: 'Welcome', link: '/' },
+              {
+                label: 'Guides',
+                autogenerate: { directory: 'guides' },
+              },
+              {
+                label: 'Reference',
+                items: [
+                  { label: 'API', link: '/reference/api/' },
+                ],
+              },
+            ],
+      """
+
+  Scenario: Locales
+    Given a Starlight config titled "Handbook"
+    And the site has the locale "root" labelled "English" in "en"
+    And the site has the locale "pt-br" labelled "Português do Brasil" in "pt-BR"
+    And the default locale is "root"
+    When the Starlight config is written
+    Then astro.config.mjs should contain:
+      """
+            defaultLocale: 'root',
+            locales: {
+              'pt-br': { label: 'Português do Brasil', lang: 'pt-BR' },
+              root: { label: 'English', lang: 'en' },
+            },
+      """
+
+  Scenario: The default locale must be one of the locales
+    Given a Starlight config titled "Handbook"
+    And the site has the locale "fr" labelled "Français" in "fr"
+    And the default locale is "de"
+    When the Starlight config is written
+    Then writing the Starlight config should fail with "is not one of the locales"
+
+  Scenario: Table of contents levels are checked
+    Given a Starlight config titled "Handbook" with:
+      | tableOfContents | 4-2 |
+    When the Starlight config is written
+    Then writing the Starlight config should fail with "table of contents levels must satisfy 1 <= min <= max <= 6"
+
+  Scenario: A title is required
+    Given a Starlight config titled ""
+    When the Starlight config is written
+    Then writing the Starlight config should fail with "title is required"
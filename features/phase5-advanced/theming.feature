@@ -0,0 +1,133 @@
+Feature: Theming
+  As a flashdoc user
+  I want my site to carry my project's logo, favicon, styles and colors
+  So that it doesn't look like every other flashdoc site
+
+  Background:
+    Given the stardoc CLI is available
+    And a source directory "./docs" exists
+    And a temp workspace exists at "/tmp/stardoc-theming"
+    And the Starlight template has been extracted
+
+  Scenario: Conventional files in the source directory are picked up
+    Given the source directory has a file "logo.svg"
+    And the source directory has a file "favicon.ico"
+    And the source directory has a file "custom.css"
+    When stardoc "build" is run on the source directory with ""
+    And the theme is applied to the workspace
+    Then the workspace should have "src/assets/logo.svg"
+    And the workspace should have "public/favicon.ico"
+    And the workspace should have "src/styles/01-custom.css"
+    And astro.config.mjs should contain:
+      """
+            logo: { src: './src/assets/logo.svg' },
+            favicon: '/favicon.ico',
+      """
+    And astro.config.mjs should contain:
+      """
+            customCss: ['./src/styles/01-custom.css'],
+      """
+
+  Scenario: Paths from the config file win over conventional files
+    Given the source directory has a file "logo.svg"
+    And the source directory has a file "custom.css"
+    And the source directory has a file "brand/mark.png"
+    And the source directory has a file "brand/icon.png"
+    And the source directory has a file "brand/fonts.css"
+    And the source directory has a file "styles/custom.css"
+    And a project config file ".flashdoc.yaml" with:
+      """
+      logo: brand/mark.png
+      favicon: brand/icon.png
+      customCss:
+        - brand/fonts.css
+        - styles/custom.css
+      """
+    When stardoc "build" is run on the source directory with ""
+    And the theme is applied to the workspace
+    Then astro.config.mjs should contain:
+      """
+            logo: { src: './src/assets/mark.png' },
+            favicon: '/icon.png',
+      """
+    And astro.config.mjs should contain:
+      """
+            customCss: ['./src/styles/01-fonts.css', './src/styles/02-custom.css'],
+      """
+    And the workspace should not have "src/assets/logo.svg"
+
+  Scenario: An accent color preset sets Starlight's color variables
+    When stardoc "build" is run on the source directory with "--accent-color teal"
+    And the theme is applied to the workspace
+    Then the workspace file "src/styles/flashdoc-accent.css" should contain:
+      """
+      :root[data-theme='light'] {
+        --sl-color-accent-low: #cfeae7;
+        --sl-color-accent: #0d9488;
+        --sl-color-accent-high: #06433d;
+      }
+      """
+    And astro.config.mjs should contain:
+      """
+            customCss: ['./src/styles/flashdoc-accent.css'],
+      """
+
+  Scenario: The accent color is loaded before custom stylesheets
+    Given the source directory has a file "custom.css"
+    And a project config file ".flashdoc.yaml" with:
+      """
+      accentColor: '#f60'
+      """
+    When stardoc "build" is run on the source directory with ""
+    And the theme is applied to the workspace
+    Then the workspace file "src/styles/flashdoc-accent.css" should contain:
+      """
+        --sl-color-accent: #ff6600;
+      """
+    And astro.config.mjs should contain:
+      """
+            customCss: ['./src/styles/flashdoc-accent.css', './src/styles/01-custom.css'],
+      """
+
+  Scenario: The flag overrides the accent color from the config file
+    Given a project config file ".flashdoc.yaml" with:
+      """
+      accentColor: green
+      """
+    When stardoc "serve" is run on the source directory with "--accent-color red"
+    Then the parsed accent color should be "red"
+
+  Scenario: Unknown accent colors are rejected
+    Then running stardoc with arguments "build . --accent-color mauve" should fail with "(use a hex color like #2563eb or one of: amber, blue, cyan, green, indigo, orange, pink, purple, red, slate, teal)"
+
+  Scenario: Unknown accent colors in the config file are reported
+    Given a project config file ".flashdoc.yaml" with:
+      """
+      accentColor: '#12345'
+      """
+    When the project config is loaded
+    Then loading the project config should fail with ".flashdoc.yaml:1: accentColor: must be a hex color or one of amber, blue, cyan"
+
+  Scenario: The native renderer uses the theme
+    Given the source file "index.md" is changed to:
+      """
+      # Welcome
+      """
+    And the source directory has a file "favicon.svg"
+    And the source directory has a file "custom.css" with:
+      """
+      .content { max-width: 60rem; }
+      """
+    When stardoc "build" is run on the source directory with "--accent-color green"
+    And files are processed with a public directory
+    And the processed docs are rendered natively with the theme
+    Then the rendered file "_flashdoc/favicon.svg" should exist
+    And the rendered page "index.html" should contain:
+      """
+      <link rel="icon" href="/_flashdoc/favicon.svg">
+      """
+    And the rendered page "_flashdoc/style.css" should contain:
+      """
+      --accent: #16a34a;
+      .content { max-width: 60rem; }
+      """
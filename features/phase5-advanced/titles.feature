@@ -0,0 +1,154 @@
+Feature: Page Titles
+  As a flashdoc user whose pages start with a heading
+  I want the page title taken from that heading
+  So that Starlight shows the title I wrote, and only once
+
+  Background:
+    Given the stardoc CLI is available
+    And a source directory "./docs" exists
+    And a temp workspace exists at "/tmp/stardoc-titles"
+    And the source file "index.md" is changed to:
+      """
+      Welcome.
+      """
+
+  Scenario: The first H1 becomes the title and is removed from the body
+    Given the source file "getting-started.md" is changed to:
+      """
+      # Installing flashdoc
+
+      Run the installer.
+
+      # Not the title
+      """
+    When files are processed with a public directory
+    Then the target file "getting-started.md" should contain in order:
+      """
+      ---
+      title: Installing flashdoc
+      ---
+      Run the installer.
+      # Not the title
+      """
+    And the target file "getting-started.md" should not contain "# Installing flashdoc"
+
+  Scenario: Setext headings and inline markup are understood
+    Given the source file "api.md" is changed to:
+      """
+      The **`flashdoc`** [API](./api.md)
+      ==================================
+
+      Reference.
+      """
+    When files are processed with a public directory
+    Then the target file "api.md" should contain "title: The flashdoc API"
+    And the target file "api.md" should not contain "==="
+
+  Scenario: Pages that don't open with an H1 keep the filename title
+    Given the source file "faq.md" is changed to:
+      """
+      Intro text.
+
+      # Questions
+      """
+    When files are processed with a public directory
+    Then the target file "faq.md" should contain "title: Faq"
+    And the target file "faq.md" should contain "# Questions"
+
+  Scenario: The filename strategy leaves the body alone
+    Given the source file "getting-started.md" is changed to:
+      """
+      # Installing flashdoc
+
+      Run the installer.
+      """
+    When files are processed with the title strategy "filename"
+    Then the target file "getting-started.md" should contain "title: Getting Started"
+    And the target file "getting-started.md" should contain "# Installing flashdoc"
+
+  Scenario: The both strategy uses the filename title as the sidebar label
+    Given the source file "getting-started.md" is changed to:
+      """
+      # Installing flashdoc
+
+      Run the installer.
+      """
+    When files are processed with the title strategy "both"
+    Then the target file "getting-started.md" should contain in order:
+      """
+      title: Installing flashdoc
+      sidebar:
+        label: Getting Started
+      """
+
+  Scenario: An H1 repeating the frontmatter title is removed
+    Given the source file "guide.md" is changed to:
+      """
+      ---
+      title: User Guide
+      ---
+      # User Guide
+
+      Read on.
+      """
+    When files are processed with a public directory
+    Then the target file "guide.md" should not contain "# User Guide"
+    And the target file "guide.md" should contain "Read on."
+
+  Scenario: An H1 differing from the frontmatter title is kept
+    Given the source file "guide.md" is changed to:
+      """
+      ---
+      title: User Guide
+      ---
+      # Overview
+      """
+    When files are processed with a public directory
+    Then the target file "guide.md" should contain "# Overview"
+
+  Scenario: MDX pages take the H1 below their imports
+    Given the source file "cards.mdx" is changed to:
+      """
+      import { Card } from '@astrojs/starlight/components';
+
+      # Feature Cards
+
+      <Card title="Fast">Builds in seconds.</Card>
+      """
+    When files are processed with a public directory
+    Then the target file "cards.mdx" should contain in order:
+      """
+      ---
+      title: Feature Cards
+      ---
+      import { Card } from '@astrojs/starlight/components';
+      <Card title="Fast">
+      """
+    And the target file "cards.mdx" should not contain "# Feature Cards"
+
+  Scenario: The title strategy comes from the config file
+    Given a project config file ".flashdoc.yaml" with:
+      """
+      titles: filename
+      """
+    When stardoc "build" is run on the source directory with ""
+    Then the parsed title strategy should be "filename"
+
+  Scenario: The --titles flag overrides the config file
+    Given a project config file ".flashdoc.yaml" with:
+      """
+      titles: filename
+      """
+    When stardoc "build" is run on the source directory with "--titles both"
+    Then the parsed title strategy should be "both"
+
+  Scenario: Unknown title strategies are rejected
+    Then running stardoc with arguments "build . --titles heading" should fail with "invalid title strategy"
+
+  Scenario: Unknown title strategies in the config file are reported
+    Given a project config file ".flashdoc.yaml" with:
+      """
+      titles: heading
+      """
+    When the project config is loaded
+    Then loading the project config should fail with ".flashdoc.yaml:1: titles: must be one of h1, filename, both"
@@ -0,0 +1,132 @@
+Feature: Watch Mode
+  As a flashdoc user
+  I want the site to rebuild when I edit my markdown files
+  So that I can preview changes without restarting flashdoc
+
+  Background:
+    Given the stardoc CLI is available
+    And a source directory "./docs" exists
+    And a temp workspace exists at "/tmp/stardoc-watch"
+    And the source directory contains:
+      """
+      ./docs/
+      ├── README.md
+      └── guides/
+          └── setup.md
+      """
+    And files are processed and copied
+
+  Scenario: Re-process a modified file
+    When the source file "guides/setup.md" is changed to:
+      """
+      # Setup
+
+      Updated content.
+      """
+    And the change to "guides/setup.md" is synced
+    Then the target file "guides/setup.md" should contain "Updated content."
+
+  Scenario: Process a newly added file
+    When the source file "guides/advanced.md" is changed to:
+      """
+      # Advanced
+
+      A brand new page.
+      """
+    And the change to "guides/advanced.md" is synced
+    Then the target file "guides/advanced.md" should contain "A brand new page."
+    And the target file "guides/advanced.md" should contain "title: Advanced"
+
+  Scenario: README changes update the index page
+    When the source file "README.md" is changed to:
+      """
+      # Welcome back
+      """
+    And the change to "README.md" is synced
+    Then the target file "index.md" should contain "Welcome back"
+
+  Scenario: Fixed problems are no longer reported
+    Given the source file "guides/setup.md" is changed to:
+      """
+      ---
+      title: 2024
+      ---
+      ![Diagram](diagram.png)
+      """
+    And files are processed with a public directory
+    When the source file "guides/setup.md" is changed to:
+      """
+      # Setup
+      """
+    And the change to "guides/setup.md" is synced by the same processor
+    Then no missing assets should be reported
+    And no invalid frontmatter should be reported
+
+  Scenario: Problems of removed pages are no longer reported
+    Given the source file "guides/setup.md" is changed to:
+      """
+      ![Diagram](diagram.png)
+      """
+    And files are processed with a public directory
+    When the source path "guides" is deleted
+    And the change to "guides" is synced by the same processor
+    Then no missing assets should be reported
+
+  Scenario: Remove a deleted file
+    When the source path "guides/setup.md" is deleted
+    And the change to "guides/setup.md" is synced
+    Then the target path "guides/setup.md" should not exist
+
+  Scenario: Remove a deleted directory
+    When the source path "guides" is deleted
+    And the change to "guides" is synced
+    Then the target path "guides" should not exist
+
+  Scenario: Watcher reports changed markdown files
+    Given the source directory is being watched
+    When the source file "guides/setup.md" is changed to:
+      """
+      # Setup again
+      """
+    Then the watcher should report a change covering "guides/setup.md"
+
+  Scenario: Watcher ignores non-markdown files
+    Given the source directory is being watched
+    When the source file "notes.txt" is changed to:
+      """
+      scratch notes
+      """
+    And the source file "guides/setup.md" is changed to:
+      """
+      # Setup again
+      """
+    Then the watcher should report a change covering "guides/setup.md"
+    And the watcher should not report "notes.txt"
+
+  Scenario: Watcher picks up files in new directories
+    Given the source directory is being watched
+    When the source file "api/auth.md" is changed to:
+      """
+      # Auth
+      """
+    Then the watcher should report a change covering "api/auth.md"
+
+  Scenario: Live reload script is injected into served pages
+    Given a built site containing "index.html"
+    And the static server is running with live reload
+    When I request "/" from the static server
+    Then the response should contain the live reload script
+
+  Scenario: Non-HTML assets are served untouched
+    Given a built site containing "index.html"
+    And the built site contains "style.css" with content "body {}"
+    And the static server is running with live reload
+    When I request "/style.css" from the static server
+    Then the response should not contain the live reload script
+
+  Scenario: Browsers are notified when the site is rebuilt
+    Given a built site containing "index.html"
+    And the static server is running with live reload
+    And a browser is listening for reload events
+    When the static server triggers a reload
+    Then the browser should receive a reload event
@@ -0,0 +1,100 @@
+package steps
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cucumber/godog"
+	"github.com/heidene/flashdoc/internal/processor"
+)
+
+// RegisterAssetsSteps registers all static asset and link rewriting step definitions
+func RegisterAssetsSteps(sc *godog.ScenarioContext, ctx *TestContext) {
+	sc.Step(`^a source asset "([^"]*)"$`, ctx.aSourceAsset)
+	sc.Step(`^the source asset "([^"]*)" is replaced with "([^"]*)"$`, ctx.sourceAssetIsReplacedWith)
+	sc.Step(`^files are processed with a public directory$`, ctx.filesAreProcessedWithPublicDirectory)
+	sc.Step(`^the change to "([^"]*)" is synced by the same processor$`, ctx.changeIsSyncedBySameProcessor)
+
+	sc.Step(`^the asset "([^"]*)" should be copied next to the pages$`, ctx.assetShouldBeCopiedNextToPages)
+	sc.Step(`^the asset "([^"]*)" should be copied to the public directory$`, ctx.assetShouldBeCopiedToPublicDirectory)
+	sc.Step(`^a missing asset "([^"]*)" should be reported at "([^"]*)"$`, ctx.missingAssetShouldBeReportedAt)
+	sc.Step(`^no missing assets should be reported$`, ctx.noMissingAssetsShouldBeReported)
+
+	sc.Step(`^the route for "([^"]*)" is computed$`, ctx.routeIsComputed)
+	sc.Step(`^the route should be "([^"]*)"$`, ctx.routeShouldBe)
+}
+
+func (ctx *TestContext) aSourceAsset(relPath string) error {
+	return ctx.sourceAssetIsReplacedWith(relPath, "\x89PNG fake asset data")
+}
+
+func (ctx *TestContext) sourceAssetIsReplacedWith(relPath, content string) error {
+	fullPath := filepath.Join(ctx.sourceDirectory, relPath)
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(fullPath, []byte(content), 0644)
+}
+
+// publicDirectory returns the public/ directory of the temp workspace
+func (ctx *TestContext) publicDirectory() string {
+	return filepath.Join(ctx.tempDir, "public")
+}
+
+func (ctx *TestContext) filesAreProcessedWithPublicDirectory() error {
+	ctx.processor = processor.NewWithOptions(ctx.sourceDirectory, ctx.targetDirectory, processor.Options{
+		PublicDir: ctx.publicDirectory(),
+	})
+	return ctx.processor.Process()
+}
+
+func (ctx *TestContext) changeIsSyncedBySameProcessor(relPath string) error {
+	return ctx.processor.Sync(relPath)
+}
+
+func (ctx *TestContext) assetShouldBeCopiedNextToPages(relPath string) error {
+	if _, err := os.Stat(filepath.Join(ctx.targetDirectory, relPath)); err != nil {
+		return fmt.Errorf("expected %s next to the pages: %w", relPath, err)
+	}
+	if _, err := os.Stat(filepath.Join(ctx.publicDirectory(), relPath)); err == nil {
+		return fmt.Errorf("did not expect %s in the public directory", relPath)
+	}
+	return nil
+}
+
+func (ctx *TestContext) assetShouldBeCopiedToPublicDirectory(relPath string) error {
+	if _, err := os.Stat(filepath.Join(ctx.publicDirectory(), relPath)); err != nil {
+		return fmt.Errorf("expected %s in the public directory: %w", relPath, err)
+	}
+	return nil
+}
+
+func (ctx *TestContext) missingAssetShouldBeReportedAt(target, location string) error {
+	for _, m := range ctx.processor.Missing() {
+		if m.Target == target && fmt.Sprintf("%s:%d", m.Page, m.Line) == location {
+			return nil
+		}
+	}
+	return fmt.Errorf("expected missing asset %s at %s, got %v", target, location, ctx.processor.Missing())
+}
+
+func (ctx *TestContext) noMissingAssetsShouldBeReported() error {
+	if missing := ctx.processor.Missing(); len(missing) > 0 {
+		return fmt.Errorf("expected no missing assets, got %v", missing)
+	}
+	return nil
+}
+
+func (ctx *TestContext) routeIsComputed(relPath string) error {
+	p := processor.New(ctx.sourceDirectory, ctx.targetDirectory)
+	ctx.route = p.Route(relPath)
+	return nil
+}
+
+func (ctx *TestContext) routeShouldBe(expected string) error {
+	if ctx.route != expected {
+		return fmt.Errorf("expected route %q, got %q", expected, ctx.route)
+	}
+	return nil
+}
@@ -0,0 +1,268 @@
+package steps
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cucumber/godog"
+	"github.com/heidene/flashdoc/internal/cli"
+	"github.com/heidene/flashdoc/internal/shared"
+	"github.com/heidene/flashdoc/internal/template"
+)
+
+// RegisterCacheSteps registers all cache management step definitions
+func RegisterCacheSteps(sc *godog.ScenarioContext, ctx *TestContext) {
+	sc.Step(`^the cache has a run "([^"]*)" last used (\d+) hours? ago$`, ctx.cacheHasRunLastUsedHoursAgo)
+	sc.Step(`^the run "([^"]*)" is in use$`, ctx.runIsInUse)
+	sc.Step(`^the run "([^"]*)" belonged to a process that has exited$`, ctx.runBelongedToExitedProcess)
+	sc.Step(`^the shared install was done with "([^"]*)"$`, ctx.sharedInstallWasDoneWith)
+	sc.Step(`^an install holds the lock$`, ctx.installHoldsTheLock)
+
+	sc.Step(`^the cache info is collected$`, ctx.cacheInfoIsCollected)
+	sc.Step(`^runs older than (\d+) hours are pruned$`, ctx.runsOlderThanHoursArePruned)
+	sc.Step(`^the cache is cleared$`, ctx.cacheIsCleared)
+
+	sc.Step(`^the cache info should show (\d+) runs? with (\d+) in use$`, ctx.cacheInfoShouldShowRuns)
+	sc.Step(`^the cache info should show a current install by "([^"]*)"$`, ctx.cacheInfoShouldShowCurrentInstallBy)
+	sc.Step(`^the run "([^"]*)" should still exist$`, ctx.runShouldStillExist)
+	sc.Step(`^the run "([^"]*)" should be removed$`, ctx.runShouldBeRemoved)
+	sc.Step(`^the shared install should still exist$`, ctx.sharedInstallShouldStillExist)
+	sc.Step(`^the shared install should be removed$`, ctx.sharedInstallShouldBeRemoved)
+	sc.Step(`^the parsed cache command should be "([^"]*)" older than "([^"]*)"$`, ctx.parsedCacheCommandShouldBeOlderThan)
+	sc.Step(`^running stardoc with arguments "([^"]*)" should fail with "([^"]*)"$`, ctx.runningStardocWithArgumentsShouldFailWith)
+}
+
+// cacheManager returns the scenario's shared manager with its directories created
+func (ctx *TestContext) cacheManager() (*shared.Manager, error) {
+	manager, err := ctx.sharedManager()
+	if err != nil {
+		return nil, err
+	}
+	return manager, manager.EnsureDirectories()
+}
+
+// runPath returns the directory of a run in the scenario's cache
+func (ctx *TestContext) runPath(id string) (string, error) {
+	manager, err := ctx.sharedManager()
+	if err != nil {
+		return "", err
+	}
+	return manager.GetRunDir(id), nil
+}
+
+func (ctx *TestContext) cacheHasRunLastUsedHoursAgo(id string, hours int) error {
+	runDir, err := ctx.runPath(id)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Join(runDir, "src", "content", "docs"), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(runDir, "src", "content", "docs", "index.md"), []byte("# Index\n"), 0644); err != nil {
+		return err
+	}
+	when := time.Now().Add(-time.Duration(hours) * time.Hour)
+	return os.Chtimes(runDir, when, when)
+}
+
+// writeOwner writes a run's owner file without touching the run's modification time
+func writeOwner(runDir, owner string) error {
+	stat, err := os.Stat(runDir)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(runDir, shared.OwnerFile), []byte(owner), 0644); err != nil {
+		return err
+	}
+	return os.Chtimes(runDir, stat.ModTime(), stat.ModTime())
+}
+
+func (ctx *TestContext) runIsInUse(id string) error {
+	runDir, err := ctx.runPath(id)
+	if err != nil {
+		return err
+	}
+	hostname, _ := os.Hostname()
+	return writeOwner(runDir, fmt.Sprintf("%d %s\n", os.Getpid(), hostname))
+}
+
+func (ctx *TestContext) runBelongedToExitedProcess(id string) error {
+	runDir, err := ctx.runPath(id)
+	if err != nil {
+		return err
+	}
+
+	// Start and reap a short-lived process to get the PID of a process that is gone
+	cmd := exec.Command("sleep", "0")
+	if err := cmd.Run(); err != nil {
+		return err
+	}
+	hostname, _ := os.Hostname()
+	return writeOwner(runDir, fmt.Sprintf("%d %s\n", cmd.Process.Pid, hostname))
+}
+
+func (ctx *TestContext) sharedInstallWasDoneWith(pm string) error {
+	manager, err := ctx.cacheManager()
+	if err != nil {
+		return err
+	}
+
+	modules := filepath.Join(manager.GetSharedDir(), "node_modules", "astro")
+	if err := os.MkdirAll(modules, 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(modules, "package.json"), []byte(`{"name":"astro"}`), 0644); err != nil {
+		return err
+	}
+
+	hash, err := template.GetEmbeddedPackageHash()
+	if err != nil {
+		return err
+	}
+	if err := manager.SaveVersion(hash); err != nil {
+		return err
+	}
+	return manager.SavePackageManager(pm)
+}
+
+func (ctx *TestContext) installHoldsTheLock() error {
+	manager, err := ctx.cacheManager()
+	if err != nil {
+		return err
+	}
+	if err := manager.AcquireLock(time.Second); err != nil {
+		return err
+	}
+	ctx.lockHolder = manager
+	return nil
+}
+
+func (ctx *TestContext) cacheInfoIsCollected() error {
+	manager, err := ctx.cacheManager()
+	if err != nil {
+		return err
+	}
+	ctx.cacheInfo, err = manager.Info()
+	return err
+}
+
+func (ctx *TestContext) runsOlderThanHoursArePruned(hours int) error {
+	manager, err := ctx.cacheManager()
+	if err != nil {
+		return err
+	}
+	_, err = manager.PruneRuns(time.Duration(hours) * time.Hour)
+	return err
+}
+
+func (ctx *TestContext) cacheIsCleared() error {
+	manager, err := ctx.cacheManager()
+	if err != nil {
+		return err
+	}
+	_, err = manager.Clear()
+	return err
+}
+
+func (ctx *TestContext) cacheInfoShouldShowRuns(total, inUse int) error {
+	used := 0
+	for _, run := range ctx.cacheInfo.Runs {
+		if run.InUse {
+			used++
+		}
+	}
+	if len(ctx.cacheInfo.Runs) != total || used != inUse {
+		return fmt.Errorf("expected %d runs with %d in use, got %d with %d in use", total, inUse, len(ctx.cacheInfo.Runs), used)
+	}
+	return nil
+}
+
+func (ctx *TestContext) cacheInfoShouldShowCurrentInstallBy(pm string) error {
+	hash, err := template.GetEmbeddedPackageHash()
+	if err != nil {
+		return err
+	}
+	info := ctx.cacheInfo
+	if !info.Installed || info.TemplateHash != hash || info.PackageManager != pm {
+		return fmt.Errorf("expected a current install by %s, got installed=%v hash=%q pm=%q",
+			pm, info.Installed, info.TemplateHash, info.PackageManager)
+	}
+	if info.SharedSize == 0 {
+		return fmt.Errorf("expected the shared install size to be measured")
+	}
+	return nil
+}
+
+func (ctx *TestContext) runShouldStillExist(id string) error {
+	runDir, err := ctx.runPath(id)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(runDir); err != nil {
+		return fmt.Errorf("expected run %s to be kept: %w", id, err)
+	}
+	return nil
+}
+
+func (ctx *TestContext) runShouldBeRemoved(id string) error {
+	runDir, err := ctx.runPath(id)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(runDir); !os.IsNotExist(err) {
+		return fmt.Errorf("expected run %s to be removed", id)
+	}
+	return nil
+}
+
+func (ctx *TestContext) sharedInstallShouldStillExist() error {
+	manager, err := ctx.sharedManager()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(manager.GetSharedDir(), "node_modules")); err != nil {
+		return fmt.Errorf("expected the shared install to be kept: %w", err)
+	}
+	return nil
+}
+
+func (ctx *TestContext) sharedInstallShouldBeRemoved() error {
+	manager, err := ctx.sharedManager()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(manager.GetSharedDir()); !os.IsNotExist(err) {
+		return fmt.Errorf("expected the shared install to be removed")
+	}
+	return nil
+}
+
+func (ctx *TestContext) parsedCacheCommandShouldBeOlderThan(action, age string) error {
+	cache, ok := ctx.cliCommand.(*cli.CacheConfig)
+	if !ok {
+		return fmt.Errorf("expected a cache command, got %T", ctx.cliCommand)
+	}
+	expected, err := time.ParseDuration(age)
+	if err != nil {
+		return err
+	}
+	if cache.Action != action || cache.OlderThan != expected {
+		return fmt.Errorf("expected cache %s older than %s, got %s older than %s", action, expected, cache.Action, cache.OlderThan)
+	}
+	return nil
+}
+
+func (ctx *TestContext) runningStardocWithArgumentsShouldFailWith(args, expected string) error {
+	_, err := cli.Parse(strings.Fields(args))
+	if err == nil {
+		return fmt.Errorf("expected %q to fail", args)
+	}
+	if !strings.Contains(err.Error(), expected) {
+		return fmt.Errorf("expected error containing %q, got %v", expected, err)
+	}
+	return nil
+}
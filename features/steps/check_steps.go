@@ -0,0 +1,109 @@
+package steps
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/cucumber/godog"
+	"github.com/heidene/flashdoc/internal/checker"
+	"github.com/heidene/flashdoc/internal/cli"
+	"github.com/heidene/flashdoc/internal/ignore"
+)
+
+// RegisterCheckSteps registers all link checker step definitions
+func RegisterCheckSteps(sc *godog.ScenarioContext, ctx *TestContext) {
+	sc.Step(`^the docs are checked$`, ctx.docsAreChecked)
+	sc.Step(`^the parsed check is run$`, ctx.parsedCheckIsRun)
+	sc.Step(`^the check report is written as "([^"]*)"$`, ctx.checkReportIsWrittenAs)
+	sc.Step(`^stardoc is run with check arguments "([^"]*)"$`, ctx.stardocIsRunWithCheckArguments)
+
+	sc.Step(`^the check should pass$`, ctx.checkShouldPass)
+	sc.Step(`^the check should report "([^"]*)" as "([^"]*)"$`, ctx.checkShouldReportAs)
+	sc.Step(`^the check should report (\d+) problems?$`, ctx.checkShouldReportProblems)
+	sc.Step(`^the check output should contain "(.*)"$`, ctx.checkOutputShouldContain)
+	sc.Step(`^the parsed command should be "([^"]*)" with format "([^"]*)"$`, ctx.parsedCommandShouldBeWithFormat)
+}
+
+func (ctx *TestContext) docsAreChecked() error {
+	report, err := checker.New(ctx.sourceDirectory).Run()
+	if err != nil {
+		return err
+	}
+	ctx.checkReport = report
+	return nil
+}
+
+// parsedCheckIsRun runs the checker the way the parsed check command does
+func (ctx *TestContext) parsedCheckIsRun() error {
+	check, ok := ctx.cliCommand.(*cli.CheckConfig)
+	if !ok {
+		return fmt.Errorf("expected a check command, got %#v", ctx.cliCommand)
+	}
+	matcher, err := ignore.Load(check.SourceDir, ignore.Options{Exclude: check.Exclude, Include: check.Include})
+	if err != nil {
+		return err
+	}
+
+	c := checker.New(check.SourceDir)
+	c.SetIgnore(matcher)
+	c.SetTitles(check.Titles)
+	c.SetIncludes(check.Includes)
+	c.SetAdmonitions(check.Admonitions)
+	ctx.checkReport, err = c.Run()
+	return err
+}
+
+func (ctx *TestContext) checkReportIsWrittenAs(format string) error {
+	var buf bytes.Buffer
+	if err := checker.Write(&buf, ctx.checkReport, format); err != nil {
+		return err
+	}
+	ctx.checkOutput = buf.String()
+	return nil
+}
+
+func (ctx *TestContext) stardocIsRunWithCheckArguments(flags string) error {
+	return ctx.stardocCommandIsRunOnSourceDirectoryWith("check", flags)
+}
+
+func (ctx *TestContext) checkShouldPass() error {
+	if !ctx.checkReport.OK() {
+		return fmt.Errorf("expected no problems, got %v", ctx.checkReport.Problems)
+	}
+	return nil
+}
+
+func (ctx *TestContext) checkShouldReportAs(location, kind string) error {
+	for _, p := range ctx.checkReport.Problems {
+		if fmt.Sprintf("%s:%d", p.File, p.Line) == location {
+			if p.Kind != kind {
+				return fmt.Errorf("expected %s to be reported as %s, got %s", location, kind, p.Kind)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("no problem reported at %s (got %v)", location, ctx.checkReport.Problems)
+}
+
+func (ctx *TestContext) checkShouldReportProblems(count int) error {
+	if len(ctx.checkReport.Problems) != count {
+		return fmt.Errorf("expected %d problems, got %v", count, ctx.checkReport.Problems)
+	}
+	return nil
+}
+
+func (ctx *TestContext) checkOutputShouldContain(expected string) error {
+	if !strings.Contains(ctx.checkOutput, expected) {
+		return fmt.Errorf("expected check output to contain %q:\n%s", expected, ctx.checkOutput)
+	}
+	return nil
+}
+
+func (ctx *TestContext) parsedCommandShouldBeWithFormat(command, format string) error {
+	check, ok := ctx.cliCommand.(*cli.CheckConfig)
+	if !ok || check.Name() != command || check.Format != format {
+		return fmt.Errorf("expected command %q with format %q, got %#v", command, format, ctx.cliCommand)
+	}
+	return nil
+}
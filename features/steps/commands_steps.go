@@ -0,0 +1,109 @@
+package steps
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cucumber/godog"
+	"github.com/heidene/flashdoc/internal/cli"
+	"github.com/heidene/flashdoc/internal/doctor"
+	"github.com/heidene/flashdoc/internal/shared"
+)
+
+// RegisterCommandSteps registers all subcommand and doctor step definitions
+func RegisterCommandSteps(sc *godog.ScenarioContext, ctx *TestContext) {
+	sc.Step(`^stardoc is run with arguments "([^"]*)"$`, ctx.stardocIsRunWithArguments)
+	sc.Step(`^the doctor is run on the source directory$`, ctx.doctorIsRunOnSourceDirectory)
+
+	sc.Step(`^the parsed command should be "([^"]*)"$`, ctx.parsedCommandShouldBe)
+	sc.Step(`^no command should be parsed$`, ctx.noCommandShouldBeParsed)
+	sc.Step(`^the parsed source directory should be the source directory$`, ctx.parsedSourceDirectoryShouldBeSourceDirectory)
+	sc.Step(`^the doctor should report "([^"]*)" as "([^"]*)"$`, ctx.doctorShouldReportAs)
+	sc.Step(`^the doctor should find problems$`, ctx.doctorShouldFindProblems)
+}
+
+func (ctx *TestContext) stardocIsRunWithArguments(args string) error {
+	return ctx.parseCommandLine(strings.Fields(args))
+}
+
+// sharedManager returns a shared manager whose ~/.stardoc lives in the scenario's temp directory
+func (ctx *TestContext) sharedManager() (*shared.Manager, error) {
+	home := os.Getenv("HOME")
+	os.Setenv("HOME", ctx.tempDir)
+	defer os.Setenv("HOME", home)
+	return shared.NewManager()
+}
+
+func (ctx *TestContext) doctorIsRunOnSourceDirectory() error {
+	manager, err := ctx.sharedManager()
+	if err != nil {
+		return err
+	}
+
+	ctx.doctorResults = doctor.New(manager, ctx.sourceDirectory).Run()
+	return nil
+}
+
+func (ctx *TestContext) parsedCommandShouldBe(expected string) error {
+	if ctx.cliCommand == nil {
+		return fmt.Errorf("expected command %q, got none", expected)
+	}
+	if ctx.cliCommand.Name() != expected {
+		return fmt.Errorf("expected command %q, got %q", expected, ctx.cliCommand.Name())
+	}
+	return nil
+}
+
+func (ctx *TestContext) noCommandShouldBeParsed() error {
+	if ctx.cliCommand != nil {
+		return fmt.Errorf("expected no command, got %q", ctx.cliCommand.Name())
+	}
+	return nil
+}
+
+func (ctx *TestContext) parsedSourceDirectoryShouldBeSourceDirectory() error {
+	var sourceDir string
+	switch cmd := ctx.cliCommand.(type) {
+	case *cli.CheckConfig:
+		sourceDir = cmd.SourceDir
+	case *cli.DoctorConfig:
+		sourceDir = cmd.SourceDir
+	default:
+		site, err := ctx.parsedSiteConfig()
+		if err != nil {
+			return err
+		}
+		sourceDir = site.SourceDir
+	}
+
+	if sourceDir != ctx.sourceDirectory {
+		return fmt.Errorf("expected source directory %q, got %q", ctx.sourceDirectory, sourceDir)
+	}
+	return nil
+}
+
+func (ctx *TestContext) doctorShouldReportAs(name, status string) error {
+	statuses := map[string]doctor.Status{"ok": doctor.OK, "warning": doctor.Warning, "failed": doctor.Failed}
+	expected, ok := statuses[status]
+	if !ok {
+		return fmt.Errorf("unknown status %q", status)
+	}
+
+	for _, r := range ctx.doctorResults {
+		if r.Name == name {
+			if r.Status != expected {
+				return fmt.Errorf("expected %s to be %s, got %s (%s)", name, status, r.Status, r.Detail)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("doctor did not check %s", name)
+}
+
+func (ctx *TestContext) doctorShouldFindProblems() error {
+	if doctor.Healthy(ctx.doctorResults) {
+		return fmt.Errorf("expected the doctor to find problems, got %v", ctx.doctorResults)
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package steps
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cucumber/godog"
+	"github.com/heidene/flashdoc/internal/cli"
+	"github.com/heidene/flashdoc/internal/config"
+	"github.com/heidene/flashdoc/internal/ignore"
+	"github.com/heidene/flashdoc/internal/processor"
+)
+
+// RegisterConfigFileSteps registers all project config file step definitions
+func RegisterConfigFileSteps(sc *godog.ScenarioContext, ctx *TestContext) {
+	sc.Step(`^a project config file "([^"]*)" with:$`, ctx.projectConfigFileWith)
+	sc.Step(`^the environment variable "([^"]*)" is "([^"]*)"$`, ctx.environmentVariableIs)
+	sc.Step(`^the project config is loaded$`, ctx.projectConfigIsLoaded)
+	sc.Step(`^files are processed with the project config$`, ctx.filesAreProcessedWithProjectConfig)
+	sc.Step(`^stardoc is run on the source directory with "([^"]*)"$`, ctx.stardocIsRunOnSourceDirectoryWith)
+	sc.Step(`^stardoc "([^"]*)" is run on the source directory with "([^"]*)"$`, ctx.stardocCommandIsRunOnSourceDirectoryWith)
+
+	sc.Step(`^the project config should have title "([^"]*)"$`, ctx.projectConfigShouldHaveTitle)
+	sc.Step(`^the project config should have port (\d+)$`, ctx.projectConfigShouldHavePort)
+	sc.Step(`^the project config should exclude "([^"]*)"$`, ctx.projectConfigShouldExclude)
+	sc.Step(`^the project config should have social link "([^"]*)" to "([^"]*)"$`, ctx.projectConfigShouldHaveSocialLink)
+	sc.Step(`^loading the project config should fail with "([^"]*)"$`, ctx.loadingProjectConfigShouldFailWith)
+	sc.Step(`^the project config error should suggest "([^"]*)"$`, ctx.projectConfigErrorShouldSuggest)
+	sc.Step(`^the resolved title should be "([^"]*)"$`, ctx.resolvedTitleShouldBe)
+	sc.Step(`^the resolved port should be (\d+)$`, ctx.resolvedPortShouldBe)
+	sc.Step(`^the resolved export path should end with "([^"]*)"$`, ctx.resolvedExportPathShouldEndWith)
+}
+
+func (ctx *TestContext) projectConfigFileWith(name, content string) error {
+	return os.WriteFile(filepath.Join(ctx.sourceDirectory, name), []byte(content), 0644)
+}
+
+func (ctx *TestContext) environmentVariableIs(name, value string) error {
+	if ctx.envVars == nil {
+		ctx.envVars = make(map[string]string)
+	}
+	ctx.envVars[name] = value
+	return nil
+}
+
+// lookupEnv reads environment overrides set by the scenario
+func (ctx *TestContext) lookupEnv(name string) (string, bool) {
+	value, ok := ctx.envVars[name]
+	return value, ok
+}
+
+func (ctx *TestContext) projectConfigIsLoaded() error {
+	cfg, err := config.Load(ctx.sourceDirectory)
+	if err == nil {
+		err = cfg.ApplyEnv(ctx.lookupEnv)
+	}
+	ctx.projectConfig = cfg
+	ctx.projectConfigErr = err
+	return nil
+}
+
+func (ctx *TestContext) filesAreProcessedWithProjectConfig() error {
+	if ctx.projectConfigErr != nil {
+		return ctx.projectConfigErr
+	}
+
+	matcher, err := ignore.Load(ctx.sourceDirectory, ignore.Options{Exclude: ctx.projectConfig.Exclude})
+	if err != nil {
+		return err
+	}
+	p := processor.NewWithOptions(ctx.sourceDirectory, ctx.targetDirectory, processor.Options{
+		Ignore:       matcher,
+		SidebarOrder: ctx.projectConfig.Sidebar,
+	})
+	return p.Process()
+}
+
+func (ctx *TestContext) stardocIsRunOnSourceDirectoryWith(flags string) error {
+	return ctx.parseCommandLine(append([]string{ctx.sourceDirectory}, strings.Fields(flags)...))
+}
+
+func (ctx *TestContext) stardocCommandIsRunOnSourceDirectoryWith(command, flags string) error {
+	return ctx.parseCommandLine(append([]string{command, ctx.sourceDirectory}, strings.Fields(flags)...))
+}
+
+// parseCommandLine parses args with the real CLI and stores the selected command
+func (ctx *TestContext) parseCommandLine(args []string) error {
+	// The CLI reads the real environment, so export the scenario's variables for this run
+	for name, value := range ctx.envVars {
+		os.Setenv(name, value)
+		defer os.Unsetenv(name)
+	}
+
+	cmd, logCfg, err := cli.ParseWithLogging(args)
+	if err != nil {
+		return fmt.Errorf("failed to parse arguments: %w", err)
+	}
+
+	ctx.cliCommand = cmd
+	ctx.logConfig = logCfg
+	return nil
+}
+
+// parsedSiteConfig returns the site settings of a parsed serve, build or export command
+func (ctx *TestContext) parsedSiteConfig() (*cli.SiteConfig, error) {
+	switch cmd := ctx.cliCommand.(type) {
+	case *cli.ServeConfig:
+		return &cmd.SiteConfig, nil
+	case *cli.BuildConfig:
+		return &cmd.SiteConfig, nil
+	case *cli.ExportConfig:
+		return &cmd.SiteConfig, nil
+	}
+	return nil, fmt.Errorf("expected a serve, build or export command, got %T", ctx.cliCommand)
+}
+
+// loadedProjectConfig returns the loaded config, failing if loading returned an error
+func (ctx *TestContext) loadedProjectConfig() (*config.File, error) {
+	if ctx.projectConfigErr != nil {
+		return nil, fmt.Errorf("failed to load project config: %w", ctx.projectConfigErr)
+	}
+	return ctx.projectConfig, nil
+}
+
+func (ctx *TestContext) projectConfigShouldHaveTitle(expected string) error {
+	cfg, err := ctx.loadedProjectConfig()
+	if err != nil {
+		return err
+	}
+	if cfg.Title != expected {
+		return fmt.Errorf("expected title %q, got %q", expected, cfg.Title)
+	}
+	return nil
+}
+
+func (ctx *TestContext) projectConfigShouldHavePort(expected int) error {
+	cfg, err := ctx.loadedProjectConfig()
+	if err != nil {
+		return err
+	}
+	if cfg.Port != expected {
+		return fmt.Errorf("expected port %d, got %d", expected, cfg.Port)
+	}
+	return nil
+}
+
+func (ctx *TestContext) projectConfigShouldExclude(pattern string) error {
+	cfg, err := ctx.loadedProjectConfig()
+	if err != nil {
+		return err
+	}
+	for _, exclude := range cfg.Exclude {
+		if exclude == pattern {
+			return nil
+		}
+	}
+	return fmt.Errorf("expected exclude patterns %v to contain %q", cfg.Exclude, pattern)
+}
+
+func (ctx *TestContext) projectConfigShouldHaveSocialLink(icon, href string) error {
+	cfg, err := ctx.loadedProjectConfig()
+	if err != nil {
+		return err
+	}
+	if cfg.Social[icon] != href {
+		return fmt.Errorf("expected social link %s to be %q, got %q", icon, href, cfg.Social[icon])
+	}
+	return nil
+}
+
+func (ctx *TestContext) loadingProjectConfigShouldFailWith(expected string) error {
+	if ctx.projectConfigErr == nil {
+		return fmt.Errorf("expected loading the project config to fail")
+	}
+	if !strings.Contains(ctx.projectConfigErr.Error(), expected) {
+		return fmt.Errorf("expected error to contain %q, got:\n%v", expected, ctx.projectConfigErr)
+	}
+	return nil
+}
+
+func (ctx *TestContext) projectConfigErrorShouldSuggest(key string) error {
+	return ctx.loadingProjectConfigShouldFailWith(fmt.Sprintf("did you mean %q?", key))
+}
+
+func (ctx *TestContext) resolvedTitleShouldBe(expected string) error {
+	site, err := ctx.parsedSiteConfig()
+	if err != nil {
+		return err
+	}
+	if site.Title != expected {
+		return fmt.Errorf("expected title %q, got %q", expected, site.Title)
+	}
+	return nil
+}
+
+func (ctx *TestContext) resolvedPortShouldBe(expected int) error {
+	serve, ok := ctx.cliCommand.(*cli.ServeConfig)
+	if !ok {
+		return fmt.Errorf("expected a serve command, got %T", ctx.cliCommand)
+	}
+	if serve.Port != expected {
+		return fmt.Errorf("expected port %d, got %d", expected, serve.Port)
+	}
+	return nil
+}
+
+func (ctx *TestContext) resolvedExportPathShouldEndWith(suffix string) error {
+	export, ok := ctx.cliCommand.(*cli.ExportConfig)
+	if !ok {
+		return fmt.Errorf("expected an export command, got %T", ctx.cliCommand)
+	}
+	if !strings.HasSuffix(filepath.ToSlash(export.OutputDir), suffix) {
+		return fmt.Errorf("expected export path ending in %q, got %q", suffix, export.OutputDir)
+	}
+	return nil
+}
@@ -5,10 +5,24 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"time"
 
+	"github.com/heidene/flashdoc/internal/checker"
+	"github.com/heidene/flashdoc/internal/cleanup"
+	"github.com/heidene/flashdoc/internal/cli"
+	"github.com/heidene/flashdoc/internal/config"
+	"github.com/heidene/flashdoc/internal/doctor"
+	"github.com/heidene/flashdoc/internal/logging"
 	"github.com/heidene/flashdoc/internal/pkgmanager"
+	"github.com/heidene/flashdoc/internal/processor"
+	"github.com/heidene/flashdoc/internal/progress"
+	"github.com/heidene/flashdoc/internal/runlog"
 	"github.com/heidene/flashdoc/internal/scanner"
 	"github.com/heidene/flashdoc/internal/server"
+	"github.com/heidene/flashdoc/internal/shared"
+	"github.com/heidene/flashdoc/internal/staticserver"
+	"github.com/heidene/flashdoc/internal/template"
+	"github.com/heidene/flashdoc/internal/watcher"
 	"github.com/heidene/flashdoc/internal/workspace"
 )
 
@@ -56,6 +70,8 @@ type TestContext struct {
 	configContent   string
 	detectedPM      pkgmanager.PackageManager
 	mockPMAvailable map[string]bool // For mocking availability
+	pmErr           error           // Result of the last package manager selection
+	binDir          string          // Only directory on the PATH while selecting a package manager
 	installOutput   string
 
 	// Phase 4: Server & Browser
@@ -79,6 +95,55 @@ type TestContext struct {
 	exportShouldFail bool
 	forbiddenPath    string
 	expectedTitle    string
+
+	// Phase 5: Advanced
+	watcher        *watcher.Watcher
+	watchedChanges []string
+	staticServer   *staticserver.Server
+	responseBody   string
+	reloadEvents   chan string
+	devServer      *fakeDevServer
+	cleanupMgr     *cleanup.Manager
+
+	// Project config file
+	projectConfig    *config.File
+	projectConfigErr error
+	envVars          map[string]string
+	cliCommand       cli.Command
+
+	// Doctor
+	doctorResults []doctor.Result
+
+	// Cache management
+	cacheInfo *shared.CacheInfo
+
+	// Install lock testing
+	lockHolder *shared.Manager // Manager holding the install lock for the scenario
+	lockErr    error           // Result of the last lock attempt
+	lockWait   time.Duration   // How long the last lock attempt waited
+
+	// Processor shared across steps (assets, links)
+	processor *processor.Processor
+	route     string
+
+	// Link checker
+	checkReport *checker.Report
+	checkOutput string
+
+	// Build log
+	buildLog *runlog.Log
+	buildErr error
+
+	// Starlight config generation
+	starlightConfig *template.StarlightConfig
+	starlightErr    error
+
+	// Logging
+	logConfig *cli.LogConfig
+	logger    *logging.Logger
+	logOut    *bytes.Buffer
+	logErr    *bytes.Buffer
+	spinner   *progress.Spinner
 }
 
 // NewTestContext creates a new test context
@@ -139,6 +204,50 @@ func (ctx *TestContext) Reset() {
 	ctx.browserCommand = ""
 	ctx.outputLines = make([]string, 0)
 
+	// Phase 5 fields
+	if ctx.watcher != nil {
+		_ = ctx.watcher.Close()
+		ctx.watcher = nil
+	}
+	ctx.watchedChanges = nil
+	if ctx.staticServer != nil {
+		_ = ctx.staticServer.Stop()
+		ctx.staticServer = nil
+	}
+	ctx.responseBody = ""
+	ctx.reloadEvents = nil
+	ctx.devServer = nil
+	ctx.cleanupMgr = nil
+	ctx.projectConfig = nil
+	ctx.projectConfigErr = nil
+	ctx.envVars = nil
+	ctx.cliCommand = nil
+	ctx.doctorResults = nil
+	ctx.cacheInfo = nil
+	if ctx.lockHolder != nil {
+		_ = ctx.lockHolder.ReleaseLock()
+	}
+	ctx.lockHolder = nil
+	ctx.lockErr = nil
+	ctx.lockWait = 0
+	ctx.processor = nil
+	ctx.route = ""
+	ctx.checkReport = nil
+	ctx.checkOutput = ""
+	_ = ctx.buildLog.Close()
+	ctx.buildLog = nil
+	ctx.buildErr = nil
+	ctx.logConfig = nil
+	ctx.logger = nil
+	ctx.logOut = nil
+	ctx.logErr = nil
+	ctx.spinner = nil
+	ctx.starlightConfig = nil
+	ctx.starlightErr = nil
+	ctx.pmErr = nil
+	ctx.binDir = ""
+	logging.SetDefault(quietLogger())
+
 	// Clean up test files and directories
 	for _, file := range ctx.createdFiles {
 		os.Remove(file)
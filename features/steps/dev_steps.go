@@ -0,0 +1,117 @@
+package steps
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cucumber/godog"
+	"github.com/heidene/flashdoc/internal/cleanup"
+	"github.com/heidene/flashdoc/internal/server"
+)
+
+// RegisterDevSteps registers all dev mode step definitions
+func RegisterDevSteps(sc *godog.ScenarioContext, ctx *TestContext) {
+	sc.Step(`^the dev server starts and logs:$`, ctx.devServerStartsAndLogs)
+	sc.Step(`^the dev server starts and logs with colors:$`, ctx.devServerStartsAndLogsWithColors)
+	sc.Step(`^stardoc parses the output$`, ctx.stardocParsesTheOutput)
+	sc.Step(`^this URL should be used for opening the browser$`, ctx.thisURLShouldBeUsedForOpeningBrowser)
+	sc.Step(`^the requested port (\d+) should be reported as in use$`, ctx.requestedPortShouldBeReportedInUse)
+	sc.Step(`^no server URL should be detected$`, ctx.noServerURLShouldBeDetected)
+
+	sc.Step(`^a dev server is registered with the cleanup manager$`, ctx.devServerIsRegisteredWithCleanupManager)
+	sc.Step(`^cleanup is performed$`, ctx.cleanupIsPerformed)
+	sc.Step(`^the dev server should be stopped$`, ctx.devServerShouldBeStopped)
+	sc.Step(`^the workspace should be removed$`, ctx.workspaceShouldBeRemoved)
+}
+
+// fakeDevServer records whether cleanup stopped it
+type fakeDevServer struct {
+	stopped bool
+}
+
+func (f *fakeDevServer) Stop() error {
+	f.stopped = true
+	return nil
+}
+
+func (ctx *TestContext) devServerStartsAndLogs(output string) error {
+	ctx.outputLines = strings.Split(output, "\n")
+	return nil
+}
+
+func (ctx *TestContext) devServerStartsAndLogsWithColors(output string) error {
+	lines := strings.Split(output, "\n")
+	for i, line := range lines {
+		lines[i] = strings.Replace(line, "http://", "\x1b[36mhttp://", 1) + "\x1b[39m"
+	}
+	ctx.outputLines = lines
+	return nil
+}
+
+func (ctx *TestContext) stardocParsesTheOutput() error {
+	ctx.serverURL = ""
+	ctx.serverPort = 0
+
+	for _, line := range ctx.outputLines {
+		if port, ok := server.ParsePortInUse(line); ok {
+			ctx.serverPort = port
+		}
+		if url, ok := server.ParseLocalURL(line); ok {
+			ctx.serverURL = url
+		}
+	}
+
+	return nil
+}
+
+func (ctx *TestContext) thisURLShouldBeUsedForOpeningBrowser() error {
+	if ctx.serverURL == "" {
+		return fmt.Errorf("no server URL was detected")
+	}
+	return nil
+}
+
+func (ctx *TestContext) requestedPortShouldBeReportedInUse(port int) error {
+	if ctx.serverPort != port {
+		return fmt.Errorf("expected port %d to be reported in use, got %d", port, ctx.serverPort)
+	}
+	return nil
+}
+
+func (ctx *TestContext) noServerURLShouldBeDetected() error {
+	if ctx.serverURL != "" {
+		return fmt.Errorf("expected no server URL, got %q", ctx.serverURL)
+	}
+	return nil
+}
+
+func (ctx *TestContext) devServerIsRegisteredWithCleanupManager() error {
+	ws, err := ctx.CreateTestWorkspace()
+	if err != nil {
+		return err
+	}
+
+	ctx.devServer = &fakeDevServer{}
+	ctx.cleanupMgr = cleanup.New(ws)
+	ctx.cleanupMgr.RegisterServer(ctx.devServer)
+	return nil
+}
+
+func (ctx *TestContext) cleanupIsPerformed() error {
+	return ctx.cleanupMgr.Cleanup()
+}
+
+func (ctx *TestContext) devServerShouldBeStopped() error {
+	if !ctx.devServer.stopped {
+		return fmt.Errorf("dev server was not stopped during cleanup")
+	}
+	return nil
+}
+
+func (ctx *TestContext) workspaceShouldBeRemoved() error {
+	if _, err := os.Stat(ctx.workspacePath); !os.IsNotExist(err) {
+		return fmt.Errorf("workspace %s still exists", ctx.workspacePath)
+	}
+	return nil
+}
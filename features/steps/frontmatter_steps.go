@@ -38,6 +38,7 @@ func RegisterFrontmatterSteps(sc *godog.ScenarioContext, ctx *TestContext) {
 	sc.Step(`^the structure should remain valid YAML$`, ctx.structureShouldRemainValidYAML)
 	sc.Step(`^the frontmatter should be valid UTF-8$`, ctx.frontmatterShouldBeValidUTF8)
 	sc.Step(`^the "([^"]*)" extension should not appear in the title$`, ctx.extensionShouldNotAppearInTitle)
+	sc.Step(`^the target file "([^"]*)" should start with:$`, ctx.targetFileShouldStartWith)
 }
 
 func (ctx *TestContext) createMarkdownFileWithContent(filename, content string) error {
@@ -67,7 +68,7 @@ func (ctx *TestContext) createMarkdownFileWithName(filename string) error {
 	ctx.sourceDirectory = tempDir
 	filePath := filepath.Join(tempDir, filename)
 
-	content := "# Content\n\nThis is test content."
+	content := "This is test content."
 	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
 		return err
 	}
@@ -89,7 +90,7 @@ func (ctx *TestContext) createMarkdownFileInDirectory(filename, directory string
 	}
 
 	filePath := filepath.Join(dirPath, filename)
-	content := "# Content\n\nThis is test content."
+	content := "This is test content."
 	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
 		return err
 	}
@@ -419,3 +420,14 @@ func (ctx *TestContext) extensionShouldNotAppearInTitle(extension string) error
 
 	return nil
 }
+
+func (ctx *TestContext) targetFileShouldStartWith(relPath string, expected *godog.DocString) error {
+	data, err := os.ReadFile(filepath.Join(ctx.targetDirectory, relPath))
+	if err != nil {
+		return fmt.Errorf("failed to read target file %s: %w", relPath, err)
+	}
+	if !strings.HasPrefix(string(data), expected.Content) {
+		return fmt.Errorf("expected %s to start with:\n%s\n\nActual:\n%s", relPath, expected.Content, data)
+	}
+	return nil
+}
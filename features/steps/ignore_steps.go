@@ -0,0 +1,95 @@
+package steps
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cucumber/godog"
+	"github.com/heidene/flashdoc/internal/cli"
+	"github.com/heidene/flashdoc/internal/ignore"
+	"github.com/heidene/flashdoc/internal/processor"
+)
+
+// RegisterIgnoreSteps registers all ignore file step definitions
+func RegisterIgnoreSteps(sc *godog.ScenarioContext, ctx *TestContext) {
+	sc.Step(`^files are processed honoring ignore files$`, ctx.filesAreProcessedHonoringIgnoreFiles)
+	sc.Step(`^files are processed with the parsed ignore settings$`, ctx.filesAreProcessedWithParsedIgnoreSettings)
+
+	sc.Step(`^the path "([^"]*)" should be ignored$`, ctx.pathShouldBeIgnored)
+	sc.Step(`^the path "([^"]*)" should not be ignored$`, ctx.pathShouldNotBeIgnored)
+	sc.Step(`^the parsed excludes should be "([^"]*)"$`, ctx.parsedExcludesShouldBe)
+	sc.Step(`^the parsed includes should be "([^"]*)"$`, ctx.parsedIncludesShouldBe)
+}
+
+// processWithIgnore processes the source directory with ignore files and the given options
+func (ctx *TestContext) processWithIgnore(opts ignore.Options) error {
+	matcher, err := ignore.Load(ctx.sourceDirectory, opts)
+	if err != nil {
+		return err
+	}
+	p := processor.NewWithOptions(ctx.sourceDirectory, ctx.targetDirectory, processor.Options{Ignore: matcher})
+	return p.Process()
+}
+
+func (ctx *TestContext) filesAreProcessedHonoringIgnoreFiles() error {
+	return ctx.processWithIgnore(ignore.Options{})
+}
+
+func (ctx *TestContext) filesAreProcessedWithParsedIgnoreSettings() error {
+	site, err := ctx.parsedSiteConfig()
+	if err != nil {
+		return err
+	}
+	return ctx.processWithIgnore(ignore.Options{Exclude: site.Exclude, Include: site.Include})
+}
+
+func (ctx *TestContext) pathShouldBeIgnored(relPath string) error {
+	return ctx.checkIgnored(relPath, true)
+}
+
+func (ctx *TestContext) pathShouldNotBeIgnored(relPath string) error {
+	return ctx.checkIgnored(relPath, false)
+}
+
+// checkIgnored loads the source directory's ignore files and checks a file path against them
+func (ctx *TestContext) checkIgnored(relPath string, expected bool) error {
+	matcher, err := ignore.Load(ctx.sourceDirectory, ignore.Options{})
+	if err != nil {
+		return err
+	}
+	if ignored := matcher.Ignored(relPath, false); ignored != expected {
+		return fmt.Errorf("expected %s ignored to be %v, got %v", relPath, expected, ignored)
+	}
+	return nil
+}
+
+// parsedCheckConfig returns the parsed check command
+func (ctx *TestContext) parsedCheckConfig() (*cli.CheckConfig, error) {
+	check, ok := ctx.cliCommand.(*cli.CheckConfig)
+	if !ok {
+		return nil, fmt.Errorf("expected a check command, got %T", ctx.cliCommand)
+	}
+	return check, nil
+}
+
+func (ctx *TestContext) parsedExcludesShouldBe(expected string) error {
+	check, err := ctx.parsedCheckConfig()
+	if err != nil {
+		return err
+	}
+	if got := strings.Join(check.Exclude, ", "); got != expected {
+		return fmt.Errorf("expected excludes %q, got %q", expected, got)
+	}
+	return nil
+}
+
+func (ctx *TestContext) parsedIncludesShouldBe(expected string) error {
+	check, err := ctx.parsedCheckConfig()
+	if err != nil {
+		return err
+	}
+	if got := strings.Join(check.Include, ", "); got != expected {
+		return fmt.Errorf("expected includes %q, got %q", expected, got)
+	}
+	return nil
+}
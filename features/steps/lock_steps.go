@@ -0,0 +1,145 @@
+package steps
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cucumber/godog"
+	"github.com/heidene/flashdoc/internal/shared"
+)
+
+// lockWaitThreshold separates an immediate lock from one that waited for another install
+const lockWaitThreshold = 100 * time.Millisecond
+
+// RegisterLockSteps registers all install lock step definitions
+func RegisterLockSteps(sc *godog.ScenarioContext, ctx *TestContext) {
+	sc.Step(`^a lock file was left behind by a killed install$`, ctx.lockFileLeftBehind)
+	sc.Step(`^the running install finishes after (\d+) milliseconds$`, ctx.runningInstallFinishesAfter)
+	sc.Step(`^the running install finishes$`, ctx.runningInstallFinishes)
+	sc.Step(`^the install lock is broken$`, ctx.installLockIsBroken)
+	sc.Step(`^another install acquires the lock with a (\d+) seconds? timeout$`, ctx.anotherInstallAcquiresLock)
+
+	sc.Step(`^the lock should be acquired without waiting$`, ctx.lockShouldBeAcquiredWithoutWaiting)
+	sc.Step(`^the lock should be acquired after waiting$`, ctx.lockShouldBeAcquiredAfterWaiting)
+	sc.Step(`^the lock attempt should time out$`, ctx.lockAttemptShouldTimeOut)
+	sc.Step(`^the install lock should be held$`, ctx.installLockShouldBeHeld)
+	sc.Step(`^the install lock should not be held$`, ctx.installLockShouldNotBeHeld)
+	sc.Step(`^the parsed site config should break the lock$`, ctx.parsedSiteConfigShouldBreakLock)
+}
+
+func (ctx *TestContext) lockFileLeftBehind() error {
+	manager, err := ctx.cacheManager()
+	if err != nil {
+		return err
+	}
+	// The old O_EXCL lock wrote the installer's PID into the file
+	return os.WriteFile(manager.GetLockFilePath(), []byte("99999"), 0644)
+}
+
+func (ctx *TestContext) runningInstallFinishesAfter(ms int) error {
+	holder := ctx.lockHolder
+	if holder == nil {
+		return fmt.Errorf("no install holds the lock")
+	}
+	ctx.lockHolder = nil
+	time.AfterFunc(time.Duration(ms)*time.Millisecond, func() {
+		_ = holder.ReleaseLock()
+	})
+	return nil
+}
+
+func (ctx *TestContext) runningInstallFinishes() error {
+	if ctx.lockHolder == nil {
+		return fmt.Errorf("no install holds the lock")
+	}
+	err := ctx.lockHolder.ReleaseLock()
+	ctx.lockHolder = nil
+	return err
+}
+
+func (ctx *TestContext) installLockIsBroken() error {
+	manager, err := ctx.cacheManager()
+	if err != nil {
+		return err
+	}
+	return manager.BreakLock()
+}
+
+func (ctx *TestContext) anotherInstallAcquiresLock(seconds int) error {
+	manager, err := ctx.cacheManager()
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	ctx.lockErr = manager.AcquireLock(time.Duration(seconds) * time.Second)
+	ctx.lockWait = time.Since(start)
+	if ctx.lockErr == nil {
+		// A holder whose lock was broken still has the old file locked
+		if ctx.lockHolder != nil {
+			_ = ctx.lockHolder.ReleaseLock()
+		}
+		ctx.lockHolder = manager
+	}
+	return nil
+}
+
+func (ctx *TestContext) lockShouldBeAcquiredWithoutWaiting() error {
+	if ctx.lockErr != nil {
+		return fmt.Errorf("expected the lock to be acquired, got: %v", ctx.lockErr)
+	}
+	if ctx.lockWait >= lockWaitThreshold {
+		return fmt.Errorf("expected the lock to be acquired immediately, waited %s", ctx.lockWait)
+	}
+	return nil
+}
+
+func (ctx *TestContext) lockShouldBeAcquiredAfterWaiting() error {
+	if ctx.lockErr != nil {
+		return fmt.Errorf("expected the lock to be acquired, got: %v", ctx.lockErr)
+	}
+	if ctx.lockWait < lockWaitThreshold {
+		return fmt.Errorf("expected the lock to wait for the running install, waited %s", ctx.lockWait)
+	}
+	return nil
+}
+
+func (ctx *TestContext) lockAttemptShouldTimeOut() error {
+	if !errors.Is(ctx.lockErr, shared.ErrLockTimeout) {
+		return fmt.Errorf("expected %v, got: %v", shared.ErrLockTimeout, ctx.lockErr)
+	}
+	return nil
+}
+
+func (ctx *TestContext) installLockShouldBeHeld() error {
+	return ctx.checkLockHeld(true)
+}
+
+func (ctx *TestContext) installLockShouldNotBeHeld() error {
+	return ctx.checkLockHeld(false)
+}
+
+// checkLockHeld checks the lock state as seen by a separate flashdoc process
+func (ctx *TestContext) checkLockHeld(expected bool) error {
+	manager, err := ctx.cacheManager()
+	if err != nil {
+		return err
+	}
+	if held := manager.LockHeld(); held != expected {
+		return fmt.Errorf("expected lock held to be %v, got %v", expected, held)
+	}
+	return nil
+}
+
+func (ctx *TestContext) parsedSiteConfigShouldBreakLock() error {
+	site, err := ctx.parsedSiteConfig()
+	if err != nil {
+		return err
+	}
+	if !site.BreakLock {
+		return fmt.Errorf("expected --break-lock to be set")
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+package steps
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/cucumber/godog"
+	"github.com/heidene/flashdoc/internal/cli"
+	"github.com/heidene/flashdoc/internal/logging"
+	"github.com/heidene/flashdoc/internal/progress"
+)
+
+// RegisterLoggingSteps registers all logging step definitions
+func RegisterLoggingSteps(sc *godog.ScenarioContext, ctx *TestContext) {
+	sc.Step(`^a logger with the flags "([^"]*)"$`, ctx.loggerWithFlags)
+	sc.Step(`^a logger with colors forced on$`, ctx.loggerWithColorsForcedOn)
+	sc.Step(`^a logger writing to a pipe$`, ctx.loggerWritingToPipe)
+
+	sc.Step(`^flashdoc logs (debug|info|success|warning|error|result) "([^"]*)"$`, ctx.flashdocLogs)
+	sc.Step(`^flashdoc logs the result "([^"]*)" with the fields:$`, ctx.flashdocLogsResultWithFields)
+	sc.Step(`^a progress spinner for "([^"]*)" finishes with "([^"]*)"$`, ctx.progressSpinnerFinishesWith)
+
+	sc.Step(`^stdout should be:$`, ctx.logStdoutShouldBe)
+	sc.Step(`^stderr should be:$`, ctx.logStderrShouldBe)
+	sc.Step(`^(stdout|stderr) should be empty$`, ctx.logStreamShouldBeEmpty)
+	sc.Step(`^(stdout|stderr) should contain "([^"]*)"$`, ctx.logStreamShouldContain)
+	sc.Step(`^every (stdout|stderr) line should match "([^"]*)"$`, ctx.everyLogLineShouldMatch)
+	sc.Step(`^(stdout|stderr) should contain color codes$`, ctx.logStreamShouldContainColorCodes)
+	sc.Step(`^(stdout|stderr) should not contain color codes$`, ctx.logStreamShouldNotContainColorCodes)
+	sc.Step(`^(stdout|stderr) line (\d+) should be JSON with level "([^"]*)" and msg "([^"]*)"$`, ctx.logLineShouldBeJSON)
+	sc.Step(`^(stdout|stderr) line (\d+) should have the field "([^"]*)" set to (.+)$`, ctx.logLineShouldHaveField)
+	sc.Step(`^the logger should not be interactive$`, ctx.loggerShouldNotBeInteractive)
+
+	sc.Step(`^the parsed log config should be (quiet|verbose|timestamped)$`, ctx.parsedLogConfigShouldBe)
+	sc.Step(`^the parsed log format should be "([^"]*)"$`, ctx.parsedLogFormatShouldBe)
+}
+
+// quietLogger returns a logger that drops everything, for steps that don't check output
+func quietLogger() *logging.Logger {
+	return logging.New(logging.Options{Level: logging.LevelResult + 1, Out: io.Discard, Err: io.Discard})
+}
+
+// newLogger creates the scenario's logger on fresh buffers and makes it the default
+func (ctx *TestContext) newLogger(opts logging.Options) {
+	// The logger reads NO_COLOR from the real environment
+	for name, value := range ctx.envVars {
+		os.Setenv(name, value)
+		defer os.Unsetenv(name)
+	}
+
+	ctx.logOut, ctx.logErr = new(bytes.Buffer), new(bytes.Buffer)
+	opts.Out, opts.Err = ctx.logOut, ctx.logErr
+	ctx.logger = logging.New(opts)
+	logging.SetDefault(ctx.logger)
+}
+
+func (ctx *TestContext) loggerWithFlags(flags string) error {
+	_, logCfg, err := cli.ParseWithLogging(append([]string{"cache", "path"}, strings.Fields(flags)...))
+	if err != nil {
+		return err
+	}
+	ctx.newLogger(logging.Options{Level: logCfg.Level(), Format: logCfg.Format, Timestamps: logCfg.Timestamps})
+	return nil
+}
+
+func (ctx *TestContext) loggerWithColorsForcedOn() error {
+	color := true
+	ctx.newLogger(logging.Options{Color: &color})
+	return nil
+}
+
+func (ctx *TestContext) loggerWritingToPipe() error {
+	// A pipe is a real file, like stderr redirected by a script or CI, but not a terminal
+	r, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	_ = r.Close()
+	ctx.logger = logging.New(logging.Options{Err: w})
+	return w.Close()
+}
+
+func (ctx *TestContext) flashdocLogs(level, message string) error {
+	switch level {
+	case "debug":
+		ctx.logger.Debugf("%s", message)
+	case "info":
+		ctx.logger.Infof("%s", message)
+	case "success":
+		ctx.logger.Successf("%s", message)
+	case "warning":
+		ctx.logger.Warnf("%s", message)
+	case "error":
+		ctx.logger.Errorf("%s", message)
+	case "result":
+		ctx.logger.Resultf("%s", message)
+	}
+	return nil
+}
+
+func (ctx *TestContext) flashdocLogsResultWithFields(text string, fields *godog.DocString) error {
+	var values map[string]interface{}
+	if err := json.Unmarshal([]byte(fields.Content), &values); err != nil {
+		return fmt.Errorf("invalid fields: %w", err)
+	}
+	ctx.logger.Result(strings.ReplaceAll(text, `\n`, "\n"), values)
+	return nil
+}
+
+func (ctx *TestContext) progressSpinnerFinishesWith(message, done string) error {
+	ctx.spinner = progress.New(message)
+	ctx.spinner.Start()
+	ctx.spinner.Stop(done)
+	return nil
+}
+
+// logStream returns the captured stdout or stderr of the scenario's logger
+func (ctx *TestContext) logStream(name string) string {
+	if name == "stdout" {
+		return ctx.logOut.String()
+	}
+	return ctx.logErr.String()
+}
+
+func (ctx *TestContext) logStdoutShouldBe(expected *godog.DocString) error {
+	return compareLogStream("stdout", ctx.logOut.String(), expected.Content)
+}
+
+func (ctx *TestContext) logStderrShouldBe(expected *godog.DocString) error {
+	return compareLogStream("stderr", ctx.logErr.String(), expected.Content)
+}
+
+func compareLogStream(name, actual, expected string) error {
+	if strings.TrimRight(actual, "\n") != expected {
+		return fmt.Errorf("expected %s:\n%s\ngot:\n%s", name, expected, actual)
+	}
+	return nil
+}
+
+func (ctx *TestContext) logStreamShouldBeEmpty(name string) error {
+	if out := ctx.logStream(name); out != "" {
+		return fmt.Errorf("expected %s to be empty, got:\n%s", name, out)
+	}
+	return nil
+}
+
+func (ctx *TestContext) logStreamShouldContain(name, expected string) error {
+	if out := ctx.logStream(name); !strings.Contains(out, expected) {
+		return fmt.Errorf("expected %s to contain %q, got:\n%s", name, expected, out)
+	}
+	return nil
+}
+
+func (ctx *TestContext) everyLogLineShouldMatch(name, pattern string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+	out := strings.TrimRight(ctx.logStream(name), "\n")
+	if out == "" {
+		return fmt.Errorf("expected %s lines, got none", name)
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if !re.MatchString(line) {
+			return fmt.Errorf("%s line %q does not match %q", name, line, pattern)
+		}
+	}
+	return nil
+}
+
+func (ctx *TestContext) logStreamShouldContainColorCodes(name string) error {
+	if !strings.Contains(ctx.logStream(name), "\x1b[") {
+		return fmt.Errorf("expected color codes in %s, got %q", name, ctx.logStream(name))
+	}
+	return nil
+}
+
+func (ctx *TestContext) logStreamShouldNotContainColorCodes(name string) error {
+	if strings.Contains(ctx.logStream(name), "\x1b[") {
+		return fmt.Errorf("expected no color codes in %s, got %q", name, ctx.logStream(name))
+	}
+	return nil
+}
+
+func (ctx *TestContext) logLineShouldBeJSON(name string, n int, level, msg string) error {
+	entry, err := ctx.logLineJSON(name, n)
+	if err != nil {
+		return err
+	}
+	if entry["level"] != level || entry["msg"] != msg || entry["time"] == nil || entry["time"] == "" {
+		return fmt.Errorf("expected level %q and msg %q with a time, got %v", level, msg, entry)
+	}
+	return nil
+}
+
+func (ctx *TestContext) logLineShouldHaveField(name string, n int, field, value string) error {
+	entry, err := ctx.logLineJSON(name, n)
+	if err != nil {
+		return err
+	}
+	actual, _ := json.Marshal(entry[field])
+	if _, ok := entry[field]; !ok || string(actual) != value {
+		return fmt.Errorf("expected field %q to be %s, got %v", field, value, entry)
+	}
+	return nil
+}
+
+// logLineJSON decodes line n of the captured stdout or stderr as a JSON object
+func (ctx *TestContext) logLineJSON(name string, n int) (map[string]interface{}, error) {
+	lines := strings.Split(strings.TrimRight(ctx.logStream(name), "\n"), "\n")
+	if n < 1 || n > len(lines) {
+		return nil, fmt.Errorf("expected at least %d %s lines, got %d", n, name, len(lines))
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[n-1]), &entry); err != nil {
+		return nil, fmt.Errorf("%s line %d is not JSON: %w\n%s", name, n, err, lines[n-1])
+	}
+	return entry, nil
+}
+
+func (ctx *TestContext) loggerShouldNotBeInteractive() error {
+	if ctx.logger.Interactive() {
+		return fmt.Errorf("expected spinners to be disabled")
+	}
+	if ctx.logger.Color() {
+		return fmt.Errorf("expected colors to be disabled")
+	}
+	return nil
+}
+
+func (ctx *TestContext) parsedLogConfigShouldBe(mode string) error {
+	if ctx.logConfig == nil {
+		return fmt.Errorf("no command line was parsed")
+	}
+	set := map[string]bool{
+		"quiet":       ctx.logConfig.Quiet,
+		"verbose":     ctx.logConfig.Verbose,
+		"timestamped": ctx.logConfig.Timestamps,
+	}
+	if !set[mode] {
+		return fmt.Errorf("expected the log config to be %s, got %+v", mode, *ctx.logConfig)
+	}
+	return nil
+}
+
+func (ctx *TestContext) parsedLogFormatShouldBe(format string) error {
+	if ctx.logConfig == nil {
+		return fmt.Errorf("no command line was parsed")
+	}
+	if ctx.logConfig.Format != format {
+		return fmt.Errorf("expected log format %q, got %q", format, ctx.logConfig.Format)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package steps
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cucumber/godog"
+	"github.com/heidene/flashdoc/internal/ignore"
+	"github.com/heidene/flashdoc/internal/processor"
+	"github.com/heidene/flashdoc/internal/template"
+	"github.com/heidene/flashdoc/internal/watcher"
+)
+
+// RegisterMdBookSteps registers the mdBook and GitBook step definitions
+func RegisterMdBookSteps(sc *godog.ScenarioContext, ctx *TestContext) {
+	sc.Step(`^the project root has a book config "([^"]*)" with:$`, ctx.projectRootHasBookConfigWith)
+	sc.Step(`^the book is processed$`, ctx.bookIsProcessed)
+	sc.Step(`^the book is being watched$`, ctx.bookIsBeingWatched)
+	sc.Step(`^the book is reloaded$`, ctx.bookIsReloaded)
+}
+
+func (ctx *TestContext) projectRootHasBookConfigWith(name, content string) error {
+	return os.WriteFile(filepath.Join(ctx.projectRoot(), name), []byte(content), 0644)
+}
+
+// bookIsProcessed processes the parsed book the way the site is built: with its excludes,
+// SUMMARY.md's order and includes, writing the summary as the sidebar
+func (ctx *TestContext) bookIsProcessed() error {
+	site, err := ctx.parsedSiteConfig()
+	if err != nil {
+		return err
+	}
+	if site.Book == nil {
+		return fmt.Errorf("expected a book")
+	}
+
+	matcher, err := ignore.Load(site.SourceDir, ignore.Options{Exclude: site.Exclude, Include: site.Include})
+	if err != nil {
+		return err
+	}
+	ctx.processor = processor.NewWithOptions(site.SourceDir, ctx.targetDirectory, processor.Options{
+		Ignore:       matcher,
+		SidebarOrder: site.SidebarOrder,
+		PublicDir:    ctx.publicDirectory(),
+		Includes:     true,
+	})
+	if err := ctx.processor.Process(); err != nil {
+		return err
+	}
+
+	title := site.Title
+	if title == "" {
+		title = template.GenerateTitle(site.SourceDir)
+	}
+	ctx.starlightConfig = &template.StarlightConfig{Title: title, Sidebar: site.Book.Sidebar(ctx.processor.PageLink)}
+	ctx.starlightErr = template.WriteConfig(ctx.tempDir, ctx.starlightConfig)
+	return nil
+}
+
+// bookIsBeingWatched watches the book's source directory the way serve does, with
+// SUMMARY.md and book.toml watched on their own
+func (ctx *TestContext) bookIsBeingWatched() error {
+	site, err := ctx.parsedSiteConfig()
+	if err != nil {
+		return err
+	}
+	matcher, err := ignore.Load(site.SourceDir, ignore.Options{Exclude: site.Exclude, Include: site.Include})
+	if err != nil {
+		return err
+	}
+
+	w, err := watcher.New(site.SourceDir)
+	if err != nil {
+		return err
+	}
+	w.SetDebounce(50 * time.Millisecond)
+	w.SetIgnore(matcher)
+	for _, path := range []string{site.Book.Summary, site.Book.Path} {
+		if err := w.WatchFile(path); err != nil {
+			return err
+		}
+	}
+	if err := w.Start(); err != nil {
+		return err
+	}
+	ctx.watcher = w
+	return nil
+}
+
+// bookIsReloaded re-reads the book after an edit the way serve does: pages follow the new
+// order and the sidebar is rewritten
+func (ctx *TestContext) bookIsReloaded() error {
+	site, err := ctx.parsedSiteConfig()
+	if err != nil {
+		return err
+	}
+	if err := site.Book.Reload(); err != nil {
+		return err
+	}
+
+	ctx.processor.SetSidebarOrder(site.Book.Pages())
+	if err := ctx.processor.Sync("."); err != nil {
+		return err
+	}
+	ctx.starlightConfig.Sidebar = site.Book.Sidebar(ctx.processor.PageLink)
+	if err := template.ExtractConfigOnly(ctx.tempDir); err != nil {
+		return err
+	}
+	ctx.starlightErr = template.WriteConfig(ctx.tempDir, ctx.starlightConfig)
+	return nil
+}
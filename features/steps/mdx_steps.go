@@ -0,0 +1,74 @@
+package steps
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cucumber/godog"
+	"github.com/heidene/flashdoc/internal/processor"
+)
+
+// RegisterMDXSteps registers all MDX step definitions
+func RegisterMDXSteps(sc *godog.ScenarioContext, ctx *TestContext) {
+	sc.Step(`^files are processed with the components directory "([^"]*)"$`, ctx.filesAreProcessedWithComponentsDirectory)
+
+	sc.Step(`^the target file "([^"]*)" should contain in order:$`, ctx.targetFileShouldContainInOrder)
+	sc.Step(`^the copied component "([^"]*)" should contain "([^"]*)"$`, ctx.copiedComponentShouldContain)
+	sc.Step(`^the parsed components directory should be "([^"]*)"$`, ctx.parsedComponentsDirectoryShouldBe)
+}
+
+// componentsTarget is where the scenario's components are copied, like src/components in a workspace
+func (ctx *TestContext) componentsTarget() string {
+	return filepath.Join(ctx.tempDir, "src", "components")
+}
+
+func (ctx *TestContext) filesAreProcessedWithComponentsDirectory(dir string) error {
+	ctx.processor = processor.NewWithOptions(ctx.sourceDirectory, ctx.targetDirectory, processor.Options{
+		PublicDir:        ctx.publicDirectory(),
+		Components:       dir,
+		ComponentsTarget: ctx.componentsTarget(),
+	})
+	return ctx.processor.Process()
+}
+
+func (ctx *TestContext) targetFileShouldContainInOrder(relPath string, expected *godog.DocString) error {
+	data, err := os.ReadFile(filepath.Join(ctx.targetDirectory, relPath))
+	if err != nil {
+		return fmt.Errorf("failed to read target file %s: %w", relPath, err)
+	}
+	content := string(data)
+
+	offset := 0
+	for _, line := range strings.Split(expected.Content, "\n") {
+		i := strings.Index(content[offset:], line)
+		if i < 0 {
+			return fmt.Errorf("expected %q after offset %d in %s, got:\n%s", line, offset, relPath, content)
+		}
+		offset += i + len(line)
+	}
+	return nil
+}
+
+func (ctx *TestContext) copiedComponentShouldContain(relPath, expected string) error {
+	data, err := os.ReadFile(filepath.Join(ctx.componentsTarget(), relPath))
+	if err != nil {
+		return fmt.Errorf("expected copied component %s: %w", relPath, err)
+	}
+	if !strings.Contains(string(data), expected) {
+		return fmt.Errorf("expected component %s to contain %q, got:\n%s", relPath, expected, data)
+	}
+	return nil
+}
+
+func (ctx *TestContext) parsedComponentsDirectoryShouldBe(expected string) error {
+	site, err := ctx.parsedSiteConfig()
+	if err != nil {
+		return err
+	}
+	if got := filepath.ToSlash(site.ComponentsRel()); got != expected {
+		return fmt.Errorf("expected components directory %q, got %q", expected, got)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package steps
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cucumber/godog"
+	"github.com/heidene/flashdoc/internal/cli"
+	"github.com/heidene/flashdoc/internal/processor"
+	"github.com/heidene/flashdoc/internal/template"
+)
+
+// RegisterMkDocsSteps registers the MkDocs import step definitions
+func RegisterMkDocsSteps(sc *godog.ScenarioContext, ctx *TestContext) {
+	sc.Step(`^the project root has an MkDocs config "([^"]*)" with:$`, ctx.projectRootHasMkDocsConfigWith)
+
+	sc.Step(`^stardoc "([^"]*)" is run on the project root with "([^"]*)"$`, ctx.stardocIsRunOnProjectRootWith)
+	sc.Step(`^the MkDocs site is processed$`, ctx.mkdocsSiteIsProcessed)
+
+	sc.Step(`^the site should be built from the source directory$`, ctx.siteShouldBeBuiltFromSourceDirectory)
+	sc.Step(`^the site should be built from "([^"]*)" in the source directory$`, ctx.siteShouldBeBuiltFrom)
+	sc.Step(`^the parsed stylesheets should be "([^"]*)"$`, ctx.parsedStylesheetsShouldBe)
+	sc.Step(`^the parsed social link "([^"]*)" should be "([^"]*)"$`, ctx.parsedSocialLinkShouldBe)
+	sc.Step(`^running stardoc on the project root should fail with "([^"]*)"$`, ctx.runningStardocOnProjectRootShouldFailWith)
+}
+
+// projectRoot is the directory above the source directory, where mkdocs.yml lives
+func (ctx *TestContext) projectRoot() string {
+	return filepath.Dir(ctx.sourceDirectory)
+}
+
+func (ctx *TestContext) projectRootHasMkDocsConfigWith(name, content string) error {
+	return os.WriteFile(filepath.Join(ctx.projectRoot(), name), []byte(content), 0644)
+}
+
+func (ctx *TestContext) stardocIsRunOnProjectRootWith(command, flags string) error {
+	return ctx.parseCommandLine(append([]string{command, ctx.projectRoot()}, strings.Fields(flags)...))
+}
+
+func (ctx *TestContext) mkdocsSiteIsProcessed() error {
+	site, err := ctx.parsedSiteConfig()
+	if err != nil {
+		return err
+	}
+	if site.MkDocs == nil {
+		return fmt.Errorf("expected an MkDocs project")
+	}
+
+	ctx.processor = processor.NewWithOptions(site.SourceDir, ctx.targetDirectory, processor.Options{
+		PublicDir:   ctx.publicDirectory(),
+		Admonitions: true,
+	})
+	if err := ctx.processor.Process(); err != nil {
+		return err
+	}
+
+	ctx.starlightConfig = &template.StarlightConfig{Title: site.Title, Sidebar: site.MkDocs.Sidebar(ctx.processor.PageLink)}
+	ctx.starlightErr = template.WriteConfig(ctx.tempDir, ctx.starlightConfig)
+	return nil
+}
+
+func (ctx *TestContext) siteShouldBeBuiltFromSourceDirectory() error {
+	return ctx.siteShouldBeBuiltFrom(".")
+}
+
+func (ctx *TestContext) siteShouldBeBuiltFrom(relPath string) error {
+	site, err := ctx.parsedSiteConfig()
+	if err != nil {
+		return err
+	}
+	if expected := filepath.Join(ctx.sourceDirectory, relPath); site.SourceDir != expected {
+		return fmt.Errorf("expected the site to be built from %s, got %s", expected, site.SourceDir)
+	}
+	return nil
+}
+
+func (ctx *TestContext) parsedStylesheetsShouldBe(expected string) error {
+	site, err := ctx.parsedSiteConfig()
+	if err != nil {
+		return err
+	}
+	var stylesheets []string
+	for _, css := range site.CustomCSS {
+		rel, err := filepath.Rel(ctx.sourceDirectory, css)
+		if err != nil {
+			return err
+		}
+		stylesheets = append(stylesheets, filepath.ToSlash(rel))
+	}
+	if got := strings.Join(stylesheets, ", "); got != expected {
+		return fmt.Errorf("expected stylesheets %q, got %q", expected, got)
+	}
+	return nil
+}
+
+func (ctx *TestContext) parsedSocialLinkShouldBe(icon, expected string) error {
+	site, err := ctx.parsedSiteConfig()
+	if err != nil {
+		return err
+	}
+	if got := site.Social[icon]; got != expected {
+		return fmt.Errorf("expected %s link %q, got %q", icon, expected, got)
+	}
+	return nil
+}
+
+func (ctx *TestContext) runningStardocOnProjectRootShouldFailWith(expected string) error {
+	_, err := cli.Parse([]string{"build", ctx.projectRoot()})
+	if err == nil {
+		return fmt.Errorf("expected the command line to be rejected")
+	}
+	if !strings.Contains(err.Error(), expected) {
+		return fmt.Errorf("expected error containing %q, got %q", expected, err.Error())
+	}
+	return nil
+}
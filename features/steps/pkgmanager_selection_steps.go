@@ -0,0 +1,193 @@
+package steps
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/cucumber/godog"
+	"github.com/heidene/flashdoc/internal/pkgmanager"
+	"github.com/heidene/flashdoc/internal/template"
+)
+
+// RegisterPkgManagerSelectionSteps registers the package manager selection step definitions
+func RegisterPkgManagerSelectionSteps(sc *godog.ScenarioContext, ctx *TestContext) {
+	sc.Step(`^the (install|build|dev) command for "([^"]*)" should be "([^"]*)"$`, ctx.commandForPackageManagerShouldBe)
+	sc.Step(`^the commands for "([^"]*)" should set "([^"]*)"$`, ctx.commandsForPackageManagerShouldSet)
+	sc.Step(`^the commands for "([^"]*)" should not set any environment variables$`, ctx.commandsForPackageManagerShouldNotSetEnv)
+
+	sc.Step(`^only "([^"]*)" version "([^"]*)" is installed$`, ctx.onlyPackageManagerVersionIsInstalled)
+	sc.Step(`^the package manager "([^"]*)" is selected$`, ctx.packageManagerIsSelected)
+	sc.Step(`^the selected package manager should be "([^"]*)"$`, ctx.selectedPackageManagerShouldBe)
+	sc.Step(`^selecting the package manager should fail with "([^"]*)"$`, ctx.selectingPackageManagerShouldFailWith)
+	sc.Step(`^the parsed package manager should be "([^"]*)"$`, ctx.parsedPackageManagerShouldBe)
+
+	sc.Step(`^the shared project has a file "([^"]*)"$`, ctx.sharedProjectHasFile)
+	sc.Step(`^the shared install is cleaned$`, ctx.sharedInstallIsCleaned)
+	sc.Step(`^the shared install should be current for "([^"]*)"$`, ctx.sharedInstallShouldBeCurrentFor)
+	sc.Step(`^the shared install should not be current for "([^"]*)"$`, ctx.sharedInstallShouldNotBeCurrentFor)
+	sc.Step(`^the shared project should have "([^"]*)"$`, ctx.sharedProjectShouldHave)
+	sc.Step(`^the shared project should not have "([^"]*)"$`, ctx.sharedProjectShouldNotHave)
+}
+
+func (ctx *TestContext) commandForPackageManagerShouldBe(kind, name, expected string) error {
+	pm := pkgmanager.PackageManager(name)
+	var cmd pkgmanager.Command
+	switch kind {
+	case "install":
+		cmd = pm.InstallCommand()
+	case "build":
+		cmd = pm.BuildCommand()
+	default:
+		cmd = pm.DevCommand(4321)
+	}
+	if cmd.String() != expected {
+		return fmt.Errorf("expected %s command %q for %s, got %q", kind, expected, name, cmd.String())
+	}
+	return nil
+}
+
+func (ctx *TestContext) commandsForPackageManagerShouldSet(name, variable string) error {
+	pm := pkgmanager.PackageManager(name)
+	for _, cmd := range []pkgmanager.Command{pm.InstallCommand(), pm.BuildCommand(), pm.DevCommand(4321)} {
+		if !slices.Contains(cmd.Env, variable) {
+			return fmt.Errorf("expected %q to set %s, got %v", cmd, variable, cmd.Env)
+		}
+	}
+	return nil
+}
+
+func (ctx *TestContext) commandsForPackageManagerShouldNotSetEnv(name string) error {
+	pm := pkgmanager.PackageManager(name)
+	for _, cmd := range []pkgmanager.Command{pm.InstallCommand(), pm.BuildCommand(), pm.DevCommand(4321)} {
+		if len(cmd.Env) > 0 {
+			return fmt.Errorf("expected %q to keep the environment, got %v", cmd, cmd.Env)
+		}
+	}
+	return nil
+}
+
+func (ctx *TestContext) onlyPackageManagerVersionIsInstalled(name, version string) error {
+	// A stand-in that only answers --version, alone on the PATH
+	ctx.binDir = filepath.Join(ctx.tempDir, "bin")
+	if err := os.MkdirAll(ctx.binDir, 0755); err != nil {
+		return err
+	}
+	script := fmt.Sprintf("#!/bin/sh\necho %s\n", version)
+	return os.WriteFile(filepath.Join(ctx.binDir, name), []byte(script), 0755)
+}
+
+func (ctx *TestContext) packageManagerIsSelected(name string) error {
+	path := os.Getenv("PATH")
+	os.Setenv("PATH", ctx.binDir)
+	defer os.Setenv("PATH", path)
+
+	ctx.detectedPM, ctx.pmErr = pkgmanager.Select(name)
+	return nil
+}
+
+func (ctx *TestContext) selectedPackageManagerShouldBe(expected string) error {
+	if ctx.pmErr != nil {
+		return fmt.Errorf("expected %s to be selected, got error: %w", expected, ctx.pmErr)
+	}
+	if ctx.detectedPM.String() != expected {
+		return fmt.Errorf("expected package manager %q, got %q", expected, ctx.detectedPM)
+	}
+	return nil
+}
+
+func (ctx *TestContext) selectingPackageManagerShouldFailWith(expected string) error {
+	if ctx.pmErr == nil {
+		return fmt.Errorf("expected selection to fail, got %q", ctx.detectedPM)
+	}
+	if !strings.Contains(ctx.pmErr.Error(), expected) {
+		return fmt.Errorf("expected error containing %q, got %q", expected, ctx.pmErr.Error())
+	}
+	return nil
+}
+
+func (ctx *TestContext) parsedPackageManagerShouldBe(expected string) error {
+	site, err := ctx.parsedSiteConfig()
+	if err != nil {
+		return err
+	}
+	if site.PackageManager != expected {
+		return fmt.Errorf("expected package manager %q, got %q", expected, site.PackageManager)
+	}
+	return nil
+}
+
+func (ctx *TestContext) sharedProjectHasFile(name string) error {
+	manager, err := ctx.cacheManager()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(manager.GetSharedDir(), name), []byte("{}\n"), 0644)
+}
+
+func (ctx *TestContext) sharedInstallIsCleaned() error {
+	manager, err := ctx.cacheManager()
+	if err != nil {
+		return err
+	}
+	return manager.CleanInstall()
+}
+
+// sharedInstallIsCurrentFor checks the shared install against the embedded template and pm
+func (ctx *TestContext) sharedInstallIsCurrentFor(pm string) (bool, error) {
+	manager, err := ctx.cacheManager()
+	if err != nil {
+		return false, err
+	}
+	hash, err := template.GetEmbeddedPackageHash()
+	if err != nil {
+		return false, err
+	}
+	return manager.IsInstalledWith(hash, pm)
+}
+
+func (ctx *TestContext) sharedInstallShouldBeCurrentFor(pm string) error {
+	current, err := ctx.sharedInstallIsCurrentFor(pm)
+	if err != nil {
+		return err
+	}
+	if !current {
+		return fmt.Errorf("expected the shared install to be current for %s", pm)
+	}
+	return nil
+}
+
+func (ctx *TestContext) sharedInstallShouldNotBeCurrentFor(pm string) error {
+	current, err := ctx.sharedInstallIsCurrentFor(pm)
+	if err != nil {
+		return err
+	}
+	if current {
+		return fmt.Errorf("expected %s to need a reinstall", pm)
+	}
+	return nil
+}
+
+func (ctx *TestContext) sharedProjectShouldHave(name string) error {
+	manager, err := ctx.cacheManager()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(manager.GetSharedDir(), name)); err != nil {
+		return fmt.Errorf("expected the shared project to have %s: %w", name, err)
+	}
+	return nil
+}
+
+func (ctx *TestContext) sharedProjectShouldNotHave(name string) error {
+	manager, err := ctx.cacheManager()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(manager.GetSharedDir(), name)); !os.IsNotExist(err) {
+		return fmt.Errorf("expected %s to be removed from the shared project", name)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package steps
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cucumber/godog"
+	"github.com/heidene/flashdoc/internal/cli"
+	"github.com/heidene/flashdoc/internal/renderer"
+)
+
+// RegisterRendererSteps registers all native renderer step definitions
+func RegisterRendererSteps(sc *godog.ScenarioContext, ctx *TestContext) {
+	sc.Step(`^the processed docs are rendered natively$`, ctx.processedDocsAreRenderedNatively)
+
+	sc.Step(`^the rendered file "([^"]*)" should exist$`, ctx.renderedFileShouldExist)
+	sc.Step(`^the rendered page "([^"]*)" should contain:$`, ctx.renderedPageShouldContain)
+	sc.Step(`^the rendered page "([^"]*)" should not contain:$`, ctx.renderedPageShouldNotContain)
+	sc.Step(`^the sidebar of "([^"]*)" should list "([^"]*)" in order$`, ctx.sidebarShouldListInOrder)
+	sc.Step(`^the resolved renderer should be "([^"]*)"$`, ctx.resolvedRendererShouldBe)
+	sc.Step(`^stardoc with "([^"]*)" should fail with "([^"]*)"$`, ctx.stardocWithShouldFailWith)
+}
+
+// renderedDirectory returns the dist/ directory of the temp workspace
+func (ctx *TestContext) renderedDirectory() string {
+	return filepath.Join(ctx.tempDir, "dist")
+}
+
+func (ctx *TestContext) processedDocsAreRenderedNatively() error {
+	r := renderer.New(ctx.targetDirectory, ctx.renderedDirectory(), renderer.Options{
+		Title:     "Test Docs",
+		PublicDir: ctx.publicDirectory(),
+	}, quietLogger())
+	return r.Build()
+}
+
+func (ctx *TestContext) renderedFileShouldExist(relPath string) error {
+	if _, err := os.Stat(filepath.Join(ctx.renderedDirectory(), relPath)); err != nil {
+		return fmt.Errorf("expected rendered file %s: %w", relPath, err)
+	}
+	return nil
+}
+
+// renderedPage reads a file from the rendered site
+func (ctx *TestContext) renderedPage(relPath string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(ctx.renderedDirectory(), relPath))
+	if err != nil {
+		return "", fmt.Errorf("failed to read rendered page %s: %w", relPath, err)
+	}
+	return string(content), nil
+}
+
+func (ctx *TestContext) renderedPageShouldContain(relPath string, expected *godog.DocString) error {
+	content, err := ctx.renderedPage(relPath)
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(expected.Content, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.Contains(content, line) {
+			return fmt.Errorf("expected %s to contain %q", relPath, line)
+		}
+	}
+	return nil
+}
+
+func (ctx *TestContext) renderedPageShouldNotContain(relPath string, unexpected *godog.DocString) error {
+	content, err := ctx.renderedPage(relPath)
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(unexpected.Content, "\n") {
+		if line = strings.TrimSpace(line); line != "" && strings.Contains(content, line) {
+			return fmt.Errorf("did not expect %s to contain %q", relPath, line)
+		}
+	}
+	return nil
+}
+
+func (ctx *TestContext) sidebarShouldListInOrder(relPath, labels string) error {
+	content, err := ctx.renderedPage(relPath)
+	if err != nil {
+		return err
+	}
+
+	start := strings.Index(content, `<nav class="sidebar"`)
+	end := strings.Index(content, "</nav>\n    <main>")
+	if start < 0 || end < start {
+		return fmt.Errorf("no sidebar in %s", relPath)
+	}
+	sidebar := content[start:end]
+
+	pos := 0
+	for _, label := range strings.Split(labels, ",") {
+		label = ">" + strings.TrimSpace(label) + "<"
+		i := strings.Index(sidebar[pos:], label)
+		if i < 0 {
+			return fmt.Errorf("expected %q after position %d in sidebar:\n%s", label, pos, sidebar)
+		}
+		pos += i + len(label)
+	}
+	return nil
+}
+
+func (ctx *TestContext) resolvedRendererShouldBe(expected string) error {
+	site, err := ctx.parsedSiteConfig()
+	if err != nil {
+		return err
+	}
+	if site.Renderer != expected {
+		return fmt.Errorf("expected renderer %q, got %q", expected, site.Renderer)
+	}
+	return nil
+}
+
+func (ctx *TestContext) stardocWithShouldFailWith(flags, expected string) error {
+	args := append([]string{ctx.sourceDirectory}, strings.Fields(flags)...)
+	_, err := cli.Parse(args)
+	if err == nil {
+		return fmt.Errorf("expected parsing %q to fail", flags)
+	}
+	if !strings.Contains(err.Error(), expected) {
+		return fmt.Errorf("expected error containing %q, got %v", expected, err)
+	}
+	return nil
+}
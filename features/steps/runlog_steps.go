@@ -0,0 +1,117 @@
+package steps
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cucumber/godog"
+	"github.com/heidene/flashdoc/internal/runlog"
+)
+
+// RegisterRunLogSteps registers all build log step definitions
+func RegisterRunLogSteps(sc *godog.ScenarioContext, ctx *TestContext) {
+	sc.Step(`^"([^"]*)" wrote to the build log:$`, ctx.commandWroteToBuildLog)
+	sc.Step(`^the astro build fails on "([^"]*)" in "([^"]*)" with "([^"]*)"$`, ctx.astroBuildFailsOn)
+	sc.Step(`^the build fails$`, ctx.theBuildFails)
+
+	sc.Step(`^the build error should contain "([^"]*)"$`, ctx.buildErrorShouldContain)
+	sc.Step(`^the build error should not contain "([^"]*)"$`, ctx.buildErrorShouldNotContain)
+	sc.Step(`^the build error should point at "([^"]*)"$`, ctx.buildErrorShouldPointAt)
+	sc.Step(`^the build log file should contain "([^"]*)"$`, ctx.buildLogFileShouldContain)
+}
+
+// openBuildLog opens the scenario's build log, mapping pages through the processor if there is one
+func (ctx *TestContext) openBuildLog() error {
+	if ctx.buildLog != nil {
+		return nil
+	}
+	dir, err := os.MkdirTemp("", "stardoc-logs-*")
+	if err != nil {
+		return err
+	}
+	ctx.createdDirs = append(ctx.createdDirs, dir)
+
+	ctx.buildLog, err = runlog.Open(filepath.Join(dir, "run.log"), false)
+	if err != nil {
+		return err
+	}
+	if ctx.processor != nil {
+		ctx.buildLog.SetLocator(ctx.processor.SourceLocation)
+	}
+	return nil
+}
+
+func (ctx *TestContext) commandWroteToBuildLog(command string, output *godog.DocString) error {
+	if err := ctx.openBuildLog(); err != nil {
+		return err
+	}
+	ctx.buildLog.Step(command)
+	_, err := ctx.buildLog.Write([]byte(strings.ReplaceAll(output.Content, `\e`, "\x1b") + "\n"))
+	return err
+}
+
+// astroBuildFailsOn logs an Astro MDX error pointing at the line of the processed page containing text
+func (ctx *TestContext) astroBuildFailsOn(text, page, message string) error {
+	content, err := os.ReadFile(filepath.Join(ctx.targetDirectory, page))
+	if err != nil {
+		return fmt.Errorf("failed to read target file: %w", err)
+	}
+	line := 0
+	for i, l := range strings.Split(string(content), "\n") {
+		if strings.Contains(l, text) {
+			line = i + 1
+			break
+		}
+	}
+	if line == 0 {
+		return fmt.Errorf("target file %s does not contain %q", page, text)
+	}
+
+	if err := ctx.openBuildLog(); err != nil {
+		return err
+	}
+	ctx.buildLog.Step("npm run build")
+	output := fmt.Sprintf("building client (vite)\n[ERROR] [MDXError] %s\n  Location:\n    %s:%d:1\n",
+		message, filepath.Join(ctx.targetDirectory, page), line)
+	if _, err := ctx.buildLog.Write([]byte(output)); err != nil {
+		return err
+	}
+	return ctx.theBuildFails()
+}
+
+func (ctx *TestContext) theBuildFails() error {
+	ctx.buildErr = ctx.buildLog.Fail(errors.New("build failed: exit status 1"))
+	return nil
+}
+
+func (ctx *TestContext) buildErrorShouldContain(expected string) error {
+	if ctx.buildErr == nil || !strings.Contains(ctx.buildErr.Error(), expected) {
+		return fmt.Errorf("expected build error to contain %q, got: %v", expected, ctx.buildErr)
+	}
+	return nil
+}
+
+func (ctx *TestContext) buildErrorShouldNotContain(unexpected string) error {
+	if ctx.buildErr != nil && strings.Contains(ctx.buildErr.Error(), unexpected) {
+		return fmt.Errorf("expected build error not to contain %q, got: %v", unexpected, ctx.buildErr)
+	}
+	return nil
+}
+
+func (ctx *TestContext) buildErrorShouldPointAt(location string) error {
+	return ctx.buildErrorShouldContain(filepath.Join(ctx.sourceDirectory, location))
+}
+
+func (ctx *TestContext) buildLogFileShouldContain(expected string) error {
+	content, err := os.ReadFile(ctx.buildLog.Path())
+	if err != nil {
+		return fmt.Errorf("failed to read build log: %w", err)
+	}
+	if !strings.Contains(string(content), expected) {
+		return fmt.Errorf("build log does not contain %q:\n%s", expected, content)
+	}
+	return nil
+}
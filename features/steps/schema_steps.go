@@ -0,0 +1,55 @@
+package steps
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cucumber/godog"
+	"github.com/heidene/flashdoc/internal/processor"
+)
+
+// RegisterSchemaSteps registers all frontmatter schema step definitions
+func RegisterSchemaSteps(sc *godog.ScenarioContext, ctx *TestContext) {
+	sc.Step(`^files are processed with frontmatter fixing$`, ctx.filesAreProcessedWithFrontmatterFixing)
+
+	sc.Step(`^invalid frontmatter "([^"]*)" should be reported for "([^"]*)" with "([^"]*)"$`, ctx.invalidFrontmatterShouldBeReported)
+	sc.Step(`^no invalid frontmatter should be reported$`, ctx.noInvalidFrontmatterShouldBeReported)
+	sc.Step(`^the parsed site config should fix frontmatter$`, ctx.parsedSiteConfigShouldFixFrontmatter)
+}
+
+func (ctx *TestContext) filesAreProcessedWithFrontmatterFixing() error {
+	ctx.processor = processor.NewWithOptions(ctx.sourceDirectory, ctx.targetDirectory, processor.Options{
+		PublicDir:      ctx.publicDirectory(),
+		FixFrontmatter: true,
+	})
+	return ctx.processor.Process()
+}
+
+func (ctx *TestContext) invalidFrontmatterShouldBeReported(key, page, message string) error {
+	var reported []string
+	for _, f := range ctx.processor.Invalid() {
+		if f.Page == page && f.Key == key && strings.Contains(f.String(), message) {
+			return nil
+		}
+		reported = append(reported, f.String())
+	}
+	return fmt.Errorf("expected %s: %s with %q, got: %v", page, key, message, reported)
+}
+
+func (ctx *TestContext) noInvalidFrontmatterShouldBeReported() error {
+	if invalid := ctx.processor.Invalid(); len(invalid) > 0 {
+		return fmt.Errorf("expected no invalid frontmatter, got: %v", invalid)
+	}
+	return nil
+}
+
+func (ctx *TestContext) parsedSiteConfigShouldFixFrontmatter() error {
+	site, err := ctx.parsedSiteConfig()
+	if err != nil {
+		return err
+	}
+	if !site.FixFrontmatter {
+		return fmt.Errorf("expected --fix-frontmatter to be set")
+	}
+	return nil
+}
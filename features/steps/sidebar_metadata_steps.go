@@ -0,0 +1,71 @@
+package steps
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cucumber/godog"
+	"github.com/heidene/flashdoc/internal/processor"
+	"github.com/heidene/flashdoc/internal/renderer"
+	"github.com/heidene/flashdoc/internal/template"
+)
+
+// RegisterSidebarMetadataSteps registers the directory metadata step definitions
+func RegisterSidebarMetadataSteps(sc *godog.ScenarioContext, ctx *TestContext) {
+	sc.Step(`^the sidebar is written to the Starlight config$`, ctx.sidebarIsWrittenToStarlightConfig)
+	sc.Step(`^the processed docs are rendered natively with the directory metadata$`, ctx.processedDocsAreRenderedNativelyWithDirectoryMetadata)
+
+	sc.Step(`^the sidebar should list "([^"]*)"$`, ctx.sidebarShouldList)
+	sc.Step(`^astro\.config\.mjs should not contain "([^"]*)"$`, ctx.astroConfigShouldNotContain)
+	sc.Step(`^processing the files should fail with "([^"]*)"$`, ctx.processingFilesShouldFailWith)
+}
+
+func (ctx *TestContext) sidebarIsWrittenToStarlightConfig() error {
+	ctx.starlightConfig = &template.StarlightConfig{Title: "Handbook", Sidebar: ctx.processor.Sidebar()}
+	ctx.starlightErr = template.WriteConfig(ctx.tempDir, ctx.starlightConfig)
+	return nil
+}
+
+func (ctx *TestContext) processedDocsAreRenderedNativelyWithDirectoryMetadata() error {
+	r := renderer.New(ctx.targetDirectory, ctx.renderedDirectory(), renderer.Options{
+		Title:      "Test Docs",
+		PublicDir:  ctx.publicDirectory(),
+		Categories: ctx.processor.Categories(),
+	}, quietLogger())
+	return r.Build()
+}
+
+func (ctx *TestContext) sidebarShouldList(expected string) error {
+	var labels []string
+	for _, item := range ctx.processor.Sidebar() {
+		labels = append(labels, item.Label)
+	}
+	if got := strings.Join(labels, ", "); got != expected {
+		return fmt.Errorf("expected the sidebar to list %q, got %q", expected, got)
+	}
+	return nil
+}
+
+func (ctx *TestContext) astroConfigShouldNotContain(unexpected string) error {
+	content, err := ctx.writtenAstroConfig()
+	if err != nil {
+		return err
+	}
+	if strings.Contains(content, unexpected) {
+		return fmt.Errorf("expected astro.config.mjs not to contain %q, got:\n%s", unexpected, content)
+	}
+	return nil
+}
+
+func (ctx *TestContext) processingFilesShouldFailWith(expected string) error {
+	err := processor.NewWithOptions(ctx.sourceDirectory, ctx.targetDirectory, processor.Options{
+		PublicDir: ctx.publicDirectory(),
+	}).Process()
+	if err == nil {
+		return fmt.Errorf("expected processing to fail")
+	}
+	if !strings.Contains(err.Error(), expected) {
+		return fmt.Errorf("expected error containing %q, got %q", expected, err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package steps
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cucumber/godog"
+	"github.com/heidene/flashdoc/internal/template"
+)
+
+// RegisterStarlightConfigSteps registers the Starlight config generation step definitions
+func RegisterStarlightConfigSteps(sc *godog.ScenarioContext, ctx *TestContext) {
+	sc.Step(`^a Starlight config titled "([^"]*)"$`, ctx.starlightConfigTitled)
+	sc.Step(`^a Starlight config titled "([^"]*)" with:$`, ctx.starlightConfigTitledWith)
+	sc.Step(`^the sidebar links "([^"]*)" to "([^"]*)"$`, ctx.sidebarLinks)
+	sc.Step(`^the sidebar has a group "([^"]*)" generated from "([^"]*)"$`, ctx.sidebarHasAutogeneratedGroup)
+	sc.Step(`^the sidebar has a group "([^"]*)" linking "([^"]*)" to "([^"]*)"$`, ctx.sidebarHasGroupLinking)
+	sc.Step(`^the site has the locale "([^"]*)" labelled "([^"]*)" in "([^"]*)"$`, ctx.siteHasLocale)
+	sc.Step(`^the default locale is "([^"]*)"$`, ctx.defaultLocaleIs)
+
+	sc.Step(`^the Starlight config is written$`, ctx.starlightConfigIsWritten)
+
+	sc.Step(`^astro\.config\.mjs should be:$`, ctx.astroConfigShouldBe)
+	sc.Step(`^astro\.config\.mjs should contain:$`, ctx.astroConfigShouldContain)
+	sc.Step(`^writing the Starlight config should fail with "([^"]*)"$`, ctx.writingStarlightConfigShouldFailWith)
+}
+
+func (ctx *TestContext) starlightConfigTitled(title string) error {
+	ctx.starlightConfig = &template.StarlightConfig{Title: title}
+	return nil
+}
+
+func (ctx *TestContext) starlightConfigTitledWith(title string, options *godog.Table) error {
+	cfg := &template.StarlightConfig{Title: title}
+	for _, row := range options.Rows {
+		option, value := row.Cells[0].Value, row.Cells[1].Value
+		switch option {
+		case "description":
+			cfg.Description = value
+		case "logo":
+			cfg.Logo = &template.Logo{Src: value}
+		case "favicon":
+			cfg.Favicon = value
+		case "social":
+			icon, href, _ := strings.Cut(value, " ")
+			cfg.Social = append(cfg.Social, template.SocialLinks(map[string]string{icon: href})...)
+		case "customCss":
+			cfg.CustomCSS = append(cfg.CustomCSS, value)
+		case "editLink":
+			cfg.EditLink = &template.EditLink{BaseURL: value}
+		case "pagination":
+			pagination := value == "true"
+			cfg.Pagination = &pagination
+		case "tableOfContents":
+			toc, err := parseTableOfContents(value)
+			if err != nil {
+				return err
+			}
+			cfg.TableOfContents = toc
+		default:
+			return fmt.Errorf("unknown Starlight option %q", option)
+		}
+	}
+	ctx.starlightConfig = cfg
+	return nil
+}
+
+// parseTableOfContents reads "false" or heading levels as "min-max"
+func parseTableOfContents(value string) (*template.TableOfContents, error) {
+	if value == "false" {
+		return &template.TableOfContents{Disabled: true}, nil
+	}
+	minLevel, maxLevel, _ := strings.Cut(value, "-")
+	lo, err := strconv.Atoi(minLevel)
+	if err != nil {
+		return nil, fmt.Errorf("invalid table of contents levels %q", value)
+	}
+	hi, err := strconv.Atoi(maxLevel)
+	if err != nil {
+		return nil, fmt.Errorf("invalid table of contents levels %q", value)
+	}
+	return &template.TableOfContents{MinHeadingLevel: lo, MaxHeadingLevel: hi}, nil
+}
+
+func (ctx *TestContext) sidebarLinks(label, link string) error {
+	ctx.starlightConfig.Sidebar = append(ctx.starlightConfig.Sidebar, template.SidebarItem{Label: label, Link: link})
+	return nil
+}
+
+func (ctx *TestContext) sidebarHasAutogeneratedGroup(label, directory string) error {
+	ctx.starlightConfig.Sidebar = append(ctx.starlightConfig.Sidebar, template.SidebarItem{
+		Label:        label,
+		Autogenerate: &template.Autogenerate{Directory: directory},
+	})
+	return nil
+}
+
+func (ctx *TestContext) sidebarHasGroupLinking(label, linkLabel, link string) error {
+	ctx.starlightConfig.Sidebar = append(ctx.starlightConfig.Sidebar, template.SidebarItem{
+		Label: label,
+		Items: []template.SidebarItem{{Label: linkLabel, Link: link}},
+	})
+	return nil
+}
+
+func (ctx *TestContext) siteHasLocale(dir, label, lang string) error {
+	if ctx.starlightConfig.Locales == nil {
+		ctx.starlightConfig.Locales = make(map[string]template.Locale)
+	}
+	ctx.starlightConfig.Locales[dir] = template.Locale{Label: label, Lang: lang}
+	return nil
+}
+
+func (ctx *TestContext) defaultLocaleIs(locale string) error {
+	ctx.starlightConfig.DefaultLocale = locale
+	return nil
+}
+
+func (ctx *TestContext) starlightConfigIsWritten() error {
+	ctx.starlightErr = template.WriteConfig(ctx.tempDir, ctx.starlightConfig)
+	return nil
+}
+
+// writtenAstroConfig returns the generated astro.config.mjs, failing if writing it failed
+func (ctx *TestContext) writtenAstroConfig() (string, error) {
+	if ctx.starlightErr != nil {
+		return "", fmt.Errorf("writing the Starlight config failed: %w", ctx.starlightErr)
+	}
+	content, err := os.ReadFile(filepath.Join(ctx.tempDir, "astro.config.mjs"))
+	return string(content), err
+}
+
+func (ctx *TestContext) astroConfigShouldBe(expected *godog.DocString) error {
+	content, err := ctx.writtenAstroConfig()
+	if err != nil {
+		return err
+	}
+	if strings.TrimRight(content, "\n") != expected.Content {
+		return fmt.Errorf("expected astro.config.mjs:\n%s\ngot:\n%s", expected.Content, content)
+	}
+	return nil
+}
+
+func (ctx *TestContext) astroConfigShouldContain(expected *godog.DocString) error {
+	content, err := ctx.writtenAstroConfig()
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(content, expected.Content) {
+		return fmt.Errorf("expected astro.config.mjs to contain:\n%s\ngot:\n%s", expected.Content, content)
+	}
+	return nil
+}
+
+func (ctx *TestContext) writingStarlightConfigShouldFailWith(expected string) error {
+	if ctx.starlightErr == nil {
+		return fmt.Errorf("expected writing the Starlight config to fail")
+	}
+	if !strings.Contains(ctx.starlightErr.Error(), expected) {
+		return fmt.Errorf("expected error containing %q, got %q", expected, ctx.starlightErr.Error())
+	}
+	return nil
+}
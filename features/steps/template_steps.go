@@ -31,7 +31,7 @@ func RegisterTemplateSteps(sc *godog.ScenarioContext, ctx *TestContext) {
 	sc.Step(`^it should have a "([^"]*)" field set to "([^"]*)"$`, ctx.shouldHaveFieldSetTo)
 	sc.Step(`^it should have a "([^"]*)" section with "([^"]*)" and "([^"]*)" scripts$`, ctx.shouldHaveScriptsSection)
 	sc.Step(`^it should import "([^"]*)" integration$`, ctx.shouldImportIntegration)
-	sc.Step(`^it should have a placeholder for site title$`, ctx.shouldHavePlaceholderForSiteTitle)
+	sc.Step(`^it should have a placeholder for the Starlight options$`, ctx.shouldHavePlaceholderForStarlightOptions)
 	sc.Step(`^the placeholder should be "([^"]*)"$`, ctx.placeholderShouldBe)
 	sc.Step(`^the following structure should exist:$`, ctx.followingStructureShouldExist)
 	sc.Step(`^the "([^"]*)" version should be "([^"]*)" or newer$`, ctx.versionShouldBeOrNewer)
@@ -259,13 +259,13 @@ func (ctx *TestContext) shouldImportIntegration(integrationName string) error {
 	return nil
 }
 
-func (ctx *TestContext) shouldHavePlaceholderForSiteTitle() error {
+func (ctx *TestContext) shouldHavePlaceholderForStarlightOptions() error {
 	if ctx.configContent == "" {
 		return fmt.Errorf("astro.config.mjs content not loaded")
 	}
 
-	if !strings.Contains(ctx.configContent, "SITE_TITLE") {
-		return fmt.Errorf("site title placeholder not found")
+	if !strings.Contains(ctx.configContent, template.OptionsMarker) {
+		return fmt.Errorf("starlight options placeholder not found")
 	}
 
 	return nil
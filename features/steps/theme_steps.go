@@ -0,0 +1,115 @@
+package steps
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cucumber/godog"
+	"github.com/heidene/flashdoc/internal/renderer"
+	"github.com/heidene/flashdoc/internal/template"
+	"github.com/heidene/flashdoc/internal/theme"
+)
+
+// RegisterThemeSteps registers the theming step definitions
+func RegisterThemeSteps(sc *godog.ScenarioContext, ctx *TestContext) {
+	sc.Step(`^the source directory has a file "([^"]*)"$`, ctx.sourceDirectoryHasFile)
+	sc.Step(`^the source directory has a file "([^"]*)" with:$`, ctx.sourceDirectoryHasFileWith)
+
+	sc.Step(`^the theme is applied to the workspace$`, ctx.themeIsAppliedToWorkspace)
+	sc.Step(`^the processed docs are rendered natively with the theme$`, ctx.processedDocsAreRenderedNativelyWithTheme)
+
+	sc.Step(`^the workspace should have "([^"]*)"$`, ctx.workspaceShouldHave)
+	sc.Step(`^the workspace should not have "([^"]*)"$`, ctx.workspaceShouldNotHave)
+	sc.Step(`^the workspace file "([^"]*)" should contain:$`, ctx.workspaceFileShouldContain)
+	sc.Step(`^the parsed accent color should be "([^"]*)"$`, ctx.parsedAccentColorShouldBe)
+}
+
+func (ctx *TestContext) sourceDirectoryHasFile(relPath string) error {
+	return ctx.sourceDirectoryHasFileWith(relPath, "/* "+relPath+" */\n")
+}
+
+func (ctx *TestContext) sourceDirectoryHasFileWith(relPath, content string) error {
+	path := filepath.Join(ctx.sourceDirectory, relPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(content), 0644)
+}
+
+// parsedTheme discovers the theme of the parsed command's site, as flashdoc does before building
+func (ctx *TestContext) parsedTheme() (theme.Theme, error) {
+	site, err := ctx.parsedSiteConfig()
+	if err != nil {
+		return theme.Theme{}, err
+	}
+	t := theme.Discover(site.SourceDir, theme.Theme{Logo: site.Logo, Favicon: site.Favicon, CustomCSS: site.CustomCSS})
+	t.Accent, err = theme.ParseAccent(site.AccentColor)
+	return t, err
+}
+
+func (ctx *TestContext) themeIsAppliedToWorkspace() error {
+	t, err := ctx.parsedTheme()
+	if err != nil {
+		return err
+	}
+	ctx.starlightConfig = &template.StarlightConfig{Title: "Handbook"}
+	if err := t.Apply(ctx.tempDir, ctx.starlightConfig); err != nil {
+		return err
+	}
+	ctx.starlightErr = template.WriteConfig(ctx.tempDir, ctx.starlightConfig)
+	return nil
+}
+
+func (ctx *TestContext) processedDocsAreRenderedNativelyWithTheme() error {
+	t, err := ctx.parsedTheme()
+	if err != nil {
+		return err
+	}
+	r := renderer.New(ctx.targetDirectory, ctx.renderedDirectory(), renderer.Options{
+		Title:     "Test Docs",
+		Logo:      t.Logo,
+		Favicon:   t.Favicon,
+		CustomCSS: t.CustomCSS,
+		Accent:    t.Accent,
+		PublicDir: ctx.publicDirectory(),
+	}, quietLogger())
+	return r.Build()
+}
+
+func (ctx *TestContext) workspaceShouldHave(relPath string) error {
+	if _, err := os.Stat(filepath.Join(ctx.tempDir, relPath)); err != nil {
+		return fmt.Errorf("expected %s in the workspace: %w", relPath, err)
+	}
+	return nil
+}
+
+func (ctx *TestContext) workspaceShouldNotHave(relPath string) error {
+	if _, err := os.Stat(filepath.Join(ctx.tempDir, relPath)); err == nil {
+		return fmt.Errorf("expected no %s in the workspace", relPath)
+	}
+	return nil
+}
+
+func (ctx *TestContext) workspaceFileShouldContain(relPath string, expected *godog.DocString) error {
+	content, err := os.ReadFile(filepath.Join(ctx.tempDir, relPath))
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(string(content), expected.Content) {
+		return fmt.Errorf("expected %s to contain:\n%s\ngot:\n%s", relPath, expected.Content, content)
+	}
+	return nil
+}
+
+func (ctx *TestContext) parsedAccentColorShouldBe(expected string) error {
+	site, err := ctx.parsedSiteConfig()
+	if err != nil {
+		return err
+	}
+	if site.AccentColor != expected {
+		return fmt.Errorf("expected accent color %q, got %q", expected, site.AccentColor)
+	}
+	return nil
+}
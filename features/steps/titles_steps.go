@@ -0,0 +1,49 @@
+package steps
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cucumber/godog"
+	"github.com/heidene/flashdoc/internal/processor"
+)
+
+// RegisterTitleSteps registers all page title step definitions
+func RegisterTitleSteps(sc *godog.ScenarioContext, ctx *TestContext) {
+	sc.Step(`^files are processed with the title strategy "([^"]*)"$`, ctx.filesAreProcessedWithTitleStrategy)
+
+	sc.Step(`^the target file "([^"]*)" should not contain "([^"]*)"$`, ctx.targetFileShouldNotContain)
+	sc.Step(`^the parsed title strategy should be "([^"]*)"$`, ctx.parsedTitleStrategyShouldBe)
+}
+
+func (ctx *TestContext) filesAreProcessedWithTitleStrategy(strategy string) error {
+	ctx.processor = processor.NewWithOptions(ctx.sourceDirectory, ctx.targetDirectory, processor.Options{
+		PublicDir: ctx.publicDirectory(),
+		Titles:    strategy,
+	})
+	return ctx.processor.Process()
+}
+
+func (ctx *TestContext) targetFileShouldNotContain(relPath, unexpected string) error {
+	data, err := os.ReadFile(filepath.Join(ctx.targetDirectory, relPath))
+	if err != nil {
+		return fmt.Errorf("failed to read target file %s: %w", relPath, err)
+	}
+	if strings.Contains(string(data), unexpected) {
+		return fmt.Errorf("expected %s not to contain %q, got:\n%s", relPath, unexpected, data)
+	}
+	return nil
+}
+
+func (ctx *TestContext) parsedTitleStrategyShouldBe(expected string) error {
+	site, err := ctx.parsedSiteConfig()
+	if err != nil {
+		return err
+	}
+	if site.Titles != expected {
+		return fmt.Errorf("expected title strategy %q, got %q", expected, site.Titles)
+	}
+	return nil
+}
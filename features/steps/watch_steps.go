@@ -0,0 +1,288 @@
+package steps
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cucumber/godog"
+	"github.com/heidene/flashdoc/internal/processor"
+	"github.com/heidene/flashdoc/internal/staticserver"
+	"github.com/heidene/flashdoc/internal/watcher"
+)
+
+// RegisterWatchSteps registers all watch mode and live reload step definitions
+func RegisterWatchSteps(sc *godog.ScenarioContext, ctx *TestContext) {
+	sc.Step(`^the source file "([^"]*)" is changed to:$`, ctx.sourceFileIsChangedTo)
+	sc.Step(`^the source path "([^"]*)" is deleted$`, ctx.sourcePathIsDeleted)
+	sc.Step(`^the change to "([^"]*)" is synced$`, ctx.changeIsSynced)
+	sc.Step(`^the target file "([^"]*)" should contain "([^"]*)"$`, ctx.targetFileShouldContain)
+	sc.Step(`^the target path "([^"]*)" should not exist$`, ctx.targetPathShouldNotExist)
+
+	sc.Step(`^the source directory is being watched$`, ctx.sourceDirectoryIsBeingWatched)
+	sc.Step(`^the watcher should report a change covering "([^"]*)"$`, ctx.watcherShouldReportChangeCovering)
+	sc.Step(`^the watcher should not report "([^"]*)"$`, ctx.watcherShouldNotReport)
+
+	sc.Step(`^a built site containing "([^"]*)"$`, ctx.builtSiteContaining)
+	sc.Step(`^the built site contains "([^"]*)" with content "([^"]*)"$`, ctx.builtSiteContainsWithContent)
+	sc.Step(`^the static server is running with live reload$`, ctx.staticServerIsRunningWithLiveReload)
+	sc.Step(`^I request "([^"]*)" from the static server$`, ctx.iRequestFromStaticServer)
+	sc.Step(`^the response should contain the live reload script$`, ctx.responseShouldContainLiveReloadScript)
+	sc.Step(`^the response should not contain the live reload script$`, ctx.responseShouldNotContainLiveReloadScript)
+	sc.Step(`^a browser is listening for reload events$`, ctx.browserIsListeningForReloadEvents)
+	sc.Step(`^the static server triggers a reload$`, ctx.staticServerTriggersReload)
+	sc.Step(`^the browser should receive a reload event$`, ctx.browserShouldReceiveReloadEvent)
+}
+
+func (ctx *TestContext) sourceFileIsChangedTo(relPath, content string) error {
+	fullPath := filepath.Join(ctx.sourceDirectory, relPath)
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(fullPath, []byte(content), 0644)
+}
+
+func (ctx *TestContext) sourcePathIsDeleted(relPath string) error {
+	return os.RemoveAll(filepath.Join(ctx.sourceDirectory, relPath))
+}
+
+func (ctx *TestContext) changeIsSynced(relPath string) error {
+	p := processor.New(ctx.sourceDirectory, ctx.targetDirectory)
+	return p.Sync(relPath)
+}
+
+func (ctx *TestContext) targetFileShouldContain(relPath, expected string) error {
+	content, err := os.ReadFile(filepath.Join(ctx.targetDirectory, relPath))
+	if err != nil {
+		return fmt.Errorf("failed to read target file: %w", err)
+	}
+
+	if !strings.Contains(string(content), expected) {
+		return fmt.Errorf("target file %s does not contain %q:\n%s", relPath, expected, content)
+	}
+
+	return nil
+}
+
+func (ctx *TestContext) targetPathShouldNotExist(relPath string) error {
+	if _, err := os.Stat(filepath.Join(ctx.targetDirectory, relPath)); !os.IsNotExist(err) {
+		return fmt.Errorf("expected target path %s to be removed", relPath)
+	}
+	return nil
+}
+
+func (ctx *TestContext) sourceDirectoryIsBeingWatched() error {
+	w, err := watcher.New(ctx.sourceDirectory)
+	if err != nil {
+		return err
+	}
+	w.SetDebounce(50 * time.Millisecond)
+
+	if err := w.Start(); err != nil {
+		return err
+	}
+
+	ctx.watcher = w
+	return nil
+}
+
+// collectChanges gathers reported changes until the expected path is covered or the timeout expires
+func (ctx *TestContext) collectChanges(expected string, timeout time.Duration) bool {
+	deadline := time.After(timeout)
+
+	for {
+		for _, change := range ctx.watchedChanges {
+			if changeCovers(change, expected) {
+				return true
+			}
+		}
+
+		select {
+		case batch, ok := <-ctx.watcher.Changes():
+			if !ok {
+				return false
+			}
+			ctx.watchedChanges = append(ctx.watchedChanges, batch...)
+		case <-deadline:
+			return false
+		}
+	}
+}
+
+// changeCovers reports whether a change to path also covers target (same path or a parent directory)
+func changeCovers(path, target string) bool {
+	path = filepath.ToSlash(path)
+	target = filepath.ToSlash(target)
+	return path == target || strings.HasPrefix(target, path+"/")
+}
+
+func (ctx *TestContext) watcherShouldReportChangeCovering(relPath string) error {
+	if ctx.watcher == nil {
+		return fmt.Errorf("source directory is not being watched")
+	}
+
+	if !ctx.collectChanges(relPath, 3*time.Second) {
+		return fmt.Errorf("watcher did not report %s (got %v)", relPath, ctx.watchedChanges)
+	}
+
+	return nil
+}
+
+func (ctx *TestContext) watcherShouldNotReport(relPath string) error {
+	for _, change := range ctx.watchedChanges {
+		if filepath.ToSlash(change) == relPath {
+			return fmt.Errorf("watcher unexpectedly reported %s", relPath)
+		}
+	}
+	return nil
+}
+
+func (ctx *TestContext) builtSiteContaining(page string) error {
+	if ctx.tempDir == "" {
+		tempDir, err := os.MkdirTemp("", "stardoc-dist-*")
+		if err != nil {
+			return err
+		}
+		ctx.TrackDir(tempDir)
+		ctx.tempDir = tempDir
+	}
+
+	distPath := filepath.Join(ctx.tempDir, "dist")
+	content := "<html><head><title>Docs</title></head><body><h1>Docs</h1></body></html>"
+	return ctx.writeDistFile(distPath, page, content)
+}
+
+func (ctx *TestContext) builtSiteContainsWithContent(file, content string) error {
+	return ctx.writeDistFile(filepath.Join(ctx.tempDir, "dist"), file, content)
+}
+
+func (ctx *TestContext) writeDistFile(distPath, file, content string) error {
+	fullPath := filepath.Join(distPath, file)
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(fullPath, []byte(content), 0644)
+}
+
+func (ctx *TestContext) staticServerIsRunningWithLiveReload() error {
+	port, err := freePort()
+	if err != nil {
+		return err
+	}
+
+	srv := staticserver.NewServer(filepath.Join(ctx.tempDir, "dist"), port, quietLogger())
+	srv.EnableLiveReload()
+	if err := srv.Start(); err != nil {
+		return err
+	}
+
+	ctx.staticServer = srv
+	ctx.serverPort = port
+	return nil
+}
+
+// freePort asks the OS for an unused TCP port
+func freePort() (int, error) {
+	ln, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return 0, err
+	}
+	defer ln.Close()
+	return ln.Addr().(*net.TCPAddr).Port, nil
+}
+
+func (ctx *TestContext) iRequestFromStaticServer(path string) error {
+	resp, err := http.Get(ctx.staticServer.GetURL() + path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("expected status 200, got %d", resp.StatusCode)
+	}
+
+	ctx.responseBody = string(body)
+	return nil
+}
+
+func (ctx *TestContext) responseShouldContainLiveReloadScript() error {
+	if !strings.Contains(ctx.responseBody, staticserver.LiveReloadPath) {
+		return fmt.Errorf("live reload script not found in response:\n%s", ctx.responseBody)
+	}
+	if !strings.Contains(ctx.responseBody, "<h1>Docs</h1>") {
+		return fmt.Errorf("original page content missing from response:\n%s", ctx.responseBody)
+	}
+	return nil
+}
+
+func (ctx *TestContext) responseShouldNotContainLiveReloadScript() error {
+	if strings.Contains(ctx.responseBody, staticserver.LiveReloadPath) {
+		return fmt.Errorf("live reload script unexpectedly injected:\n%s", ctx.responseBody)
+	}
+	return nil
+}
+
+func (ctx *TestContext) browserIsListeningForReloadEvents() error {
+	resp, err := http.Get(ctx.staticServer.GetURL() + staticserver.LiveReloadPath)
+	if err != nil {
+		return err
+	}
+
+	ctx.reloadEvents = make(chan string, 10)
+	connected := make(chan struct{})
+
+	go func() {
+		defer resp.Body.Close()
+		reader := bufio.NewReader(resp.Body)
+		signalled := false
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if !signalled {
+				close(connected)
+				signalled = true
+			}
+			if strings.HasPrefix(line, "event: ") {
+				ctx.reloadEvents <- strings.TrimSpace(strings.TrimPrefix(line, "event: "))
+			}
+		}
+	}()
+
+	select {
+	case <-connected:
+		return nil
+	case <-time.After(2 * time.Second):
+		return fmt.Errorf("browser did not connect to the live reload endpoint")
+	}
+}
+
+func (ctx *TestContext) staticServerTriggersReload() error {
+	ctx.staticServer.Reload()
+	return nil
+}
+
+func (ctx *TestContext) browserShouldReceiveReloadEvent() error {
+	select {
+	case event := <-ctx.reloadEvents:
+		if event != "reload" {
+			return fmt.Errorf("expected reload event, got %q", event)
+		}
+		return nil
+	case <-time.After(2 * time.Second):
+		return fmt.Errorf("no reload event received")
+	}
+}
@@ -5,11 +5,13 @@ import (
 	"os/exec"
 	"runtime"
 	"time"
+
+	"github.com/heidene/flashdoc/internal/logging"
 )
 
 // Open opens the given URL in the default browser
 func Open(url string) error {
-	fmt.Printf("Opening browser at %s...\n", url)
+	logging.Infof("🌐 Opening browser at %s...", url)
 
 	// Slight delay to ensure server is fully ready
 	time.Sleep(1 * time.Second)
@@ -41,7 +43,7 @@ func Open(url string) error {
 		return nil
 	case <-time.After(5 * time.Second):
 		// Timeout - but don't fail, just warn
-		fmt.Println("Warning: browser open command timed out")
+		logging.Warnf("browser open command timed out")
 		return nil
 	}
 }
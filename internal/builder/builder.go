@@ -2,32 +2,29 @@ package builder
 
 import (
 	"fmt"
-	"io"
 	"math/rand"
 	"os"
-	"os/exec"
 	"path/filepath"
 
+	"github.com/heidene/flashdoc/internal/pkgmanager"
 	"github.com/heidene/flashdoc/internal/progress"
+	"github.com/heidene/flashdoc/internal/runlog"
 )
 
 // Builder handles static site building with Astro
 type Builder struct {
 	workspacePath string
-	packageMgr    string
-	output        io.Writer
+	packageMgr    pkgmanager.PackageManager
+	log           *runlog.Log // Captures the build output; nil discards it
 }
 
-// NewBuilder creates a new builder instance
-func NewBuilder(workspacePath, packageMgr string, output io.Writer) *Builder {
-	if output == nil {
-		output = os.Stdout
-	}
-
+// NewBuilder creates a new builder instance. Build output goes to log, which explains
+// failures; a nil log discards it.
+func NewBuilder(workspacePath string, packageMgr pkgmanager.PackageManager, log *runlog.Log) *Builder {
 	return &Builder{
 		workspacePath: workspacePath,
 		packageMgr:    packageMgr,
-		output:        output,
+		log:           log,
 	}
 }
 
@@ -39,28 +36,15 @@ func (b *Builder) Build() error {
 	sp := progress.New(message)
 	sp.Start()
 
-	// Determine build command based on package manager
-	var cmd *exec.Cmd
-	switch b.packageMgr {
-	case "npm":
-		cmd = exec.Command("npm", "run", "build")
-	case "pnpm":
-		cmd = exec.Command("pnpm", "run", "build")
-	case "bun":
-		cmd = exec.Command("bun", "run", "build")
-	default:
-		sp.StopWithError("Unsupported package manager")
-		return fmt.Errorf("unsupported package manager: %s", b.packageMgr)
-	}
-
-	cmd.Dir = b.workspacePath
-	// Discard verbose build output
-	cmd.Stdout = progress.DiscardWriter()
-	cmd.Stderr = progress.DiscardWriter()
+	build := b.packageMgr.BuildCommand()
+	cmd := build.Cmd(b.workspacePath)
+	b.log.Step(build.String())
+	cmd.Stdout = b.log.Writer()
+	cmd.Stderr = b.log.Writer()
 
 	if err := cmd.Run(); err != nil {
 		sp.StopWithError("Build failed")
-		return fmt.Errorf("build failed: %w", err)
+		return b.log.Fail(fmt.Errorf("build failed: %w", err))
 	}
 
 	// Verify dist directory was created
@@ -0,0 +1,274 @@
+package checker
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/heidene/flashdoc/internal/frontmatter"
+	"github.com/heidene/flashdoc/internal/ignore"
+	"github.com/heidene/flashdoc/internal/links"
+	"github.com/heidene/flashdoc/internal/processor"
+	"github.com/heidene/flashdoc/internal/scanner"
+)
+
+// Problem kinds reported by the checker
+const (
+	BrokenLink   = "broken-link"
+	BrokenAnchor = "broken-anchor"
+	MissingImage = "missing-image"
+	MissingFile  = "missing-file"
+	OutsideDocs  = "outside-source"
+)
+
+// Problem is a broken reference found in a page
+type Problem struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Kind    string `json:"kind"`
+	Target  string `json:"target"`
+	Message string `json:"message"`
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s:%d: %s", p.File, p.Line, p.Message)
+}
+
+// Report summarises a check run
+type Report struct {
+	Files    []string  `json:"files"`
+	Links    int       `json:"links"`
+	Problems []Problem `json:"problems"`
+}
+
+// OK reports whether the check found no problems
+func (r *Report) OK() bool {
+	return len(r.Problems) == 0
+}
+
+// Checker resolves the internal links, images and anchors of a docs directory
+type Checker struct {
+	sourceDir   string
+	ignore      *ignore.Matcher
+	titles      string // Page title strategy, which decides whether the opening H1 is published
+	includes    bool   // Expand mdBook includes, as books are built
+	admonitions bool   // Convert MkDocs admonitions, as MkDocs projects are built
+	proc        *processor.Processor
+	pages       map[string]processor.Prepared // Source-relative path (slash separated) -> content as it is built
+	routes      map[string]string             // Site route -> page, computed lazily
+	anchors     map[string]map[string]bool    // Heading ids, computed lazily per page
+}
+
+// New creates a checker for the given source directory
+func New(sourceDir string) *Checker {
+	return &Checker{
+		sourceDir: sourceDir,
+		ignore:    ignore.New(),
+		pages:     make(map[string]processor.Prepared),
+		anchors:   make(map[string]map[string]bool),
+	}
+}
+
+// SetIgnore sets the matcher deciding which source paths are not part of the site
+func (c *Checker) SetIgnore(m *ignore.Matcher) {
+	c.ignore = m
+}
+
+// SetTitles sets the page title strategy (see frontmatter.Options); the default takes the
+// title from the opening H1
+func (c *Checker) SetTitles(strategy string) {
+	c.titles = strategy
+}
+
+// SetIncludes expands mdBook {{#include}} directives before checking, so the links of
+// included files are checked as part of the pages including them
+func (c *Checker) SetIncludes(enabled bool) {
+	c.includes = enabled
+}
+
+// SetAdmonitions converts MkDocs admonitions before checking, so the links inside them
+// aren't taken for indented code
+func (c *Checker) SetAdmonitions(enabled bool) {
+	c.admonitions = enabled
+}
+
+// Run scans the source directory and checks every page
+func (c *Checker) Run() (*Report, error) {
+	s := scanner.New(c.sourceDir)
+	s.SetIgnore(c.ignore)
+	files, err := s.Scan()
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan source directory: %w", err)
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no markdown files found in %s", c.sourceDir)
+	}
+
+	// Pages are read the way the processor builds them, with line numbers of the source
+	c.proc = processor.NewWithOptions(c.sourceDir, "", processor.Options{
+		Ignore:      c.ignore,
+		Includes:    c.includes,
+		Admonitions: c.admonitions,
+	})
+
+	report := &Report{Problems: make([]Problem, 0)}
+	for _, file := range files {
+		content, err := os.ReadFile(file.FullPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file.Path, err)
+		}
+		page := filepath.ToSlash(file.Path)
+		c.pages[page] = c.proc.Prepare(file.Path, string(content))
+		report.Files = append(report.Files, page)
+	}
+
+	// Includes that couldn't be expanded, by page
+	includes := make(map[string][]Problem)
+	for _, m := range c.proc.Missing() {
+		includes[m.Page] = append(includes[m.Page], Problem{
+			File:    m.Page,
+			Line:    m.Line,
+			Kind:    MissingFile,
+			Target:  m.Target,
+			Message: fmt.Sprintf("%s: %s", strings.TrimSuffix(m.Reason, ":"), m.Target),
+		})
+	}
+
+	sort.Strings(report.Files)
+	for _, page := range report.Files {
+		report.Problems = append(report.Problems, includes[page]...)
+		for _, ref := range links.Find(c.pages[page].Content) {
+			if strings.HasPrefix(ref.Dest, "#") || links.IsLocal(ref.Dest) || rootRelative(ref.Dest) {
+				report.Links++
+			}
+			if problem, ok := c.checkRef(page, ref); !ok {
+				report.Problems = append(report.Problems, problem)
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// rootRelative reports whether a destination is a path from the site root, like /guides/setup/
+func rootRelative(dest string) bool {
+	return strings.HasPrefix(dest, "/") && !strings.HasPrefix(dest, "//")
+}
+
+// checkRef resolves a single reference, returning the problem if it's broken
+func (c *Checker) checkRef(page string, ref links.Ref) (Problem, bool) {
+	problem := func(kind, format string, args ...interface{}) (Problem, bool) {
+		return Problem{
+			File:    page,
+			Line:    c.pages[page].SourceLine(ref.Line),
+			Kind:    kind,
+			Target:  ref.Dest,
+			Message: fmt.Sprintf(format, args...),
+		}, false
+	}
+
+	// Anchors within the same page
+	if strings.HasPrefix(ref.Dest, "#") {
+		anchor := links.Fragment(ref.Dest)
+		if anchor != "" && !c.pageAnchors(page)[anchor] {
+			return problem(BrokenAnchor, "broken anchor: %s (no heading with that id in this page)", ref.Dest)
+		}
+		return Problem{}, true
+	}
+
+	linkPath := links.Path(ref.Dest)
+	var target string
+	switch {
+	case rootRelative(ref.Dest):
+		// Paths from the site root are routes of pages, or files in the docs root
+		var ok bool
+		if target, ok = c.routePage(linkPath); !ok {
+			target = strings.TrimPrefix(path.Clean(linkPath), "/")
+		}
+		if target == "" {
+			target = "."
+		}
+	case links.IsLocal(ref.Dest):
+		target = filepath.ToSlash(filepath.Clean(filepath.Join(filepath.Dir(page), filepath.FromSlash(linkPath))))
+		if target == ".." || strings.HasPrefix(target, "../") {
+			return problem(OutsideDocs, "reference outside the source directory: %s", ref.Dest)
+		}
+	default:
+		return Problem{}, true
+	}
+
+	info, err := os.Stat(filepath.Join(c.sourceDir, filepath.FromSlash(target)))
+	if err == nil && info.IsDir() {
+		// Directory links need an index page
+		for _, index := range []string{"index.md", "index.mdx", "README.md", "README.mdx", "readme.md"} {
+			if _, ok := c.pages[strings.TrimPrefix(target+"/"+index, "./")]; ok {
+				return Problem{}, true
+			}
+		}
+		return problem(BrokenLink, "broken link: %s (directory has no index.md or README.md)", ref.Dest)
+	}
+
+	if !scanner.IsMarkdownFile(target) {
+		if err != nil {
+			if rootRelative(ref.Dest) && path.Ext(target) == "" {
+				return problem(BrokenLink, "broken link: %s (no page at that route)", ref.Dest)
+			}
+			if ref.Image {
+				return problem(MissingImage, "missing image: %s", ref.Dest)
+			}
+			return problem(MissingFile, "missing file: %s", ref.Dest)
+		}
+		return Problem{}, true
+	}
+
+	if _, ok := c.pages[target]; !ok {
+		if err == nil {
+			return problem(BrokenLink, "broken link: %s (page is excluded from the site)", ref.Dest)
+		}
+		return problem(BrokenLink, "broken link: %s", ref.Dest)
+	}
+
+	if anchor := links.Fragment(ref.Dest); anchor != "" && !c.pageAnchors(target)[anchor] {
+		return problem(BrokenAnchor, "broken anchor: %s (no heading with that id in %s)", ref.Dest, target)
+	}
+
+	return Problem{}, true
+}
+
+// routePage returns the page published at a site route, like /guides/setup/
+func (c *Checker) routePage(route string) (string, bool) {
+	if c.routes == nil {
+		c.routes = make(map[string]string, len(c.pages))
+		for page := range c.pages {
+			c.routes[c.proc.Route(filepath.FromSlash(page))] = page
+		}
+	}
+	if !strings.HasSuffix(route, "/") {
+		route += "/"
+	}
+	page, ok := c.routes[route]
+	return page, ok
+}
+
+// pageAnchors returns the heading ids of a scanned page as it's published: an opening H1
+// that becomes the page title is removed from the body, so it has no anchor
+func (c *Checker) pageAnchors(page string) map[string]bool {
+	if anchors, ok := c.anchors[page]; ok {
+		return anchors
+	}
+	content := c.pages[page].Content
+	parentDir := path.Dir(page)
+	if parentDir == "." {
+		parentDir = ""
+	}
+	if processed, err := frontmatter.InjectWithOptions(content, path.Base(page), parentDir, frontmatter.Options{Titles: c.titles}); err == nil {
+		content = processed
+	}
+	anchors := links.Anchors(content)
+	c.anchors[page] = anchors
+	return anchors
+}
@@ -0,0 +1,123 @@
+package checker
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// Output formats supported by Write
+const (
+	FormatHuman = "human"
+	FormatJSON  = "json"
+	FormatJUnit = "junit"
+)
+
+// Formats lists the valid output formats
+var Formats = []string{FormatHuman, FormatJSON, FormatJUnit}
+
+// ValidFormat checks if a format name is supported
+func ValidFormat(format string) bool {
+	for _, f := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// Write renders a report in the given format
+func Write(w io.Writer, report *Report, format string) error {
+	switch format {
+	case FormatHuman:
+		return writeHuman(w, report)
+	case FormatJSON:
+		return writeJSON(w, report)
+	case FormatJUnit:
+		return writeJUnit(w, report)
+	default:
+		return fmt.Errorf("unknown format %q (valid formats: human, json, junit)", format)
+	}
+}
+
+// writeHuman prints one file:line line per problem and a summary
+func writeHuman(w io.Writer, report *Report) error {
+	for _, p := range report.Problems {
+		if _, err := fmt.Fprintf(w, "%s\n", p); err != nil {
+			return err
+		}
+	}
+
+	if report.OK() {
+		_, err := fmt.Fprintf(w, "✅ Checked %d links in %d files, no broken references\n", report.Links, len(report.Files))
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "\n❌ Found %d broken references (checked %d links in %d files)\n",
+		len(report.Problems), report.Links, len(report.Files))
+	return err
+}
+
+// writeJSON prints the report as an indented JSON document
+func writeJSON(w io.Writer, report *Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// junitTestSuites is the root element of a JUnit XML report
+type junitTestSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string         `xml:"name,attr"`
+	ClassName string         `xml:"classname,attr"`
+	Failures  []junitFailure `xml:"failure"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit prints the report as JUnit XML with one test case per page
+func writeJUnit(w io.Writer, report *Report) error {
+	byFile := make(map[string][]Problem)
+	for _, p := range report.Problems {
+		byFile[p.File] = append(byFile[p.File], p)
+	}
+
+	suite := junitSuite{Name: "flashdoc check", Tests: len(report.Files)}
+	for _, file := range report.Files {
+		tc := junitTestCase{Name: file, ClassName: "flashdoc.check"}
+		for _, p := range byFile[file] {
+			tc.Failures = append(tc.Failures, junitFailure{Message: p.Message, Type: p.Kind, Text: p.String()})
+		}
+		if len(tc.Failures) > 0 {
+			suite.Failures++
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(junitTestSuites{Suites: []junitSuite{suite}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
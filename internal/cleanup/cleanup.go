@@ -2,17 +2,21 @@ package cleanup
 
 import (
 	"fmt"
-	"os"
 	"sync"
 
-	"github.com/heidene/flashdoc/internal/staticserver"
+	"github.com/heidene/flashdoc/internal/logging"
 	"github.com/heidene/flashdoc/internal/workspace"
 )
 
+// Server is a running server (static file server or Astro dev server) that must be stopped on exit
+type Server interface {
+	Stop() error
+}
+
 // Manager handles cleanup of resources when stardoc exits
 type Manager struct {
 	workspace    *workspace.Workspace
-	server       *staticserver.Server
+	server       Server
 	shutdownOnce sync.Once
 	mu           sync.Mutex
 }
@@ -24,8 +28,8 @@ func New(ws *workspace.Workspace) *Manager {
 	}
 }
 
-// RegisterServer adds the HTTP server to be stopped on cleanup
-func (m *Manager) RegisterServer(server *staticserver.Server) {
+// RegisterServer adds the server to be stopped on cleanup
+func (m *Manager) RegisterServer(server Server) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.server = server
@@ -38,33 +42,33 @@ func (m *Manager) Cleanup() error {
 	m.shutdownOnce.Do(func() {
 		// Stop server first
 		if err := m.StopServer(); err != nil {
-			fmt.Fprintf(os.Stderr, "Stopping server...\n")
+			logging.Infof("Stopping server...")
 			cleanupErr = fmt.Errorf("failed to stop server: %w", err)
 		}
 
 		// Remove workspace
-		fmt.Fprintf(os.Stderr, "Cleaning up workspace...\n")
+		logging.Infof("🧹 Cleaning up workspace...")
 		if m.workspace != nil {
 			if err := m.workspace.Cleanup(); err != nil {
 				if cleanupErr != nil {
 					cleanupErr = fmt.Errorf("%v; failed to cleanup workspace: %w", cleanupErr, err)
 				} else {
 					// Log warning but don't fail if workspace cleanup fails
-					fmt.Fprintf(os.Stderr, "Warning: failed to remove workspace %s: %v\n", m.workspace.Path, err)
-					fmt.Fprintf(os.Stderr, "You may need to manually remove this directory\n")
+					logging.Warnf("failed to remove workspace %s: %v", m.workspace.Path, err)
+					logging.Warnf("you may need to manually remove this directory")
 				}
 			}
 		}
 
 		if cleanupErr == nil {
-			fmt.Fprintf(os.Stderr, "Cleanup complete\n")
+			logging.Infof("Cleanup complete")
 		}
 	})
 
 	return cleanupErr
 }
 
-// StopServer gracefully shuts down the HTTP server
+// StopServer gracefully shuts down the registered server
 func (m *Manager) StopServer() error {
 	m.mu.Lock()
 	server := m.server
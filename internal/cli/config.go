@@ -2,17 +2,354 @@ package cli
 
 import (
 	"fmt"
+	"path/filepath"
 	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/heidene/flashdoc/internal/checker"
+	"github.com/heidene/flashdoc/internal/config"
+	"github.com/heidene/flashdoc/internal/frontmatter"
+	"github.com/heidene/flashdoc/internal/logging"
+	"github.com/heidene/flashdoc/internal/mdbook"
+	"github.com/heidene/flashdoc/internal/mkdocs"
+	"github.com/heidene/flashdoc/internal/pkgmanager"
+	"github.com/heidene/flashdoc/internal/theme"
 )
 
-// Config holds the CLI configuration parsed from flags and arguments
-type Config struct {
+// Command is the configuration of the subcommand selected on the command line
+type Command interface {
+	// Name returns the subcommand name
+	Name() string
+	// Validate checks the configuration before the command runs
+	Validate() error
+}
+
+// LogConfig holds the output flags shared by all commands
+type LogConfig struct {
+	Quiet      bool   // Only show results, warnings and errors
+	Verbose    bool   // Show debug messages, timings and subprocess output
+	Timestamps bool   // Prefix messages with the time
+	Format     string // Log format: text or json
+}
+
+// Validate checks the log format and that quiet and verbose aren't combined
+func (c *LogConfig) Validate() error {
+	if c.Quiet && c.Verbose {
+		return fmt.Errorf("--quiet and --verbose cannot be used together")
+	}
+	if !logging.ValidFormat(c.Format) {
+		return fmt.Errorf("invalid log format %q (valid formats: text, json)", c.Format)
+	}
+	return nil
+}
+
+// Level returns the lowest level of messages to show
+func (c *LogConfig) Level() logging.Level {
+	switch {
+	case c.Quiet:
+		return logging.LevelWarn
+	case c.Verbose:
+		return logging.LevelDebug
+	}
+	return logging.LevelInfo
+}
+
+// SiteConfig holds the settings shared by the commands that build a site
+type SiteConfig struct {
 	SourceDir      string
 	Title          string
-	Port           int
-	NoOpen         bool
 	ForceReinstall bool
-	ExportPath     string // Path to export static build, empty means no export
+	BreakLock      bool     // Remove the install lock held by another (hung) install
+	Renderer       string   // Site renderer: auto, astro or native
+	PackageManager string   // Package manager for the Astro site: auto, pnpm, bun, npm, yarn or deno
+	Include        []string // Gitignore-style patterns of ignored paths to publish anyway
+	Components     string   // Directory of MDX components inside the source directory
+	Titles         string   // Page title strategy: h1, filename or both
+	FixFrontmatter bool     // Repair or drop frontmatter values Starlight rejects instead of failing
+	AccentColor    string   // Accent color preset or hex color, empty for the default
+	SummaryOnly    bool     // Only publish the pages SUMMARY.md lists
+
+	// Settings from the project config file (.flashdoc.yaml / flashdoc.toml)
+	ConfigFile   string            // Path of the loaded config file, empty if none
+	Exclude      []string          // Gitignore-style patterns of source paths to skip (config file, then --exclude)
+	SidebarOrder []string          // Source paths in sidebar order
+	Logo         string            // Logo image path
+	Favicon      string            // Favicon path
+	CustomCSS    []string          // Stylesheet paths applied after the theme's
+	Social       map[string]string // Social icon name -> link
+
+	MkDocs *mkdocs.Project // MkDocs project the site is previewed from, nil if none
+	Book   *mdbook.Book    // mdBook or GitBook book the site is previewed from, nil if none
+}
+
+// Validate checks the source directory, renderer, package manager, accent color and title strategy
+func (c *SiteConfig) Validate() error {
+	if err := ValidatePath(c.SourceDir); err != nil {
+		return err
+	}
+	if !ValidRenderer(c.Renderer) {
+		return fmt.Errorf("invalid renderer %q (valid renderers: auto, astro, native)", c.Renderer)
+	}
+	if !pkgmanager.Valid(c.PackageManager) {
+		return fmt.Errorf("unsupported package manager: %s (supported: %s)", c.PackageManager, strings.Join(pkgmanager.Names, ", "))
+	}
+	if _, err := theme.ParseAccent(c.AccentColor); err != nil {
+		return err
+	}
+	if !frontmatter.ValidTitleStrategy(c.Titles) {
+		return fmt.Errorf("invalid title strategy %q (valid strategies: h1, filename, both)", c.Titles)
+	}
+	if c.Components != "" {
+		return ValidateComponents(c.SourceDir, c.Components)
+	}
+	return nil
+}
+
+// ComponentsRel returns the components directory relative to the source directory
+func (c *SiteConfig) ComponentsRel() string {
+	rel, err := filepath.Rel(mustAbs(c.SourceDir), mustAbs(c.Components))
+	if err != nil {
+		return c.Components
+	}
+	return rel
+}
+
+// applyMkDocs fills in what neither the flags nor the project config set from the MkDocs
+// project: the site name, the extra stylesheets and a social link to the repository
+func (c *SiteConfig) applyMkDocs(project *mkdocs.Project) {
+	c.MkDocs = project
+	if c.Title == "" {
+		c.Title = project.SiteName
+	}
+	if len(c.CustomCSS) == 0 {
+		c.CustomCSS = project.ExtraCSS
+	}
+	if icon := project.SocialIcon(); icon != "" && c.Social[icon] == "" {
+		social := map[string]string{icon: project.RepoURL}
+		for name, link := range c.Social {
+			social[name] = link
+		}
+		c.Social = social
+	}
+}
+
+// applyBook fills in the title from book.toml if neither the flags nor the project config
+// set one, follows SUMMARY.md for the sidebar order and hides it as a page. With
+// --summary-only the pages it doesn't list are left out, unless an exclude says otherwise.
+func (c *SiteConfig) applyBook(book *mdbook.Book) {
+	c.Book = book
+	if c.Title == "" {
+		c.Title = book.Title
+	}
+	if len(c.SidebarOrder) == 0 {
+		c.SidebarOrder = book.Pages()
+	}
+
+	var excludes []string
+	if c.SummaryOnly {
+		excludes = append(excludes, "*.md", "*.mdx")
+		for _, page := range book.Pages() {
+			excludes = append(excludes, "!/"+page)
+		}
+	}
+	c.Exclude = append(append(excludes, c.Exclude...), "/"+mdbook.SummaryName)
+}
+
+// applyProjectConfig fills in settings from the project config. Settings that have a flag
+// are only taken from the file when flagSet reports the flag wasn't given. Paths in the
+// file are relative to the source directory.
+func (c *SiteConfig) applyProjectConfig(file *config.File, flagSet func(name string) bool) {
+	c.ConfigFile = file.Path
+	c.Exclude = withConfigExcludes(file, c.Exclude)
+	c.SidebarOrder = file.Sidebar
+	c.Social = file.Social
+
+	if file.Title != "" && !flagSet("title") {
+		c.Title = file.Title
+	}
+	if file.Logo != "" {
+		c.Logo = resolveSourcePath(c.SourceDir, file.Logo)
+	}
+	if file.Favicon != "" {
+		c.Favicon = resolveSourcePath(c.SourceDir, file.Favicon)
+	}
+	for _, css := range file.CustomCSS {
+		c.CustomCSS = append(c.CustomCSS, resolveSourcePath(c.SourceDir, css))
+	}
+	if file.AccentColor != "" && !flagSet("accent-color") {
+		c.AccentColor = file.AccentColor
+	}
+	if file.Components != "" && !flagSet("components") {
+		c.Components = resolveSourcePath(c.SourceDir, file.Components)
+	}
+	if file.Titles != "" && !flagSet("titles") {
+		c.Titles = file.Titles
+	}
+	if file.PackageManager != "" && !flagSet("package-manager") {
+		c.PackageManager = file.PackageManager
+	}
+}
+
+// ServeConfig configures `flashdoc serve`
+type ServeConfig struct {
+	SiteConfig
+	Port   int
+	NoOpen bool
+	Watch  bool // Rebuild and live-reload when source files change
+	Dev    bool // Serve with the Astro dev server (HMR) instead of a static build
+}
+
+// Name returns "serve"
+func (c *ServeConfig) Name() string { return "serve" }
+
+// Validate checks the site settings, the port and the serving mode
+func (c *ServeConfig) Validate() error {
+	if err := c.SiteConfig.Validate(); err != nil {
+		return err
+	}
+	if err := ValidatePort(c.Port); err != nil {
+		return err
+	}
+	if c.Dev && c.Renderer == RendererNative {
+		return fmt.Errorf("--dev requires the astro renderer")
+	}
+	return nil
+}
+
+// BuildConfig configures `flashdoc build`
+type BuildConfig struct {
+	SiteConfig
+}
+
+// Name returns "build"
+func (c *BuildConfig) Name() string { return "build" }
+
+// ExportConfig configures `flashdoc export`
+type ExportConfig struct {
+	SiteConfig
+	OutputDir string // Directory the static site is copied to
+}
+
+// Name returns "export"
+func (c *ExportConfig) Name() string { return "export" }
+
+// Validate checks the site settings and the output directory
+func (c *ExportConfig) Validate() error {
+	if err := c.SiteConfig.Validate(); err != nil {
+		return err
+	}
+	if c.OutputDir == "" {
+		return fmt.Errorf("output directory is required")
+	}
+	return nil
+}
+
+// CheckConfig configures `flashdoc check`
+type CheckConfig struct {
+	SourceDir   string
+	ConfigFile  string   // Path of the loaded config file, empty if none
+	Exclude     []string // Gitignore-style patterns of source paths to skip (config file, then --exclude)
+	Include     []string // Gitignore-style patterns of ignored paths to check anyway
+	Titles      string   // Page title strategy from the config file, empty for the default
+	Includes    bool     // Expand mdBook includes, as the source is a book
+	Admonitions bool     // Convert MkDocs admonitions, as the source is an MkDocs project
+	Format      string   // Report format: human, json or junit
+	Output      string   // File to write the report to, empty for stdout
+}
+
+// Name returns "check"
+func (c *CheckConfig) Name() string { return "check" }
+
+// Validate checks the source directory and report format
+func (c *CheckConfig) Validate() error {
+	if err := ValidatePath(c.SourceDir); err != nil {
+		return err
+	}
+	if !checker.ValidFormat(c.Format) {
+		return fmt.Errorf("invalid format %q (valid formats: human, json, junit)", c.Format)
+	}
+	return nil
+}
+
+// CacheConfig configures the `flashdoc cache` subcommands
+type CacheConfig struct {
+	Action    string        // Cache subcommand: info, prune, clear or path
+	OlderThan time.Duration // prune: remove runs last used longer ago than this
+}
+
+// Name returns "cache"
+func (c *CacheConfig) Name() string { return "cache" }
+
+// Validate checks the cache action
+func (c *CacheConfig) Validate() error {
+	switch c.Action {
+	case CacheInfo, CacheClear, CachePath:
+		return nil
+	case CachePrune:
+		if c.OlderThan <= 0 {
+			return fmt.Errorf("--older-than must be positive, got %s", c.OlderThan)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown cache command %q", c.Action)
+}
+
+// Cache subcommands
+const (
+	CacheInfo  = "info"  // Show sizes, the installed template and runs
+	CachePrune = "prune" // Remove old runs that are not in use
+	CacheClear = "clear" // Remove idle runs and the shared install
+	CachePath  = "path"  // Print the cache directory
+)
+
+// defaultPruneAge matches the cleanup of old runs done on every start
+const defaultPruneAge = 24 * time.Hour
+
+// DoctorConfig configures `flashdoc doctor`
+type DoctorConfig struct {
+	SourceDir string // Optional docs directory whose project config is checked
+}
+
+// Name returns "doctor"
+func (c *DoctorConfig) Name() string { return "doctor" }
+
+// Validate checks the optional source directory
+func (c *DoctorConfig) Validate() error {
+	if c.SourceDir == "" {
+		return nil
+	}
+	return ValidatePath(c.SourceDir)
+}
+
+// Site renderers selectable with --renderer
+const (
+	RendererAuto   = "auto"   // Astro when a package manager is installed, native otherwise
+	RendererAstro  = "astro"  // Starlight built by Astro (requires Node.js)
+	RendererNative = "native" // Built-in Go renderer, no Node.js needed
+)
+
+// ValidRenderer checks if name is a known renderer
+func ValidRenderer(name string) bool {
+	switch name {
+	case RendererAuto, RendererAstro, RendererNative:
+		return true
+	}
+	return false
+}
+
+// withConfigExcludes puts the config file's excludes before the --exclude flags,
+// so the flags win where patterns conflict
+func withConfigExcludes(file *config.File, flagExcludes []string) []string {
+	return append(append([]string{}, file.Exclude...), flagExcludes...)
+}
+
+// resolveSourcePath resolves a config file path against the source directory
+func resolveSourcePath(sourceDir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(sourceDir, path)
 }
 
 // Version variables - injected at build time via ldflags
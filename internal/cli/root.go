@@ -1,167 +1,372 @@
 package cli
 
 import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/heidene/flashdoc/internal/checker"
+	"github.com/heidene/flashdoc/internal/config"
+	"github.com/heidene/flashdoc/internal/frontmatter"
+	"github.com/heidene/flashdoc/internal/logging"
+	"github.com/heidene/flashdoc/internal/mdbook"
+	"github.com/heidene/flashdoc/internal/mkdocs"
+	"github.com/heidene/flashdoc/internal/pkgmanager"
+	"github.com/heidene/flashdoc/internal/theme"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
 
-// exportValue implements pflag.Value for the --export flag
-type exportValue struct {
-	path *string
+// defaultExportPath is used when export is given no output directory
+const defaultExportPath = "./export-doc"
+
+// NewRootCommand creates the root cobra command. `flashdoc <directory>` is shorthand for
+// `flashdoc serve <directory>`. The selected command's configuration is stored in parsed.
+func NewRootCommand(parsed *Command) *cobra.Command {
+	return newRootCommand(parsed, &LogConfig{})
 }
 
-func (e *exportValue) String() string {
-	if e.path == nil {
-		return ""
+// newRootCommand creates the root cobra command, storing the output flags in log
+func newRootCommand(parsed *Command, log *LogConfig) *cobra.Command {
+	serve := &ServeConfig{}
+	rootCmd := &cobra.Command{
+		Use:   "flashdoc <directory>",
+		Short: "Generate and serve a Starlight documentation site from markdown files",
+		Long: "Generate and serve a Starlight documentation site from markdown files.\n\n" +
+			"flashdoc <directory> is shorthand for flashdoc serve <directory>.",
+		Version:      FullVersion(),
+		Args:         cobra.ExactArgs(1),
+		RunE:         serveRunner(serve, parsed),
+		SilenceUsage: true,
 	}
-	return *e.path
+	addServeFlags(rootCmd.Flags(), serve)
+	addLogFlags(rootCmd.PersistentFlags(), log)
+
+	rootCmd.AddCommand(
+		newServeCommand(parsed),
+		newBuildCommand(parsed),
+		newExportCommand(parsed),
+		newCheckCommand(parsed),
+		newCacheCommand(parsed),
+		newDoctorCommand(parsed),
+	)
+
+	return rootCmd
 }
 
-func (e *exportValue) Set(val string) error {
-	*e.path = val
-	return nil
+// newServeCommand creates the serve subcommand
+func newServeCommand(parsed *Command) *cobra.Command {
+	serve := &ServeConfig{}
+	serveCmd := &cobra.Command{
+		Use:          "serve <directory>",
+		Short:        "Build the site and serve it locally",
+		Args:         cobra.ExactArgs(1),
+		RunE:         serveRunner(serve, parsed),
+		SilenceUsage: true,
+	}
+	addServeFlags(serveCmd.Flags(), serve)
+	return serveCmd
 }
 
-func (e *exportValue) Type() string {
-	return "string"
+// serveRunner returns the RunE shared by the root and serve commands
+func serveRunner(serve *ServeConfig, parsed *Command) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		serve.SourceDir = args[0]
+		file, err := loadSiteConfig(&serve.SiteConfig, cmd.Flags())
+		if err != nil {
+			return err
+		}
+		if file.Port != 0 && !cmd.Flags().Changed("port") {
+			serve.Port = file.Port
+		}
+		return finish(serve, parsed)
+	}
 }
 
-var _ pflag.Value = (*exportValue)(nil)
+// newBuildCommand creates the build subcommand
+func newBuildCommand(parsed *Command) *cobra.Command {
+	build := &BuildConfig{}
+	buildCmd := &cobra.Command{
+		Use:   "build <directory>",
+		Short: "Build the site without serving it, failing on build errors",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			build.SourceDir = args[0]
+			if _, err := loadSiteConfig(&build.SiteConfig, cmd.Flags()); err != nil {
+				return err
+			}
+			return finish(build, parsed)
+		},
+		SilenceUsage: true,
+	}
+	addSiteFlags(buildCmd.Flags(), &build.SiteConfig)
+	return buildCmd
+}
 
-var (
-	title          string
-	port           int
-	noOpen         bool
-	forceReinstall bool
-	exportPath     string
-)
+// newExportCommand creates the export subcommand
+func newExportCommand(parsed *Command) *cobra.Command {
+	export := &ExportConfig{}
+	exportCmd := &cobra.Command{
+		Use:   "export <directory> [output]",
+		Short: "Build the site and copy it to a directory (default: ./export-doc)",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			export.SourceDir = args[0]
+			file, err := loadSiteConfig(&export.SiteConfig, cmd.Flags())
+			if err != nil {
+				return err
+			}
 
-// customArgsValidator validates arguments allowing for --export path
-func customArgsValidator(cmd *cobra.Command, args []string) error {
-	// We need exactly 1 positional arg (the directory)
-	// But if --export is used with a space, there might be 2 args
-	if len(args) < 1 {
-		return cobra.MinimumNArgs(1)(cmd, args)
-	}
-	if len(args) > 2 {
-		return cobra.MaximumNArgs(2)(cmd, args)
+			// An explicit output wins over the config file's export path
+			switch {
+			case len(args) == 2:
+				export.OutputDir = args[1]
+			case file.Export != "":
+				export.OutputDir = resolveSourcePath(export.SourceDir, file.Export)
+			default:
+				export.OutputDir = defaultExportPath
+			}
+			return finish(export, parsed)
+		},
+		SilenceUsage: true,
 	}
-	// If we have 2 args, the second one should be the export path
-	// This will be handled in Parse()
-	return nil
+	addSiteFlags(exportCmd.Flags(), &export.SiteConfig)
+	return exportCmd
 }
 
-// NewRootCommand creates the root cobra command
-func NewRootCommand() *cobra.Command {
-	rootCmd := &cobra.Command{
-		Use:          "stardoc <directory>",
-		Short:        "Generate and serve a Starlight documentation site from markdown files",
-		Version:      FullVersion(),
-		Args:         customArgsValidator,
-		RunE:         runStardoc,
+// newCheckCommand creates the check subcommand
+func newCheckCommand(parsed *Command) *cobra.Command {
+	check := &CheckConfig{}
+	checkCmd := &cobra.Command{
+		Use:   "check <directory>",
+		Short: "Check internal links, images and heading anchors without building the site",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			check.SourceDir = args[0]
+			if err := ValidatePath(check.SourceDir); err != nil {
+				return err
+			}
+			project, err := mkdocs.Find(check.SourceDir)
+			if err != nil {
+				return err
+			}
+			if project != nil {
+				check.SourceDir = project.DocsDir
+				check.Admonitions = true
+			} else {
+				book, err := mdbook.Find(check.SourceDir)
+				if err != nil {
+					return err
+				}
+				if book != nil {
+					check.SourceDir = book.SrcDir
+					check.Includes = true
+					check.Exclude = append(check.Exclude, "/"+mdbook.SummaryName)
+				}
+			}
+
+			// Excludes from the project config decide which pages are part of the site
+			file, err := loadProjectConfig(check.SourceDir)
+			if err != nil {
+				return err
+			}
+			check.ConfigFile = file.Path
+			check.Titles = file.Titles
+			check.Exclude = withConfigExcludes(file, check.Exclude)
+			return finish(check, parsed)
+		},
 		SilenceUsage: true,
 	}
 
-	rootCmd.Flags().StringVar(&title, "title", "", "Title for the documentation site")
-	rootCmd.Flags().IntVar(&port, "port", 4321, "Port for the dev server (1024-65535)")
-	rootCmd.Flags().BoolVar(&noOpen, "no-open", false, "Don't automatically open the browser")
-	rootCmd.Flags().BoolVar(&forceReinstall, "force-reinstall", false, "Force reinstall of dependencies even if cached")
+	checkCmd.Flags().StringVar(&check.Format, "format", checker.FormatHuman, "Report format (human, json, junit)")
+	checkCmd.Flags().StringVarP(&check.Output, "output", "o", "", "Write the report to a file instead of stdout")
+	addIgnoreFlags(checkCmd.Flags(), &check.Exclude, &check.Include)
 
-	// Export flag with optional value
-	exportFlag := rootCmd.Flags().VarPF(
-		&exportValue{path: &exportPath},
-		"export",
-		"",
-		"Export static build to directory (default: ./export-doc)",
+	return checkCmd
+}
+
+// newCacheCommand creates the cache command and its subcommands
+func newCacheCommand(parsed *Command) *cobra.Command {
+	cacheCmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the shared Starlight install and run directories",
+		Args:  cobra.NoArgs,
+	}
+
+	cacheCmd.AddCommand(
+		newCacheActionCommand(CacheInfo, "Show cache sizes, the installed template, package manager and last use", parsed, nil),
+		newCacheActionCommand(CachePrune, "Remove runs older than --older-than that are not in use", parsed, func(cmd *cobra.Command, cache *CacheConfig) {
+			cmd.Flags().DurationVar(&cache.OlderThan, "older-than", defaultPruneAge, "Remove runs last used longer ago than this")
+		}),
+		newCacheActionCommand(CacheClear, "Remove idle runs and the shared install (reinstalled on next run)", parsed, nil),
+		newCacheActionCommand(CachePath, "Print the cache directory", parsed, nil),
 	)
-	exportFlag.NoOptDefVal = "./export-doc"
 
-	return rootCmd
+	return cacheCmd
 }
 
-func runStardoc(cmd *cobra.Command, args []string) error {
-	sourceDir := args[0]
-
-	// Validate the source directory
-	if err := ValidatePath(sourceDir); err != nil {
-		return err
+// newCacheActionCommand creates a cache subcommand; addFlags registers its flags, if any
+func newCacheActionCommand(action, short string, parsed *Command, addFlags func(*cobra.Command, *CacheConfig)) *cobra.Command {
+	cache := &CacheConfig{Action: action}
+	actionCmd := &cobra.Command{
+		Use:   action,
+		Short: short,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return finish(cache, parsed)
+		},
+		SilenceUsage: true,
 	}
-
-	// Validate the port
-	if err := ValidatePort(port); err != nil {
-		return err
+	if addFlags != nil {
+		addFlags(actionCmd, cache)
 	}
+	return actionCmd
+}
 
-	// Store the configuration
-	config := &Config{
-		SourceDir:      sourceDir,
-		Title:          title,
-		Port:           port,
-		NoOpen:         noOpen,
-		ForceReinstall: forceReinstall,
+// newDoctorCommand creates the doctor subcommand
+func newDoctorCommand(parsed *Command) *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor [directory]",
+		Short: "Check that Node.js, a package manager and the cache are ready",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			doctor := &DoctorConfig{}
+			if len(args) == 1 {
+				doctor.SourceDir = args[0]
+			}
+			return finish(doctor, parsed)
+		},
+		SilenceUsage: true,
 	}
+}
 
-	// For now, just store it - actual execution will be wired up in main.go
-	_ = config
+// addSiteFlags registers the flags shared by the commands that build a site
+func addSiteFlags(flags *pflag.FlagSet, site *SiteConfig) {
+	flags.StringVar(&site.Title, "title", "", "Title for the documentation site")
+	flags.BoolVar(&site.ForceReinstall, "force-reinstall", false, "Force reinstall of dependencies even if cached")
+	flags.BoolVar(&site.BreakLock, "break-lock", false, "Take over the install lock from another flashdoc install that is stuck")
+	flags.StringVar(&site.Renderer, "renderer", RendererAuto, "Site renderer (auto, astro, native)")
+	flags.StringVar(&site.PackageManager, "package-manager", pkgmanager.Auto, "Package manager for the Astro site (auto, pnpm, bun, npm, yarn, deno)")
+	flags.StringVar(&site.Components, "components", "", "Directory of MDX components in the source directory, copied so imports resolve")
+	flags.StringVar(&site.Titles, "titles", frontmatter.TitleH1, "Page titles from the first H1 or the filename (h1, filename, both)")
+	flags.BoolVar(&site.FixFrontmatter, "fix-frontmatter", false, "Repair or drop frontmatter values Starlight rejects instead of failing the build")
+	flags.BoolVar(&site.SummaryOnly, "summary-only", false, "In an mdBook or GitBook book, only publish the pages SUMMARY.md lists")
+	flags.StringVar(&site.AccentColor, "accent-color", "", "Accent color: a hex color like #2563eb or a preset ("+strings.Join(theme.PresetNames(), ", ")+")")
+	addIgnoreFlags(flags, &site.Exclude, &site.Include)
+}
 
-	return nil
+// addLogFlags registers the output flags every command accepts
+func addLogFlags(flags *pflag.FlagSet, log *LogConfig) {
+	flags.BoolVarP(&log.Quiet, "quiet", "q", false, "Only show results, warnings and errors")
+	flags.BoolVar(&log.Verbose, "verbose", false, "Show debug messages, timings and install/build output")
+	flags.BoolVar(&log.Timestamps, "timestamps", false, "Prefix messages with the time (HH:MM:SS)")
+	flags.StringVar(&log.Format, "log-format", logging.FormatText, "Log format (text, json)")
 }
 
-// Parse parses the command line arguments and returns the configuration
-func Parse(args []string) (*Config, bool, error) {
-	rootCmd := NewRootCommand()
-	rootCmd.SetArgs(args)
+// addIgnoreFlags registers the flags that adjust which source paths are ignored
+func addIgnoreFlags(flags *pflag.FlagSet, exclude, include *[]string) {
+	flags.StringArrayVar(exclude, "exclude", nil, "Skip source paths matching a gitignore-style pattern (repeatable)")
+	flags.StringArrayVar(include, "include", nil, "Publish paths ignored by default or by ignore files, e.g. --include build (repeatable)")
+}
 
-	if err := rootCmd.Execute(); err != nil {
-		return nil, false, err
+// addServeFlags registers the serve flags
+func addServeFlags(flags *pflag.FlagSet, serve *ServeConfig) {
+	addSiteFlags(flags, &serve.SiteConfig)
+	flags.IntVar(&serve.Port, "port", 4321, "Port for the dev server (1024-65535)")
+	flags.BoolVar(&serve.NoOpen, "no-open", false, "Don't automatically open the browser")
+	flags.BoolVar(&serve.Watch, "watch", false, "Rebuild and reload the browser when source files change")
+	flags.BoolVar(&serve.Dev, "dev", false, "Serve with the Astro dev server and hot module replacement")
+}
+
+// loadSiteConfig validates the source directory and applies its project config to site.
+// In an MkDocs project the site is built from the docs_dir, which holds the project config,
+// and mkdocs.yml fills in the rest. An mdBook book is built from its src directory the same way.
+func loadSiteConfig(site *SiteConfig, flags *pflag.FlagSet) (*config.File, error) {
+	if err := ValidatePath(site.SourceDir); err != nil {
+		return nil, err
 	}
 
-	// Check if --help or --version was used (indicated by no args or just flags)
-	hasSourceDir := false
-	nonFlagArgs := []string{}
-	for _, arg := range args {
-		if arg != "" && arg[0] != '-' {
-			hasSourceDir = true
-			nonFlagArgs = append(nonFlagArgs, arg)
+	project, err := mkdocs.Find(site.SourceDir)
+	if err != nil {
+		return nil, err
+	}
+	if project != nil {
+		site.SourceDir = project.DocsDir
+		if err := ValidatePath(site.SourceDir); err != nil {
+			return nil, fmt.Errorf("%s: docs_dir: %w", project.Path, err)
 		}
 	}
 
-	// If no source directory provided, it means --help or --version was used
-	if !hasSourceDir {
-		return nil, true, nil
+	var book *mdbook.Book
+	if project == nil {
+		if book, err = mdbook.Find(site.SourceDir); err != nil {
+			return nil, err
+		}
+	}
+	if book != nil {
+		site.SourceDir = book.SrcDir
+	}
+
+	file, err := loadProjectConfig(site.SourceDir)
+	if err != nil {
+		return nil, err
 	}
+	site.applyProjectConfig(file, flags.Changed)
+	if project != nil {
+		site.applyMkDocs(project)
+	}
+	if book != nil {
+		site.applyBook(book)
+	}
+	return file, nil
+}
 
-	// Extract the source directory from args if available
-	sourceDir := ""
-	if len(nonFlagArgs) > 0 {
-		sourceDir = nonFlagArgs[0]
+// loadProjectConfig loads the project config file, then lets FLASHDOC_* variables override it
+func loadProjectConfig(sourceDir string) (*config.File, error) {
+	file, err := config.Load(sourceDir)
+	if err != nil {
+		return nil, err
 	}
+	if err := file.ApplyEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
 
-	// Handle --export with space-separated path
-	// If we have 2 non-flag args and exportPath is empty or is the default,
-	// the second arg is the export path
-	finalExportPath := exportPath
-	if len(nonFlagArgs) == 2 {
-		// Check if --export flag was present in args
-		exportFlagPresent := false
-		for _, arg := range args {
-			if arg == "--export" {
-				exportFlagPresent = true
-				break
-			}
-		}
-		if exportFlagPresent {
-			// Second non-flag arg is the export path
-			finalExportPath = nonFlagArgs[1]
-		}
+// finish validates the command's configuration and records it as the parsed command
+func finish(cmd Command, parsed *Command) error {
+	if err := cmd.Validate(); err != nil {
+		return err
 	}
+	*parsed = cmd
+	return nil
+}
+
+// Parse parses the command line arguments and returns the selected command's configuration.
+// The command is nil when only help or version information was shown.
+func Parse(args []string) (Command, error) {
+	cmd, _, err := ParseWithLogging(args)
+	return cmd, err
+}
+
+// ParseWithLogging is Parse, also returning the output flags. Errors are returned, not
+// printed, so they can be logged in the requested format; the output flags are filled in
+// as far as they could be parsed.
+func ParseWithLogging(args []string) (Command, *LogConfig, error) {
+	var parsed Command
+	log := &LogConfig{Format: logging.FormatText}
+	rootCmd := newRootCommand(&parsed, log)
+	rootCmd.SetArgs(args)
+	rootCmd.SilenceErrors = true
 
-	return &Config{
-		SourceDir:      sourceDir,
-		Title:          title,
-		Port:           port,
-		NoOpen:         noOpen,
-		ForceReinstall: forceReinstall,
-		ExportPath:     finalExportPath,
-	}, false, nil
+	if err := rootCmd.Execute(); err != nil {
+		return nil, log, err
+	}
+	if parsed == nil {
+		return nil, log, nil
+	}
+	if err := log.Validate(); err != nil {
+		return nil, log, err
+	}
+	return parsed, log, nil
 }
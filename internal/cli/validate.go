@@ -3,6 +3,8 @@ package cli
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 )
 
 // ValidatePath checks if the given path exists and is a directory
@@ -29,3 +31,26 @@ func ValidatePort(port int) error {
 	}
 	return nil
 }
+
+// ValidateComponents checks that the components directory exists inside the source directory,
+// where relative imports in MDX pages can reach it
+func ValidateComponents(sourceDir, dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return fmt.Errorf("components directory %s does not exist", dir)
+	}
+
+	rel, err := filepath.Rel(mustAbs(sourceDir), mustAbs(dir))
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("components directory %s must be inside %s", dir, sourceDir)
+	}
+	return nil
+}
+
+// mustAbs returns the absolute form of path, or path itself if it can't be resolved
+func mustAbs(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
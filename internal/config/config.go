@@ -0,0 +1,552 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/heidene/flashdoc/internal/frontmatter"
+	"github.com/heidene/flashdoc/internal/pkgmanager"
+	"github.com/heidene/flashdoc/internal/theme"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// FileNames lists the project config files flashdoc looks for, in order of precedence
+var FileNames = []string{
+	".flashdoc.yaml",
+	".flashdoc.yml",
+	"flashdoc.toml",
+	".flashdoc.toml",
+}
+
+// EnvPrefix is the prefix for environment variables that override config file values
+const EnvPrefix = "FLASHDOC_"
+
+// File holds settings loaded from a project config file
+type File struct {
+	Path    string            // Path of the loaded config file, empty if none was found
+	Title   string            // Site title
+	Port    int               // Port for the local server, 0 if unset
+	Exclude []string          // Glob patterns of source paths to skip
+	Sidebar []string          // Source paths in the order they should appear in the sidebar
+	Logo    string            // Logo image path, relative to the source directory
+	Favicon string            // Favicon path, relative to the source directory
+	Social  map[string]string // Social icon name -> link
+	Export  string            // Default directory for --export
+
+	Components     string // Directory of MDX components, relative to the source directory
+	Titles         string // Page title strategy: h1, filename or both
+	PackageManager string // Package manager for the Astro site: auto, pnpm, bun, npm, yarn or deno
+
+	CustomCSS   []string // Stylesheets, relative to the source directory
+	AccentColor string   // Accent color preset or hex color
+}
+
+// Error describes an invalid value in a config file
+type Error struct {
+	File string
+	Line int
+	Key  string
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	location := e.File
+	if e.Line > 0 {
+		location = fmt.Sprintf("%s:%d", e.File, e.Line)
+	}
+	if e.Key == "" {
+		return fmt.Sprintf("%s: %s", location, e.Msg)
+	}
+	return fmt.Sprintf("%s: %s: %s", location, e.Key, e.Msg)
+}
+
+// ValidationError collects every problem found in a config file
+type ValidationError struct {
+	Errors []*Error
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		lines[i] = err.Error()
+	}
+	return "invalid config file:\n  " + strings.Join(lines, "\n  ")
+}
+
+// Find returns the path of the config file in dir, or an empty string if there is none
+func Find(dir string) string {
+	for _, name := range FileNames {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
+
+// Load finds, parses and validates the config file in dir.
+// It returns an empty File when the directory has no config file.
+func Load(dir string) (*File, error) {
+	path := Find(dir)
+	if path == "" {
+		return &File{}, nil
+	}
+	return LoadFile(path)
+}
+
+// LoadFile parses and validates a specific config file
+func LoadFile(path string) (*File, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	name := filepath.Base(path)
+
+	var values map[string]interface{}
+	var lines map[string]int
+	if strings.HasSuffix(path, ".toml") {
+		values, lines, err = parseTOML(name, content)
+	} else {
+		values, lines, err = parseYAML(name, content)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := decode(name, values, lines)
+	if err != nil {
+		return nil, err
+	}
+	f.Path = path
+
+	return f, nil
+}
+
+// parseYAML decodes YAML into generic values, recording the line of every key
+func parseYAML(name string, content []byte) (map[string]interface{}, map[string]int, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, nil, &Error{File: name, Line: yamlErrorLine(err), Msg: strings.TrimPrefix(err.Error(), "yaml: ")}
+	}
+
+	values := make(map[string]interface{})
+	lines := make(map[string]int)
+	if len(doc.Content) == 0 {
+		// Empty file
+		return values, lines, nil
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, nil, &Error{File: name, Line: root.Line, Msg: "config file must be a mapping of keys to values"}
+	}
+
+	recordYAMLLines(root, "", lines)
+	if err := root.Decode(&values); err != nil {
+		return nil, nil, &Error{File: name, Line: yamlErrorLine(err), Msg: strings.TrimPrefix(err.Error(), "yaml: ")}
+	}
+
+	return values, lines, nil
+}
+
+// recordYAMLLines maps dotted key paths to the line they're declared on
+func recordYAMLLines(node *yaml.Node, prefix string, lines map[string]int) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i].Value
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		lines[key] = node.Content[i].Line
+		recordYAMLLines(node.Content[i+1], key, lines)
+	}
+}
+
+// yamlErrorLine extracts the line number from a yaml.v3 error message
+func yamlErrorLine(err error) int {
+	msg := err.Error()
+	idx := strings.Index(msg, "line ")
+	if idx == -1 {
+		return 0
+	}
+	digits := msg[idx+len("line "):]
+	end := strings.IndexFunc(digits, func(r rune) bool { return r < '0' || r > '9' })
+	if end > 0 {
+		digits = digits[:end]
+	}
+	line, _ := strconv.Atoi(digits)
+	return line
+}
+
+// parseTOML decodes TOML into generic values, recording the line of every key
+func parseTOML(name string, content []byte) (map[string]interface{}, map[string]int, error) {
+	values := make(map[string]interface{})
+	if err := toml.Unmarshal(content, &values); err != nil {
+		var decodeErr *toml.DecodeError
+		if errors.As(err, &decodeErr) {
+			line, _ := decodeErr.Position()
+			return nil, nil, &Error{File: name, Line: line, Msg: decodeErr.Error()}
+		}
+		return nil, nil, &Error{File: name, Msg: err.Error()}
+	}
+
+	return values, tomlLines(content), nil
+}
+
+// tomlLines finds the line of every key and table header in a TOML document
+func tomlLines(content []byte) map[string]int {
+	lines := make(map[string]int)
+	table := ""
+
+	for i, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			table = strings.Trim(line, "[] ")
+			if _, seen := lines[table]; !seen {
+				lines[table] = i + 1
+			}
+			continue
+		}
+
+		eq := strings.Index(line, "=")
+		if eq == -1 {
+			continue
+		}
+		key := strings.Trim(strings.TrimSpace(line[:eq]), `"'`)
+		if table != "" {
+			key = table + "." + key
+		}
+		if _, seen := lines[key]; !seen {
+			lines[key] = i + 1
+		}
+	}
+
+	return lines
+}
+
+// knownKeys lists the top-level keys accepted in a config file
+var knownKeys = []string{"title", "port", "exclude", "sidebar", "logo", "social", "export", "components", "titles", "packageManager", "favicon", "customCss", "accentColor"}
+
+// decode validates generic config values against the schema and converts them into a File
+func decode(name string, values map[string]interface{}, lines map[string]int) (*File, error) {
+	f := &File{}
+	var errs []*Error
+
+	fail := func(key, format string, args ...interface{}) {
+		errs = append(errs, &Error{File: name, Line: lines[key], Key: key, Msg: fmt.Sprintf(format, args...)})
+	}
+
+	// Report keys in file order for readable output
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if lines[keys[i]] != lines[keys[j]] {
+			return lines[keys[i]] < lines[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	for _, key := range keys {
+		value := values[key]
+
+		switch key {
+		case "title":
+			s, ok := value.(string)
+			if !ok || strings.TrimSpace(s) == "" {
+				fail(key, "must be a non-empty string")
+				continue
+			}
+			f.Title = s
+
+		case "port":
+			port, ok := toInt(value)
+			if !ok {
+				fail(key, "must be an integer, got %s", describe(value))
+				continue
+			}
+			if port < 1024 || port > 65535 {
+				fail(key, "must be between 1024 and 65535, got %d", port)
+				continue
+			}
+			f.Port = port
+
+		case "exclude", "sidebar":
+			list, ok := toStringList(value)
+			if !ok {
+				fail(key, "must be a list of strings, got %s", describe(value))
+				continue
+			}
+			if key == "exclude" {
+				for _, pattern := range list {
+					if _, err := filepath.Match(pattern, ""); err != nil {
+						fail(key, "invalid glob pattern %q", pattern)
+					}
+				}
+				f.Exclude = list
+			} else {
+				f.Sidebar = list
+			}
+
+		case "customCss":
+			list, ok := toStringList(value)
+			if !ok {
+				fail(key, "must be a list of paths, got %s", describe(value))
+				continue
+			}
+			f.CustomCSS = list
+
+		case "accentColor":
+			s, ok := value.(string)
+			if _, err := theme.ParseAccent(s); !ok || s == "" || err != nil {
+				fail(key, "must be a hex color or one of %s, got %s", accentPresets(), describe(value))
+				continue
+			}
+			f.AccentColor = s
+
+		case "logo", "favicon", "export", "components":
+			s, ok := value.(string)
+			if !ok || strings.TrimSpace(s) == "" {
+				fail(key, "must be a path, got %s", describe(value))
+				continue
+			}
+			switch key {
+			case "logo":
+				f.Logo = s
+			case "favicon":
+				f.Favicon = s
+			case "export":
+				f.Export = s
+			default:
+				f.Components = s
+			}
+
+		case "titles":
+			s, ok := value.(string)
+			if !ok || !frontmatter.ValidTitleStrategy(s) {
+				fail(key, "must be one of h1, filename, both, got %s", describe(value))
+				continue
+			}
+			f.Titles = s
+
+		case "packageManager":
+			s, ok := value.(string)
+			if !ok || !pkgmanager.Valid(s) {
+				fail(key, "must be one of %s, got %s", packageManagers(), describe(value))
+				continue
+			}
+			f.PackageManager = s
+
+		case "social":
+			links, ok := value.(map[string]interface{})
+			if !ok {
+				fail(key, "must be a mapping of icon names to links, e.g. github: https://github.com/you/repo")
+				continue
+			}
+			f.Social = make(map[string]string, len(links))
+			for icon, link := range links {
+				href, ok := link.(string)
+				if !ok || !isLink(href) {
+					fail(key+"."+icon, "must be an http(s) or mailto link, got %s", describe(link))
+					continue
+				}
+				f.Social[icon] = href
+			}
+
+		default:
+			if suggestion := suggest(key); suggestion != "" {
+				fail(key, "unknown key (did you mean %q?)", suggestion)
+			} else {
+				fail(key, "unknown key (valid keys: %s)", strings.Join(knownKeys, ", "))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, &ValidationError{Errors: errs}
+	}
+
+	return f, nil
+}
+
+// ApplyEnv overrides file values with FLASHDOC_* environment variables
+func (f *File) ApplyEnv(lookup func(string) (string, bool)) error {
+	if v, ok := lookup(EnvPrefix + "TITLE"); ok && v != "" {
+		f.Title = v
+	}
+	if v, ok := lookup(EnvPrefix + "PORT"); ok && v != "" {
+		port, err := strconv.Atoi(v)
+		if err != nil || port < 1024 || port > 65535 {
+			return fmt.Errorf("%sPORT must be a port between 1024 and 65535, got %q", EnvPrefix, v)
+		}
+		f.Port = port
+	}
+	if v, ok := lookup(EnvPrefix + "EXCLUDE"); ok && v != "" {
+		f.Exclude = splitList(v)
+	}
+	if v, ok := lookup(EnvPrefix + "LOGO"); ok && v != "" {
+		f.Logo = v
+	}
+	if v, ok := lookup(EnvPrefix + "FAVICON"); ok && v != "" {
+		f.Favicon = v
+	}
+	if v, ok := lookup(EnvPrefix + "CUSTOM_CSS"); ok && v != "" {
+		f.CustomCSS = splitList(v)
+	}
+	if v, ok := lookup(EnvPrefix + "ACCENT_COLOR"); ok && v != "" {
+		if _, err := theme.ParseAccent(v); err != nil {
+			return fmt.Errorf("%sACCENT_COLOR must be a hex color or one of %s, got %q", EnvPrefix, accentPresets(), v)
+		}
+		f.AccentColor = v
+	}
+	if v, ok := lookup(EnvPrefix + "EXPORT"); ok && v != "" {
+		f.Export = v
+	}
+	if v, ok := lookup(EnvPrefix + "COMPONENTS"); ok && v != "" {
+		f.Components = v
+	}
+	if v, ok := lookup(EnvPrefix + "TITLES"); ok && v != "" {
+		if !frontmatter.ValidTitleStrategy(v) {
+			return fmt.Errorf("%sTITLES must be one of h1, filename, both, got %q", EnvPrefix, v)
+		}
+		f.Titles = v
+	}
+	if v, ok := lookup(EnvPrefix + "PACKAGE_MANAGER"); ok && v != "" {
+		if !pkgmanager.Valid(v) {
+			return fmt.Errorf("%sPACKAGE_MANAGER must be one of %s, got %q", EnvPrefix, packageManagers(), v)
+		}
+		f.PackageManager = v
+	}
+	return nil
+}
+
+// packageManagers lists the values accepted for packageManager
+func packageManagers() string {
+	return strings.Join(append([]string{pkgmanager.Auto}, pkgmanager.Names...), ", ")
+}
+
+// accentPresets lists the accent color presets accepted besides hex colors
+func accentPresets() string {
+	return strings.Join(theme.PresetNames(), ", ")
+}
+
+// splitList splits a comma-separated environment value
+func splitList(v string) []string {
+	var result []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// toInt converts YAML and TOML integer representations to int
+func toInt(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case uint64:
+		return int(v), true
+	case float64:
+		if v == float64(int(v)) {
+			return int(v), true
+		}
+	}
+	return 0, false
+}
+
+// toStringList accepts a list of strings (or a single string) and returns it as a slice
+func toStringList(value interface{}) ([]string, bool) {
+	switch v := value.(type) {
+	case string:
+		return []string{v}, true
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			list = append(list, s)
+		}
+		return list, true
+	}
+	return nil, false
+}
+
+// isLink checks that a social link is an absolute web or mail link
+func isLink(href string) bool {
+	return strings.HasPrefix(href, "https://") || strings.HasPrefix(href, "http://") || strings.HasPrefix(href, "mailto:")
+}
+
+// describe names the type of a value for error messages
+func describe(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "nothing"
+	case string:
+		return fmt.Sprintf("string %q", v)
+	case bool:
+		return fmt.Sprintf("boolean %v", v)
+	case int, int64, uint64, float64:
+		return fmt.Sprintf("number %v", v)
+	case []interface{}:
+		return "a list"
+	case map[string]interface{}:
+		return "a mapping"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// suggest returns the known key closest to an unknown one, if any is close enough
+func suggest(key string) string {
+	best := ""
+	bestDistance := 3
+	for _, known := range knownKeys {
+		if d := levenshtein(strings.ToLower(key), strings.ToLower(known)); d < bestDistance {
+			best = known
+			bestDistance = d
+		}
+	}
+	return best
+}
+
+// levenshtein computes the edit distance between two strings
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr := make([]int, len(b)+1)
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev = curr
+	}
+
+	return prev[len(b)]
+}
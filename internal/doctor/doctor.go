@@ -0,0 +1,245 @@
+package doctor
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/heidene/flashdoc/internal/config"
+	"github.com/heidene/flashdoc/internal/pkgmanager"
+	"github.com/heidene/flashdoc/internal/shared"
+	"github.com/heidene/flashdoc/internal/template"
+)
+
+// minNodeMajor is the oldest Node.js major version Astro supports
+const minNodeMajor = 18
+
+// Status is the outcome of a single check
+type Status int
+
+const (
+	OK      Status = iota // Ready
+	Warning               // Works, but with reduced features or a first-run cost
+	Failed                // flashdoc will not work until this is fixed
+)
+
+// String returns the symbol printed in front of the check
+func (s Status) String() string {
+	switch s {
+	case OK:
+		return "✓"
+	case Warning:
+		return "!"
+	default:
+		return "✗"
+	}
+}
+
+// Result is the outcome of a check with a short explanation
+type Result struct {
+	Name   string
+	Status Status
+	Detail string
+}
+
+// Doctor checks that the tools and directories flashdoc needs are in place
+type Doctor struct {
+	manager   *shared.Manager
+	sourceDir string
+	lookPath  func(file string) (string, error)
+	output    func(name string, args ...string) (string, error)
+}
+
+// New creates a doctor for the shared install managed by manager.
+// sourceDir is optional; when set its project config file is validated too.
+func New(manager *shared.Manager, sourceDir string) *Doctor {
+	return &Doctor{
+		manager:   manager,
+		sourceDir: sourceDir,
+		lookPath:  exec.LookPath,
+		output: func(name string, args ...string) (string, error) {
+			out, err := exec.Command(name, args...).Output()
+			return strings.TrimSpace(string(out)), err
+		},
+	}
+}
+
+// Run performs all checks in order
+func (d *Doctor) Run() []Result {
+	results := []Result{
+		d.checkNode(),
+		d.checkPackageManager(),
+		d.checkCacheDir(),
+		d.checkSharedInstall(),
+		d.checkLock(),
+	}
+	if d.sourceDir != "" {
+		results = append(results, d.checkProjectConfig())
+	}
+	return results
+}
+
+// Healthy reports whether no check failed
+func Healthy(results []Result) bool {
+	for _, r := range results {
+		if r.Status == Failed {
+			return false
+		}
+	}
+	return true
+}
+
+// Write prints one line per check
+func Write(w io.Writer, results []Result) {
+	for _, r := range results {
+		fmt.Fprintf(w, "%s %-18s %s\n", r.Status, r.Name, r.Detail)
+	}
+}
+
+// checkNode checks that Node.js is installed and recent enough for Astro
+func (d *Doctor) checkNode() Result {
+	result := Result{Name: "Node.js"}
+
+	if _, err := d.lookPath("node"); err != nil {
+		result.Status = Warning
+		result.Detail = "not found, sites are rendered with the native renderer (no search)"
+		return result
+	}
+
+	version, err := d.output("node", "--version")
+	if err != nil {
+		result.Status = Failed
+		result.Detail = fmt.Sprintf("failed to run node --version: %v", err)
+		return result
+	}
+
+	major, ok := nodeMajor(version)
+	if !ok {
+		result.Status = Warning
+		result.Detail = fmt.Sprintf("unrecognized version %q", version)
+		return result
+	}
+	if major < minNodeMajor {
+		result.Status = Failed
+		result.Detail = fmt.Sprintf("%s is too old, Astro needs Node.js %d or newer", version, minNodeMajor)
+		return result
+	}
+
+	result.Detail = version
+	return result
+}
+
+// nodeMajor extracts the major version from `node --version` output such as "v20.11.0"
+func nodeMajor(version string) (int, bool) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	major, _, _ := strings.Cut(version, ".")
+	n, err := strconv.Atoi(major)
+	return n, err == nil
+}
+
+// checkPackageManager checks that a supported package manager is available
+func (d *Doctor) checkPackageManager() Result {
+	result := Result{Name: "Package manager"}
+
+	pm, err := pkgmanager.Detect()
+	if err != nil {
+		result.Status = Warning
+		result.Detail = "none of pnpm, bun, npm, yarn or deno found, sites are rendered with the native renderer"
+		return result
+	}
+
+	result.Detail = pm.String()
+	return result
+}
+
+// checkCacheDir checks that the shared and runs directories are writable
+func (d *Doctor) checkCacheDir() Result {
+	result := Result{Name: "Cache directory"}
+
+	if err := d.manager.EnsureDirectories(); err != nil {
+		result.Status = Failed
+		result.Detail = err.Error()
+		return result
+	}
+
+	probe, err := os.CreateTemp(d.manager.GetRunsDir(), ".doctor-*")
+	if err != nil {
+		result.Status = Failed
+		result.Detail = fmt.Sprintf("%s is not writable: %v", d.manager.GetStardocDir(), err)
+		return result
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	result.Detail = d.manager.GetStardocDir()
+	return result
+}
+
+// checkSharedInstall checks whether the Starlight dependencies are installed and current
+func (d *Doctor) checkSharedInstall() Result {
+	result := Result{Name: "Starlight install"}
+
+	hash, err := template.GetEmbeddedPackageHash()
+	if err != nil {
+		result.Status = Failed
+		result.Detail = fmt.Sprintf("failed to get package hash: %v", err)
+		return result
+	}
+
+	current, err := d.manager.IsSharedProjectCurrent(hash)
+	if err != nil {
+		result.Status = Failed
+		result.Detail = err.Error()
+		return result
+	}
+	if !current {
+		result.Status = Warning
+		result.Detail = "not installed or outdated, the next run installs dependencies"
+		return result
+	}
+
+	result.Detail = "installed and current"
+	return result
+}
+
+// checkLock reports an install currently holding the install lock
+func (d *Doctor) checkLock() Result {
+	result := Result{Name: "Install lock"}
+
+	if d.manager.LockHeld() {
+		result.Status = Warning
+		result.Detail = "held by a running install; rerun with --break-lock if it is stuck"
+		return result
+	}
+
+	result.Detail = "free"
+	return result
+}
+
+// checkProjectConfig validates the project config file in the source directory
+func (d *Doctor) checkProjectConfig() Result {
+	result := Result{Name: "Project config"}
+
+	file, err := config.Load(d.sourceDir)
+	if err != nil {
+		result.Status = Failed
+		result.Detail = err.Error()
+		return result
+	}
+	if err := file.ApplyEnv(os.LookupEnv); err != nil {
+		result.Status = Failed
+		result.Detail = err.Error()
+		return result
+	}
+
+	if file.Path == "" {
+		result.Detail = fmt.Sprintf("none in %s (optional)", filepath.Clean(d.sourceDir))
+		return result
+	}
+	result.Detail = file.Path
+	return result
+}
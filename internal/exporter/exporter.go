@@ -5,21 +5,23 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+
+	"github.com/heidene/flashdoc/internal/logging"
 )
 
 // Exporter handles exporting the built static site to a directory
 type Exporter struct {
 	distPath   string
 	exportPath string
-	output     io.Writer
+	log        *logging.Logger
 }
 
 // New creates a new Exporter
-func New(distPath, exportPath string, output io.Writer) *Exporter {
+func New(distPath, exportPath string, log *logging.Logger) *Exporter {
 	return &Exporter{
 		distPath:   distPath,
 		exportPath: exportPath,
-		output:     output,
+		log:        logging.Or(log),
 	}
 }
 
@@ -33,7 +35,7 @@ func (e *Exporter) Export() error {
 
 	// Check if export directory exists
 	if _, err := os.Stat(absExportPath); err == nil {
-		fmt.Fprintf(e.output, "⚠️  Warning: export directory already exists, overwriting...\n")
+		e.log.Warnf("export directory already exists, overwriting...")
 	}
 
 	// Create export directory
@@ -41,7 +43,7 @@ func (e *Exporter) Export() error {
 		return fmt.Errorf("failed to create export directory: %w", err)
 	}
 
-	fmt.Fprintf(e.output, "Copying files to %s...\n", e.exportPath)
+	e.log.Infof("Copying files to %s...", e.exportPath)
 
 	// Copy all files from dist to export directory
 	fileCount := 0
@@ -82,8 +84,8 @@ func (e *Exporter) Export() error {
 		return fmt.Errorf("failed to copy files: %w", err)
 	}
 
-	fmt.Fprintf(e.output, "Exported %d files\n", fileCount)
-	fmt.Fprintf(e.output, "✅ Exported to %s\n", e.exportPath)
+	e.log.Infof("Exported %d files", fileCount)
+	e.log.Resultf("✅ Exported to %s", e.exportPath)
 
 	return nil
 }
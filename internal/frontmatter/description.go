@@ -0,0 +1,97 @@
+package frontmatter
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/heidene/flashdoc/internal/mdx"
+)
+
+// maxDescription is the length descriptions are cut to, about what search results show
+const maxDescription = 160
+
+// listItem matches the marker of an ordered list item
+var listItem = regexp.MustCompile(`^\d+[.)](\s|$)`)
+
+// ExtractDescription returns the first paragraph of a markdown body as plain text, cut at a
+// word boundary when longer than maxDescription. Headings, lists, quotes, code, tables, HTML
+// and component blocks are skipped; the result is empty when the page has no paragraph.
+func ExtractDescription(body string) string {
+	_, content := mdx.SplitESM(body)
+
+	var block []string
+	inFence := ""
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		// Fenced code never contains the description, even if it looks like a paragraph
+		if inFence != "" {
+			if strings.HasPrefix(trimmed, inFence) {
+				inFence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = trimmed[:3]
+			block = nil
+			continue
+		}
+
+		if trimmed != "" {
+			block = append(block, line)
+			continue
+		}
+		if text := paragraphText(block); text != "" {
+			return truncate(text, maxDescription)
+		}
+		block = nil
+	}
+
+	return truncate(paragraphText(block), maxDescription)
+}
+
+// paragraphText returns the plain text of a block of lines if it is a paragraph, or ""
+func paragraphText(block []string) string {
+	if len(block) == 0 {
+		return ""
+	}
+
+	first := strings.TrimSpace(block[0])
+	if strings.HasPrefix(block[0], "    ") || strings.HasPrefix(block[0], "\t") {
+		return "" // Indented code
+	}
+	for _, marker := range []string{"#", ">", "<", "|", "- ", "* ", "+ ", ":::", "{", "![", "---", "***", "___", "$$"} {
+		if strings.HasPrefix(first, marker) || first == strings.TrimSpace(marker) {
+			return ""
+		}
+	}
+	if listItem.MatchString(first) {
+		return ""
+	}
+	if len(block) > 1 {
+		// A Setext heading
+		if underline := strings.TrimSpace(block[1]); strings.Trim(underline, "=") == "" || strings.Trim(underline, "-") == "" {
+			return ""
+		}
+	}
+
+	words := make([]string, 0, len(block))
+	for _, line := range block {
+		words = append(words, strings.TrimSpace(line))
+	}
+	return strings.Join(strings.Fields(plainText(strings.Join(words, " "))), " ")
+}
+
+// truncate shortens text to at most limit characters, ending on a whole word with an ellipsis
+func truncate(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+
+	cut := string(runes[:limit-1])
+	if i := strings.LastIndexAny(cut, " \t"); i > limit/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,;:-") + "…"
+}
@@ -0,0 +1,220 @@
+package frontmatter
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// field is a frontmatter key to add; a []field value is written as a nested mapping
+type field struct {
+	key   string
+	value interface{}
+}
+
+// additions are the keys injection adds to a page's frontmatter
+type additions struct {
+	top     []field // Top-level keys, e.g. title and description
+	sidebar []field // Keys under sidebar, e.g. label and order
+}
+
+// newFrontmatter renders a frontmatter block for a page that has none
+func newFrontmatter(add additions) (string, error) {
+	fields := add.top
+	if len(add.sidebar) > 0 {
+		fields = append(fields, field{"sidebar", add.sidebar})
+	}
+
+	yml, err := encodeFields(fields, "")
+	if err != nil {
+		return "", err
+	}
+	return "---\n" + yml + "---\n", nil
+}
+
+// Values decodes the YAML frontmatter of content into generic values, as Astro reads them.
+// ok is false when content has no valid YAML frontmatter.
+func Values(content string) (values map[string]interface{}, ok bool) {
+	format, raw, _ := split(content)
+	if format != formatYAML || yaml.Unmarshal([]byte(raw), &values) != nil {
+		return nil, false
+	}
+	if values == nil {
+		values = make(map[string]interface{})
+	}
+	return values, true
+}
+
+// SetValues replaces the YAML frontmatter of content with values. Unlike injection this
+// rewrites the whole block, so comments and the original key order are not kept.
+func SetValues(content string, values map[string]interface{}) (string, error) {
+	format, _, body := split(content)
+	if format != formatYAML {
+		return "", fmt.Errorf("no YAML frontmatter to replace")
+	}
+
+	yml, err := encodeFields(orderedFields(values, nil), "")
+	if err != nil {
+		return "", err
+	}
+	return "---\n" + yml + "---\n" + body, nil
+}
+
+// insertFields adds keys to an existing frontmatter block, given with its delimiter lines.
+// Only the lines for the new keys are written; every other byte of the block is kept, so
+// comments, key order, quoting, anchors and dates survive. A key that is present but blank
+// is replaced as a whole line. Keys that can't be added without rewriting the block, such
+// as sidebar settings inside a flow mapping, are left out.
+func insertFields(head string, add additions) (string, error) {
+	lines := strings.SplitAfter(head, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	closing := len(lines) - 1
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(strings.Join(lines[1:closing], "")), &doc); err != nil {
+		return "", fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	if len(doc.Content) > 0 {
+		root = doc.Content[0]
+	}
+	if root.Kind != yaml.MappingNode {
+		return head, nil
+	}
+
+	// Node lines count from the first line inside the block, which is lines[1]
+	inserts := make(map[int][]field) // Fields to write before a line, by indent
+	indents := make(map[int]string)
+	replaced := make(map[int]field)
+	var tail []field
+
+	for _, f := range add.top {
+		key, value := lookup(root, f.key)
+		switch {
+		case key == nil:
+			tail = append(tail, f)
+		case blankOnKeyLine(key, value):
+			replaced[key.Line] = f
+		}
+	}
+
+	if len(add.sidebar) > 0 {
+		key, value := lookup(root, "sidebar")
+		switch {
+		case key == nil:
+			tail = append(tail, field{"sidebar", add.sidebar})
+		case blankOnKeyLine(key, value):
+			replaced[key.Line] = field{"sidebar", add.sidebar}
+		case value.Kind == yaml.MappingNode && value.Style&yaml.FlowStyle == 0 && len(value.Content) > 0:
+			// Insert as the first keys of the mapping, at the indent of its existing keys
+			line := key.Line + 1
+			inserts[line] = add.sidebar
+			indents[line] = strings.Repeat(" ", value.Content[0].Column-1)
+		}
+	}
+
+	if len(tail) > 0 {
+		inserts[closing] = append(inserts[closing], tail...)
+	}
+
+	newline := "\n"
+	if strings.HasSuffix(lines[0], "\r\n") {
+		newline = "\r\n"
+	}
+
+	var b strings.Builder
+	for i, line := range lines {
+		if fields, ok := inserts[i]; ok {
+			yml, err := encodeFields(fields, indents[i])
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(strings.ReplaceAll(yml, "\n", newline))
+		}
+		if f, ok := replaced[i]; ok {
+			yml, err := encodeFields([]field{f}, "")
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(strings.ReplaceAll(yml, "\n", newline))
+			continue
+		}
+		b.WriteString(line)
+	}
+
+	return b.String(), nil
+}
+
+// lookup returns the key and value nodes of a key in a mapping, or nils
+func lookup(mapping *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i], mapping.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// blankOnKeyLine reports whether a value is empty or null and written on its key's line,
+// like `title:`, `title: ""` or `title: ~`, so the line can be replaced without touching
+// its neighbours
+func blankOnKeyLine(key, value *yaml.Node) bool {
+	blank := value.Value == "" || value.ShortTag() == "!!null"
+	return value.Kind == yaml.ScalarNode && blank && value.Line == key.Line &&
+		value.Style&(yaml.LiteralStyle|yaml.FoldedStyle) == 0
+}
+
+// encodeFields renders fields as block YAML with 2-space indentation, each line prefixed with indent
+func encodeFields(fields []field, indent string) (string, error) {
+	node, err := mappingNode(fields)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(node); err != nil {
+		return "", fmt.Errorf("failed to marshal frontmatter: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return "", fmt.Errorf("failed to marshal frontmatter: %w", err)
+	}
+
+	if indent == "" {
+		return buf.String(), nil
+	}
+	lines := strings.SplitAfter(buf.String(), "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = indent + line
+		}
+	}
+	return strings.Join(lines, ""), nil
+}
+
+// mappingNode builds a mapping node holding fields in order
+func mappingNode(fields []field) (*yaml.Node, error) {
+	mapping := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, f := range fields {
+		var value *yaml.Node
+		if nested, ok := f.value.([]field); ok {
+			var err error
+			if value, err = mappingNode(nested); err != nil {
+				return nil, err
+			}
+		} else {
+			value = &yaml.Node{}
+			if err := value.Encode(f.value); err != nil {
+				return nil, fmt.Errorf("failed to marshal frontmatter: %w", err)
+			}
+		}
+		key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: f.key}
+		mapping.Content = append(mapping.Content, key, value)
+	}
+	return mapping, nil
+}
@@ -0,0 +1,185 @@
+package frontmatter
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// Frontmatter formats, as written by Jekyll/Astro (YAML) and Hugo (all three)
+const (
+	formatYAML = "yaml" // Between --- lines
+	formatTOML = "toml" // Between +++ lines
+	formatJSON = "json" // A JSON object on the first lines
+)
+
+// hugoKeys are Hugo settings that become Starlight sidebar settings on conversion
+var hugoKeys = []string{"weight", "linkTitle"}
+
+// split finds the frontmatter block at the start of content, returning its format, the
+// text inside it and the body after it. format is empty when there is no complete block.
+func split(content string) (format, raw, body string) {
+	switch {
+	case strings.HasPrefix(content, "---\n"), strings.HasPrefix(content, "---\r\n"):
+		raw, body, ok := splitDelimited(content, "---")
+		if ok {
+			return formatYAML, raw, body
+		}
+	case strings.HasPrefix(content, "+++\n"), strings.HasPrefix(content, "+++\r\n"):
+		raw, body, ok := splitDelimited(content, "+++")
+		if ok {
+			return formatTOML, raw, body
+		}
+	case strings.HasPrefix(content, "{"):
+		raw, body, ok := splitJSON(content)
+		if ok {
+			return formatJSON, raw, body
+		}
+	}
+	return "", "", content
+}
+
+// splitDelimited splits a block between two delimiter lines off content
+func splitDelimited(content, delim string) (raw, body string, ok bool) {
+	lines := strings.Split(content, "\n")
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == delim {
+			return strings.Join(lines[1:i], "\n"), strings.Join(lines[i+1:], "\n"), true
+		}
+	}
+	// Malformed frontmatter - no closing delimiter
+	return "", content, false
+}
+
+// splitJSON splits a leading JSON object off content. Nothing but whitespace may follow
+// the object on its last line, so MDX expressions like {props.title} aren't mistaken for it.
+func splitJSON(content string) (raw, body string, ok bool) {
+	dec := json.NewDecoder(strings.NewReader(content))
+	var values map[string]interface{}
+	if err := dec.Decode(&values); err != nil {
+		return "", content, false
+	}
+
+	end := int(dec.InputOffset())
+	rest := content[end:]
+	line, body, found := strings.Cut(rest, "\n")
+	if strings.TrimSpace(line) != "" {
+		return "", content, false
+	}
+	if !found {
+		body = ""
+	}
+	return content[:end], body, true
+}
+
+// decodeValues decodes TOML or JSON frontmatter into values YAML can represent
+func decodeValues(format, raw string) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	var err error
+	if format == formatTOML {
+		err = toml.Unmarshal([]byte(raw), &values)
+	} else {
+		err = json.Unmarshal([]byte(raw), &values)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return normalize(values).(map[string]interface{}), nil
+}
+
+// normalize converts TOML's local dates and times into values that marshal as YAML
+// timestamps and strings instead of structs
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = normalize(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalize(item)
+		}
+	case toml.LocalDate:
+		return v.AsTime(time.UTC)
+	case toml.LocalDateTime:
+		return v.AsTime(time.UTC)
+	case toml.LocalTime:
+		return v.String()
+	}
+	return value
+}
+
+// hugoWeight returns Hugo's weight setting of a page, its position in menus
+func hugoWeight(fm *Frontmatter) (int, bool) {
+	switch weight := fm.Other["weight"].(type) {
+	case int:
+		return weight, true
+	case float64:
+		if weight == float64(int(weight)) {
+			return int(weight), true
+		}
+	}
+	return 0, false
+}
+
+// convertFrontmatter writes TOML or JSON frontmatter as a YAML block with the additions.
+// Hugo's weight and linkTitle are dropped, as they were added as sidebar settings.
+func convertFrontmatter(format, raw string, add additions) (string, error) {
+	values, err := decodeValues(format, raw)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s frontmatter: %w", strings.ToUpper(format), err)
+	}
+	for _, key := range hugoKeys {
+		delete(values, key)
+	}
+
+	if len(add.sidebar) > 0 {
+		sidebar, _ := values["sidebar"].(map[string]interface{})
+		if sidebar == nil {
+			sidebar = make(map[string]interface{})
+		}
+		for _, f := range add.sidebar {
+			sidebar[f.key] = f.value
+		}
+		values["sidebar"] = sidebar
+	}
+
+	yml, err := encodeFields(orderedFields(values, add.top), "")
+	if err != nil {
+		return "", err
+	}
+
+	return "---\n" + yml + "---\n", nil
+}
+
+// orderedFields lists values with the title and description first, taking them from
+// generated if values has none, then the other keys in alphabetical order
+func orderedFields(values map[string]interface{}, generated []field) []field {
+	var fields []field
+	rest := make([]string, 0, len(values))
+	for key := range values {
+		if key != "title" && key != "description" {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+
+	for _, key := range []string{"title", "description"} {
+		if value, ok := values[key]; ok {
+			fields = append(fields, field{key, value})
+		}
+		for _, f := range generated {
+			if f.key == key {
+				fields = append(fields, f)
+			}
+		}
+	}
+	for _, key := range rest {
+		fields = append(fields, field{key, values[key]})
+	}
+	return fields
+}
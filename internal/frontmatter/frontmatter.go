@@ -1,11 +1,12 @@
 package frontmatter
 
 import (
-	"fmt"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 
+	"github.com/heidene/flashdoc/internal/mdx"
 	"gopkg.in/yaml.v3"
 )
 
@@ -16,71 +17,143 @@ type Frontmatter struct {
 	Other       map[string]interface{} `yaml:",inline"`
 }
 
-// Parse extracts frontmatter from markdown content
+// Parse extracts frontmatter from markdown content. YAML (---), TOML (+++) and JSON ({ })
+// blocks are recognized; malformed frontmatter is left in the body.
 func Parse(content string) (*Frontmatter, string, error) {
-	// Check if content starts with frontmatter delimiters
-	if !strings.HasPrefix(content, "---\n") && !strings.HasPrefix(content, "---\r\n") {
-		return nil, content, nil
-	}
-
-	// Find the closing delimiter
-	lines := strings.Split(content, "\n")
-	closingIndex := -1
-	for i := 1; i < len(lines); i++ {
-		if strings.TrimSpace(lines[i]) == "---" {
-			closingIndex = i
-			break
-		}
-	}
+	fm, body, _, _ := parse(content)
+	return fm, body, nil
+}
 
-	if closingIndex == -1 {
-		// Malformed frontmatter - no closing delimiter
-		return nil, content, nil
+// parse is Parse, also returning the format of the frontmatter block and its text
+func parse(content string) (fm *Frontmatter, body, format, raw string) {
+	format, raw, body = split(content)
+	if format == "" {
+		return nil, content, "", ""
 	}
 
-	// Extract frontmatter content
-	fmContent := strings.Join(lines[1:closingIndex], "\n")
-	bodyContent := strings.Join(lines[closingIndex+1:], "\n")
+	fm = &Frontmatter{}
+	if format == formatYAML {
+		if err := yaml.Unmarshal([]byte(raw), fm); err != nil {
+			// Malformed YAML - return nil frontmatter
+			return nil, content, "", ""
+		}
+		return fm, body, format, raw
+	}
 
-	// Parse YAML
-	var fm Frontmatter
-	if err := yaml.Unmarshal([]byte(fmContent), &fm); err != nil {
-		// Malformed YAML - return nil frontmatter
-		return nil, content, nil
+	values, err := decodeValues(format, raw)
+	if err != nil {
+		return nil, content, "", ""
+	}
+	converted, err := yaml.Marshal(values)
+	if err != nil || yaml.Unmarshal(converted, fm) != nil {
+		return nil, content, "", ""
 	}
+	return fm, body, format, raw
+}
 
-	return &fm, bodyContent, nil
+// Options controls the optional fields generated by InjectWithOptions
+type Options struct {
+	SidebarOrder int    // sidebar.order to set when the page doesn't define one, 0 uses the filename's number prefix
+	Titles       string // Title strategy for pages without a title: h1 (default), filename or both
 }
 
 // Inject adds or updates frontmatter in markdown content
 func Inject(content, filename, parentDir string) (string, error) {
+	return InjectWithOptions(content, filename, parentDir, Options{})
+}
+
+// InjectWithOptions adds or updates frontmatter in markdown content, filling in the optional fields from opts
+func InjectWithOptions(content, filename, parentDir string, opts Options) (string, error) {
+	// MDX frontmatter must come first, so imports written above it are moved below it
+	var esm string
+	if mdx.IsMDX(filename) {
+		if leading, rest := mdx.SplitESM(content); leading != "" && HasFrontmatter(rest) {
+			esm, content = leading, rest
+		}
+	}
+
 	// Parse existing frontmatter
-	fm, body, err := Parse(content)
-	if err != nil {
-		return "", err
+	fm, body, format, raw := parse(content)
+
+	// Existing frontmatter is edited in place; pages without one get a new block
+	existing := fm != nil
+	head := content[:len(content)-len(body)]
+	if !existing {
+		fm = &Frontmatter{}
 	}
+	var add additions
+
+	// Hugo's linkTitle is the sidebar label
+	label, _ := fm.Other["linkTitle"].(string)
 
-	// Create new frontmatter if none exists
-	if fm == nil {
-		fm = &Frontmatter{
-			Other: make(map[string]interface{}),
+	// Add title if missing, preferring the page's H1 over the filename
+	fromH1 := opts.Titles != TitleFilename
+	if fm.Title == "" {
+		title := GenerateTitle(filename, parentDir)
+		if h1, rest, ok := ExtractH1(body); ok && fromH1 {
+			if opts.Titles == TitleBoth && h1 != title && label == "" {
+				label = title
+			}
+			title, body = h1, rest
 		}
+		add.top = append(add.top, field{"title", title})
+	} else if h1, rest, ok := ExtractH1(body); ok && fromH1 && h1 == fm.Title {
+		// Starlight renders the title, so an H1 repeating it would show twice
+		body = rest
 	}
 
-	// Add title if missing
-	if fm.Title == "" {
-		fm.Title = GenerateTitle(filename, parentDir)
+	if label != "" && !sidebarDefines(fm, "label") {
+		add.sidebar = append(add.sidebar, field{"label", label})
 	}
 
-	// Serialize frontmatter
-	fmBytes, err := yaml.Marshal(fm)
+	// Describe the page with its first paragraph for search engines and link previews
+	if fm.Description == "" {
+		if description := ExtractDescription(body); description != "" {
+			add.top = append(add.top, field{"description", description})
+		}
+	}
+
+	// Add sidebar order if missing: Hugo's weight is the page's own order, then the
+	// requested one, falling back to a prefix like "01-"
+	order, ok := hugoWeight(fm)
+	if !ok {
+		order = opts.SidebarOrder
+	}
+	if order == 0 {
+		order, _ = NumberPrefix(filename)
+	}
+	if order != 0 && !sidebarDefines(fm, "order") {
+		add.sidebar = append(add.sidebar, field{"order", order})
+	}
+
+	var err error
+	switch {
+	case !existing:
+		head, err = newFrontmatter(add)
+	case format == formatYAML:
+		head, err = insertFields(head, add)
+	default:
+		// Starlight only reads YAML, so TOML and JSON frontmatter is converted
+		head, err = convertFrontmatter(format, raw, add)
+	}
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal frontmatter: %w", err)
+		return "", err
 	}
 
-	// Combine frontmatter and body
-	result := fmt.Sprintf("---\n%s---\n%s", string(fmBytes), body)
-	return result, nil
+	return head + esm + body, nil
+}
+
+// sidebarDefines checks if the page sets a sidebar key. A sidebar value that isn't a
+// mapping counts as defining every key, so it is left for Starlight to report.
+func sidebarDefines(fm *Frontmatter, key string) bool {
+	switch sidebar := fm.Other["sidebar"].(type) {
+	case nil:
+		return false
+	case map[string]interface{}:
+		_, ok := sidebar[key]
+		return ok
+	}
+	return true
 }
 
 // GenerateTitle creates a title from a filename
@@ -97,8 +170,7 @@ func GenerateTitle(filename, parentDir string) string {
 	}
 
 	// Remove numbered prefixes (e.g., "01-", "002-")
-	re := regexp.MustCompile(`^\d+-`)
-	name = re.ReplaceAllString(name, "")
+	_, name = NumberPrefix(name)
 
 	// Replace separators with spaces
 	name = strings.ReplaceAll(name, "-", " ")
@@ -120,6 +192,23 @@ func GenerateTitle(filename, parentDir string) string {
 	return title
 }
 
+// numberPrefix matches the ordering prefix of a file or directory name, e.g. "01-"
+var numberPrefix = regexp.MustCompile(`^(\d+)-`)
+
+// NumberPrefix splits an ordering prefix like "01-" off a name, returning its number and the
+// rest of the name. order is 0 when the name has no prefix or nothing follows it.
+func NumberPrefix(name string) (order int, rest string) {
+	m := numberPrefix.FindStringSubmatch(name)
+	if m == nil || len(m[0]) == len(name) {
+		return 0, name
+	}
+	order, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, name
+	}
+	return order, name[len(m[0]):]
+}
+
 // HasFrontmatter checks if content has frontmatter
 func HasFrontmatter(content string) bool {
 	fm, _, _ := Parse(content)
@@ -0,0 +1,78 @@
+package frontmatter
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/heidene/flashdoc/internal/mdx"
+)
+
+// Title strategies for pages without a frontmatter title
+const (
+	TitleH1       = "h1"       // The H1 opening the page, which is removed from the body; the filename if there is none
+	TitleFilename = "filename" // Generated from the filename; the body is left alone
+	TitleBoth     = "both"     // Like h1, with the filename-based title as the sidebar label
+)
+
+// ValidTitleStrategy checks if name is a known title strategy
+func ValidTitleStrategy(name string) bool {
+	switch name {
+	case TitleH1, TitleFilename, TitleBoth:
+		return true
+	}
+	return false
+}
+
+var (
+	atxH1       = regexp.MustCompile(`^ {0,3}#[ \t]+(.*?)(?:[ \t]+#+)?[ \t]*$`)
+	setextH1    = regexp.MustCompile(`^ {0,3}=+[ \t]*$`)
+	inlineLink  = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+	inlineEmph  = regexp.MustCompile(`\*\*(\S(?:.*?\S)?)\*\*|__(\S(?:.*?\S)?)__|\*(\S(?:.*?\S)?)\*`)
+	inlineTag   = regexp.MustCompile(`<[^>]+>`)
+	inlineClean = strings.NewReplacer("`", "", `\#`, "#", `\*`, "*", `\_`, "_")
+)
+
+// ExtractH1 returns the text of the ATX (# Title) or Setext (Title / ===) H1 that opens a
+// markdown body, and the body without it. Leading MDX imports are kept in place.
+// ok is false when the body doesn't start with an H1.
+func ExtractH1(body string) (title, rest string, ok bool) {
+	esm, content := mdx.SplitESM(body)
+	lines := strings.SplitAfter(content, "\n")
+
+	first := 0
+	for first < len(lines) && strings.TrimSpace(lines[first]) == "" {
+		first++
+	}
+	if first == len(lines) {
+		return "", body, false
+	}
+
+	line := strings.TrimRight(lines[first], "\r\n")
+	next := first + 1
+	if m := atxH1.FindStringSubmatch(line); m != nil {
+		title = m[1]
+	} else if next < len(lines) && setextH1.MatchString(strings.TrimRight(lines[next], "\r\n")) &&
+		!strings.HasPrefix(line, "    ") && !strings.HasPrefix(line, "\t") {
+		title = strings.TrimSpace(line)
+		next++
+	}
+
+	title = plainText(title)
+	if title == "" {
+		return "", body, false
+	}
+
+	// Drop the blank lines that separated the heading from the content
+	for next < len(lines) && strings.TrimSpace(lines[next]) == "" {
+		next++
+	}
+	return title, esm + strings.Join(lines[next:], ""), true
+}
+
+// plainText strips inline markdown (code spans, emphasis, links, HTML) from heading text
+func plainText(text string) string {
+	text = inlineLink.ReplaceAllString(text, "$1")
+	text = inlineEmph.ReplaceAllString(text, "$1$2$3")
+	text = inlineTag.ReplaceAllString(text, "")
+	return strings.TrimSpace(inlineClean.Replace(text))
+}
@@ -0,0 +1,252 @@
+package ignore
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// GitIgnoreFile is read from every directory of the source tree
+	GitIgnoreFile = ".gitignore"
+	// FlashdocIgnoreFile is read after .gitignore, so it can publish paths git ignores
+	FlashdocIgnoreFile = ".flashdocignore"
+)
+
+// Defaults are the patterns every matcher starts with. Ignore files, excludes and
+// includes come later and win, so `!build/` in .flashdocignore publishes a build folder.
+var Defaults = []string{
+	".*",
+	"node_modules/",
+	"dist/",
+	"build/",
+	"vendor/",
+}
+
+// Options configures the patterns Load applies on top of the defaults and ignore files
+type Options struct {
+	Exclude []string // Extra patterns to ignore (config file, --exclude)
+	Include []string // Patterns to publish even if otherwise ignored (--include)
+}
+
+// rule is a single gitignore-syntax pattern
+type rule struct {
+	base     string   // Directory of the ignore file relative to the source directory, "" for the root
+	segments []string // Pattern split on "/"; a "**" segment matches any number of directories
+	negate   bool     // Pattern started with "!" and re-includes matching paths
+	dirOnly  bool     // Pattern ended with "/" and only matches directories
+}
+
+// Matcher decides which source paths are ignored, using gitignore semantics:
+// the last matching pattern wins, and nothing below an ignored directory is published.
+type Matcher struct {
+	rules     []rule // Defaults and ignore files
+	overrides []rule // Excludes and includes, applied after every ignore file
+}
+
+// New creates a matcher with the default patterns only
+func New() *Matcher {
+	m := &Matcher{}
+	m.Add("", Defaults...)
+	return m
+}
+
+// Load creates a matcher for a source directory: the defaults, then the .gitignore and
+// .flashdocignore file of every directory that isn't ignored, then opts.
+func Load(sourceDir string, opts Options) (*Matcher, error) {
+	m := New()
+	m.overrides = append(parseAll("", opts.Exclude), parseAll("", negated(opts.Include))...)
+
+	err := filepath.WalkDir(sourceDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(sourceDir, p)
+		if err != nil {
+			return err
+		}
+		base := filepath.ToSlash(rel)
+		if base == "." {
+			base = ""
+		} else if m.Ignored(base, true) {
+			return fs.SkipDir
+		}
+
+		for _, name := range []string{GitIgnoreFile, FlashdocIgnoreFile} {
+			if err := m.AddFile(base, filepath.Join(p, name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load ignore files: %w", err)
+	}
+
+	return m, nil
+}
+
+// Add appends patterns that are relative to base, a slash-separated directory below the source directory
+func (m *Matcher) Add(base string, patterns ...string) {
+	m.rules = append(m.rules, parseAll(base, patterns)...)
+}
+
+// AddFile appends the patterns of an ignore file in base. A missing file is not an error.
+func (m *Matcher) AddFile(base, file string) error {
+	f, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	defer f.Close()
+
+	var patterns []string
+	lines := bufio.NewScanner(f)
+	for lines.Scan() {
+		patterns = append(patterns, lines.Text())
+	}
+	if err := lines.Err(); err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+
+	m.Add(base, patterns...)
+	return nil
+}
+
+// Ignored reports whether a path relative to the source directory is ignored,
+// either by itself or because one of its parent directories is
+func (m *Matcher) Ignored(relPath string, isDir bool) bool {
+	relPath = path.Clean(filepath.ToSlash(relPath))
+	if relPath == "." {
+		return false
+	}
+
+	parts := strings.Split(relPath, "/")
+	for i := 1; i < len(parts); i++ {
+		if m.match(strings.Join(parts[:i], "/"), true) {
+			return true
+		}
+	}
+	return m.match(relPath, isDir)
+}
+
+// match applies the rules to a single path; the last matching rule decides
+func (m *Matcher) match(relPath string, isDir bool) bool {
+	ignored := false
+	for _, rules := range [][]rule{m.rules, m.overrides} {
+		for _, r := range rules {
+			if r.matches(relPath, isDir) {
+				ignored = !r.negate
+			}
+		}
+	}
+	return ignored
+}
+
+// matches reports whether the rule's pattern matches a path
+func (r rule) matches(relPath string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+	if r.base != "" {
+		if !strings.HasPrefix(relPath, r.base+"/") {
+			return false
+		}
+		relPath = relPath[len(r.base)+1:]
+	}
+	return matchSegments(r.segments, strings.Split(relPath, "/"))
+}
+
+// matchSegments matches path segments against pattern segments, where "**" matches zero or more segments
+func matchSegments(pattern, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(parts); i++ {
+				if matchSegments(pattern[1:], parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+			return false
+		}
+		pattern, parts = pattern[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
+
+// parseAll parses patterns relative to base, skipping blank lines and comments
+func parseAll(base string, patterns []string) []rule {
+	var rules []rule
+	for _, pattern := range patterns {
+		if r, ok := parse(base, pattern); ok {
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
+
+// parse parses a single line of an ignore file
+func parse(base, line string) (rule, bool) {
+	// Trailing spaces are ignored unless escaped with a backslash
+	pattern := strings.TrimRight(strings.TrimSuffix(line, "\r"), " \t")
+	if strings.HasSuffix(pattern, `\`) && len(pattern) < len(line) {
+		pattern += " "
+	}
+	if pattern == "" || strings.HasPrefix(pattern, "#") {
+		return rule{}, false
+	}
+
+	r := rule{base: base}
+	if strings.HasPrefix(pattern, "!") {
+		r.negate = true
+		pattern = pattern[1:]
+	} else if strings.HasPrefix(pattern, `\!`) || strings.HasPrefix(pattern, `\#`) {
+		pattern = pattern[1:]
+	}
+
+	pattern = filepath.ToSlash(pattern)
+	if strings.HasSuffix(pattern, "/") {
+		r.dirOnly = true
+		pattern = strings.TrimRight(pattern, "/")
+	}
+	if pattern == "" {
+		return rule{}, false
+	}
+
+	// A slash anywhere but at the end anchors the pattern to its ignore file's directory;
+	// otherwise it matches a name at any depth
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	r.segments = strings.Split(pattern, "/")
+	if !anchored {
+		r.segments = append([]string{"**"}, r.segments...)
+	}
+
+	// A trailing "/**" matches everything inside the directory, but not the directory itself
+	if r.segments[len(r.segments)-1] == "**" {
+		r.segments = append(r.segments, "*")
+	}
+
+	return r, true
+}
+
+// negated turns include patterns into negations
+func negated(patterns []string) []string {
+	result := make([]string, len(patterns))
+	for i, pattern := range patterns {
+		result[i] = "!" + strings.TrimPrefix(pattern, "!")
+	}
+	return result
+}
@@ -4,10 +4,10 @@ import (
 	"fmt"
 	"math/rand"
 	"os"
-	"os/exec"
 
 	"github.com/heidene/flashdoc/internal/pkgmanager"
 	"github.com/heidene/flashdoc/internal/progress"
+	"github.com/heidene/flashdoc/internal/runlog"
 )
 
 // Installer handles dependency installation
@@ -33,9 +33,7 @@ func (i *Installer) Install() error {
 	sp.Start()
 
 	// Get install command
-	cmdArgs := i.packageManager.InstallCommand()
-	cmd := exec.Command(cmdArgs[0], cmdArgs[1:]...)
-	cmd.Dir = i.workspacePath
+	cmd := i.packageManager.InstallCommand().Cmd(i.workspacePath)
 
 	// Discard verbose output
 	cmd.Stdout = progress.DiscardWriter()
@@ -52,8 +50,9 @@ func (i *Installer) Install() error {
 	return nil
 }
 
-// InstallShared installs dependencies to the shared directory
-func InstallShared(sharedDir string, pm pkgmanager.PackageManager) error {
+// InstallShared installs dependencies to the shared directory. The install output goes
+// to log, which explains failures; a nil log discards it.
+func InstallShared(sharedDir string, pm pkgmanager.PackageManager, log *runlog.Log) error {
 	// Pick a random witty message
 	message := progress.InstallMessages[rand.Intn(len(progress.InstallMessages))]
 
@@ -61,18 +60,17 @@ func InstallShared(sharedDir string, pm pkgmanager.PackageManager) error {
 	sp.Start()
 
 	// Get install command
-	cmdArgs := pm.InstallCommand()
-	cmd := exec.Command(cmdArgs[0], cmdArgs[1:]...)
-	cmd.Dir = sharedDir
+	install := pm.InstallCommand()
+	cmd := install.Cmd(sharedDir)
 
-	// Discard verbose output
-	cmd.Stdout = progress.DiscardWriter()
-	cmd.Stderr = progress.DiscardWriter()
+	log.Step(install.String())
+	cmd.Stdout = log.Writer()
+	cmd.Stderr = log.Writer()
 
 	// Run the install command
 	if err := cmd.Run(); err != nil {
 		sp.StopWithError("Dependencies installation failed")
-		return fmt.Errorf("shared dependency installation failed: %w", err)
+		return log.Fail(fmt.Errorf("shared dependency installation failed: %w", err))
 	}
 
 	sp.Stop("Dependencies installed")
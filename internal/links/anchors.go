@@ -0,0 +1,104 @@
+package links
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// atxHeadingPattern matches "# Heading" lines, capturing the heading text
+	atxHeadingPattern = regexp.MustCompile(`^ {0,3}#{1,6}(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+
+	// setextUnderlinePattern matches the === or --- line under a setext heading
+	setextUnderlinePattern = regexp.MustCompile(`^ {0,3}(=+|-+)[ \t]*$`)
+
+	// idAttributePattern matches explicit id and name attributes in inline HTML
+	idAttributePattern = regexp.MustCompile(`<[A-Za-z][^>]*?\s(?:id|name)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+
+	// inlineImagePattern, inlineLinkPattern and htmlTagPattern strip markup from heading text
+	inlineImagePattern = regexp.MustCompile(`!\[[^\]]*\]\([^)]*\)`)
+	inlineLinkPattern  = regexp.MustCompile(`\[([^\]]*)\](?:\([^)]*\)|\[[^\]]*\])`)
+	htmlTagPattern     = regexp.MustCompile(`<[^>]+>`)
+)
+
+// Anchors returns the heading ids a page will have once rendered, including
+// explicit HTML ids. Repeated headings get -1, -2... suffixes like github-slugger.
+func Anchors(content string) map[string]bool {
+	anchors := map[string]bool{
+		// Starlight renders the page title with this id
+		"_top": true,
+	}
+
+	// Code spans are part of heading text, so only skip code blocks here
+	content = stripFrontmatter(content)
+	lines := strings.Split(maskFences(content, false), "\n")
+	seen := make(map[string]int)
+
+	add := func(text string) {
+		slug := Slugify(headingText(text))
+		if n, ok := seen[slug]; ok {
+			seen[slug] = n + 1
+			slug += "-" + strconv.Itoa(n+1)
+		} else {
+			seen[slug] = 0
+		}
+		anchors[slug] = true
+	}
+
+	for i, line := range lines {
+		line = strings.TrimRight(line, "\r")
+
+		if m := atxHeadingPattern.FindStringSubmatch(line); m != nil {
+			add(m[1])
+			continue
+		}
+
+		// A non-blank paragraph line followed by === or --- is a setext heading
+		if i+1 < len(lines) && strings.TrimSpace(line) != "" && !strings.HasPrefix(strings.TrimSpace(line), "-") &&
+			setextUnderlinePattern.MatchString(strings.TrimRight(lines[i+1], "\r")) {
+			add(strings.TrimSpace(line))
+		}
+	}
+
+	for _, m := range idAttributePattern.FindAllStringSubmatch(maskCode(content), -1) {
+		anchors[m[1]+m[2]] = true
+	}
+
+	return anchors
+}
+
+// headingText reduces heading markdown to the text that ends up in the rendered heading
+func headingText(text string) string {
+	text = inlineImagePattern.ReplaceAllString(text, "")
+	text = inlineLinkPattern.ReplaceAllString(text, "$1")
+	text = htmlTagPattern.ReplaceAllString(text, "")
+	text = strings.NewReplacer("`", "", "*", "", "~~", "").Replace(text)
+	return strings.TrimSpace(text)
+}
+
+// stripFrontmatter blanks a leading frontmatter block, keeping line numbers intact
+func stripFrontmatter(content string) string {
+	for _, delim := range []string{"---", "+++"} {
+		if !strings.HasPrefix(content, delim+"\n") && !strings.HasPrefix(content, delim+"\r\n") {
+			continue
+		}
+		rest := content[len(delim)+1:]
+		for offset := 0; offset < len(rest); {
+			end := strings.IndexByte(rest[offset:], '\n')
+			line := rest[offset:]
+			if end >= 0 {
+				line = rest[offset : offset+end]
+			}
+			if strings.TrimSpace(line) == delim {
+				closeAt := len(delim) + 1 + offset + len(line)
+				return blank(content[:closeAt]) + content[closeAt:]
+			}
+			if end < 0 {
+				break
+			}
+			offset += end + 1
+		}
+	}
+	return content
+}
@@ -0,0 +1,248 @@
+package links
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Kind identifies the markdown syntax a reference was written in
+type Kind int
+
+const (
+	Inline     Kind = iota // [text](dest) or ![alt](dest)
+	Definition             // [label]: dest
+	HTML                   // <img src="dest"> or <a href="dest">
+)
+
+// Ref is a link or image destination found in markdown content
+type Ref struct {
+	Dest  string // Destination as written, without angle brackets
+	Start int    // Byte offset of the destination in the content
+	End   int    // Byte offset just past the destination
+	Line  int    // 1-based line number of the destination
+	Image bool   // Whether the reference embeds an image
+	Kind  Kind
+}
+
+var (
+	// inlinePattern matches [text](dest "title"), allowing one level of nested brackets in the text
+	inlinePattern = regexp.MustCompile(`(!?)\[((?:[^\[\]]|\[[^\[\]]*\])*)\]\(\s*(<[^>\n]*>|[^\s()]+)(?:\s+(?:"[^"]*"|'[^']*'|\([^)]*\)))?\s*\)`)
+
+	// definitionPattern matches reference-style link definitions: [label]: dest
+	definitionPattern = regexp.MustCompile(`(?m)^ {0,3}\[([^\]]+)\]:[ \t]*(<[^>\n]*>|\S+)`)
+
+	// htmlPattern matches src and href attributes on img and a tags
+	htmlPattern = regexp.MustCompile(`<(img|a)\b[^>]*?\s(src|href)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+
+	// fencePattern matches the opening or closing line of a fenced code block
+	fencePattern = regexp.MustCompile("^ {0,3}(```+|~~~+)")
+
+	// schemePattern matches an absolute URL scheme such as https: or mailto:
+	schemePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*:`)
+)
+
+// Find returns every link, image and reference definition destination in content,
+// ignoring anything inside fenced code blocks and inline code spans
+func Find(content string) []Ref {
+	masked := maskCode(content)
+	var refs []Ref
+
+	var findInline func(offset int, text string)
+	findInline = func(offset int, text string) {
+		for _, m := range inlinePattern.FindAllStringSubmatchIndex(text, -1) {
+			// Link text may contain an image, as in [![badge](img.svg)](url)
+			findInline(offset+m[4], text[m[4]:m[5]])
+
+			start, end := offset+m[6], offset+m[7]
+			if content[start] == '<' {
+				start, end = start+1, end-1
+			}
+			refs = append(refs, Ref{Start: start, End: end, Image: m[3] > m[2], Kind: Inline})
+		}
+	}
+	findInline(0, masked)
+
+	for _, m := range definitionPattern.FindAllStringSubmatchIndex(masked, -1) {
+		start, end := m[4], m[5]
+		if content[start] == '<' {
+			start, end = start+1, end-1
+		}
+		refs = append(refs, Ref{Start: start, End: end, Kind: Definition})
+	}
+
+	for _, m := range htmlPattern.FindAllStringSubmatchIndex(masked, -1) {
+		start, end := m[6], m[7]
+		if start < 0 {
+			start, end = m[8], m[9]
+		}
+		refs = append(refs, Ref{Start: start, End: end, Image: masked[m[2]:m[3]] == "img", Kind: HTML})
+	}
+
+	// Report references in document order
+	sortRefs(refs)
+	for i := range refs {
+		refs[i].Dest = content[refs[i].Start:refs[i].End]
+		refs[i].Line = strings.Count(content[:refs[i].Start], "\n") + 1
+	}
+
+	return refs
+}
+
+// sortRefs orders references by position (insertion sort; documents have few links)
+func sortRefs(refs []Ref) {
+	for i := 1; i < len(refs); i++ {
+		for j := i; j > 0 && refs[j].Start < refs[j-1].Start; j-- {
+			refs[j], refs[j-1] = refs[j-1], refs[j]
+		}
+	}
+}
+
+// maskCode blanks out code blocks and code spans so links inside them are ignored.
+// The result has the same length and line breaks as the input.
+func maskCode(content string) string {
+	return maskFences(content, true)
+}
+
+// maskFences blanks out fenced code blocks, and inline code spans too if spans is set
+func maskFences(content string, spans bool) string {
+	lines := strings.SplitAfter(content, "\n")
+	var b strings.Builder
+	b.Grow(len(content))
+
+	fence := ""
+	for _, line := range lines {
+		trimmed := strings.TrimRight(line, "\r\n")
+
+		if fence != "" {
+			// Inside a fenced block until a closing fence of the same kind
+			if m := fencePattern.FindStringSubmatch(trimmed); m != nil && strings.HasPrefix(m[1], fence) && strings.TrimSpace(trimmed[len(m[0]):]) == "" {
+				fence = ""
+			}
+			b.WriteString(blank(line))
+			continue
+		}
+
+		if m := fencePattern.FindStringSubmatch(trimmed); m != nil {
+			fence = m[1]
+			b.WriteString(blank(line))
+			continue
+		}
+
+		if spans {
+			line = maskCodeSpans(line)
+		}
+		b.WriteString(line)
+	}
+
+	return b.String()
+}
+
+// maskCodeSpans blanks inline code spans: a run of backticks up to the next run of the same length
+func maskCodeSpans(line string) string {
+	b := []byte(line)
+	for i := 0; i < len(b); {
+		if b[i] != '`' {
+			i++
+			continue
+		}
+		open := backtickRun(b, i)
+		closeAt := -1
+		for j := i + open; j < len(b); {
+			if b[j] != '`' {
+				j++
+				continue
+			}
+			n := backtickRun(b, j)
+			if n == open {
+				closeAt = j + n
+				break
+			}
+			j += n
+		}
+		if closeAt < 0 {
+			// Unmatched backticks are literal text
+			i += open
+			continue
+		}
+		copy(b[i:closeAt], blank(string(b[i:closeAt])))
+		i = closeAt
+	}
+	return string(b)
+}
+
+// backtickRun counts consecutive backticks starting at i
+func backtickRun(b []byte, i int) int {
+	n := 0
+	for i+n < len(b) && b[i+n] == '`' {
+		n++
+	}
+	return n
+}
+
+// blank replaces every character except line breaks with spaces, keeping byte offsets intact
+func blank(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if c != '\n' && c != '\r' {
+			b[i] = ' '
+		}
+	}
+	return string(b)
+}
+
+// Replace rewrites reference destinations. The function returns the new destination
+// and whether to change it; all other content is kept byte for byte.
+func Replace(content string, refs []Ref, fn func(Ref) (string, bool)) string {
+	var b strings.Builder
+	last := 0
+	for _, ref := range refs {
+		dest, ok := fn(ref)
+		if !ok || ref.Start < last {
+			continue
+		}
+		b.WriteString(content[last:ref.Start])
+		b.WriteString(dest)
+		last = ref.End
+	}
+	b.WriteString(content[last:])
+	return b.String()
+}
+
+// IsLocal reports whether a destination refers to a file relative to the current document
+func IsLocal(dest string) bool {
+	if dest == "" || strings.HasPrefix(dest, "#") || strings.HasPrefix(dest, "/") {
+		return false
+	}
+	return !schemePattern.MatchString(dest)
+}
+
+// Split separates a destination into its path and the query/fragment suffix (including ? or #)
+func Split(dest string) (string, string) {
+	if i := strings.IndexAny(dest, "?#"); i >= 0 {
+		return dest[:i], dest[i:]
+	}
+	return dest, ""
+}
+
+// Path returns the unescaped file path part of a local destination
+func Path(dest string) string {
+	p, _ := Split(dest)
+	if unescaped, err := url.PathUnescape(p); err == nil {
+		return unescaped
+	}
+	return p
+}
+
+// Fragment returns the anchor of a destination without the leading #, or an empty string
+func Fragment(dest string) string {
+	if i := strings.Index(dest, "#"); i >= 0 {
+		return dest[i+1:]
+	}
+	return ""
+}
+
+// EscapePath percent-encodes characters that would break a markdown link destination
+func EscapePath(p string) string {
+	return strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29", "<", "%3C", ">", "%3E").Replace(p)
+}
@@ -0,0 +1,47 @@
+package links
+
+import (
+	"path"
+	"strings"
+	"unicode"
+)
+
+// Slugify converts text to a URL slug the way github-slugger does, which is what
+// Astro uses for content paths and Starlight for heading anchors: lowercase, drop
+// punctuation and symbols, and turn spaces into hyphens.
+func Slugify(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case r == ' ':
+			b.WriteRune('-')
+		case r == '-' || r == '_':
+			b.WriteRune(r)
+		case unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsMark(r):
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Route returns the site URL for a page at a slash-separated path in the docs directory:
+// the extension is dropped, each segment is slugified and index pages are served at their directory
+func Route(relPath string) string {
+	relPath = strings.TrimSuffix(relPath, path.Ext(relPath))
+
+	segments := strings.Split(relPath, "/")
+	slugs := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		if slug := Slugify(segment); slug != "" && slug != "." {
+			slugs = append(slugs, slug)
+		}
+	}
+	if len(slugs) > 0 && slugs[len(slugs)-1] == "index" {
+		slugs = slugs[:len(slugs)-1]
+	}
+
+	if len(slugs) == 0 {
+		return "/"
+	}
+	return "/" + strings.Join(slugs, "/") + "/"
+}
@@ -0,0 +1,295 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mattn/go-isatty"
+)
+
+// Level is the severity of a log message
+type Level int
+
+// Log levels, from the most to the least verbose
+const (
+	LevelDebug  Level = iota // Internal operations and timings, shown with --verbose
+	LevelInfo                // Progress of the run, hidden by --quiet
+	LevelWarn                // Problems flashdoc works around
+	LevelError               // Failures
+	LevelResult              // What the user asked for (server URL, export path), always shown
+)
+
+// Log formats
+const (
+	FormatText = "text" // Messages for people, colored on terminals
+	FormatJSON = "json" // One JSON object per line, for scripts and CI
+)
+
+// ValidFormat checks if name is a known log format
+func ValidFormat(name string) bool {
+	return name == FormatText || name == FormatJSON
+}
+
+// String returns the level's name as used in JSON lines
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	}
+	return "result"
+}
+
+// Style is an ANSI text style
+type Style string
+
+// Styles for Paint
+const (
+	Dim     Style = "2"
+	Success Style = "1;32"
+	Info    Style = "1;36"
+	Warning Style = "33"
+	Failure Style = "1;31"
+)
+
+// Leading emoji, dropped from JSON messages
+var leadingSymbols = regexp.MustCompile(`^[\p{So}\p{Sk}\x{2139}\x{FE0F}\x{200D}\s]+`)
+
+// Options configures a Logger
+type Options struct {
+	Level      Level     // Messages below this level are dropped
+	Format     string    // FormatText (default) or FormatJSON
+	Timestamps bool      // Prefix text messages with the time; JSON lines always have it
+	Out        io.Writer // Debug, info and result messages; default os.Stdout
+	Err        io.Writer // Warnings, errors and progress; default os.Stderr
+	Color      *bool     // Force colors on or off; nil uses them when Err is a terminal
+}
+
+// Logger writes leveled messages as text or JSON lines
+type Logger struct {
+	opts  Options
+	color bool
+	tty   bool // Err is an interactive terminal
+	mu    sync.Mutex
+	now   func() time.Time
+}
+
+// New creates a logger
+func New(opts Options) *Logger {
+	if opts.Format == "" {
+		opts.Format = FormatText
+	}
+	if opts.Out == nil {
+		opts.Out = os.Stdout
+	}
+	if opts.Err == nil {
+		opts.Err = os.Stderr
+	}
+
+	l := &Logger{opts: opts, tty: isTerminal(opts.Err), now: time.Now}
+	l.color = l.tty
+	if opts.Color != nil {
+		l.color = *opts.Color
+	}
+	// NO_COLOR wins even over forced colors
+	l.color = l.color && colorAllowed(os.LookupEnv)
+	return l
+}
+
+var (
+	defaultMu     sync.RWMutex
+	defaultLogger = New(Options{Level: LevelInfo})
+)
+
+// Default returns the logger used by the package-level functions
+func Default() *Logger {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultLogger
+}
+
+// SetDefault replaces the logger used by the package-level functions
+func SetDefault(l *Logger) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultLogger = l
+}
+
+// Or returns l, or the default logger if l is nil
+func Or(l *Logger) *Logger {
+	if l == nil {
+		return Default()
+	}
+	return l
+}
+
+// Enabled reports whether messages at level are written
+func (l *Logger) Enabled(level Level) bool {
+	return level >= l.opts.Level
+}
+
+// Interactive reports whether progress spinners may be drawn: text output to a terminal
+// that isn't silenced by --quiet or busy with --verbose output
+func (l *Logger) Interactive() bool {
+	return l.tty && l.opts.Format == FormatText && l.opts.Level == LevelInfo && os.Getenv("TERM") != "dumb"
+}
+
+// Color reports whether text output may use colors
+func (l *Logger) Color() bool {
+	return l.color && l.opts.Format == FormatText
+}
+
+// Paint renders text in style when colors are enabled
+func (l *Logger) Paint(style Style, text string) string {
+	if !l.Color() {
+		return text
+	}
+	return "\x1b[" + string(style) + "m" + text + "\x1b[0m"
+}
+
+// Debugf logs details of internal operations
+func (l *Logger) Debugf(format string, args ...interface{}) {
+	l.log(LevelDebug, l.Paint(Dim, "debug:")+" ", fmt.Sprintf(format, args...), nil, l.opts.Out)
+}
+
+// Infof logs the progress of the run
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.log(LevelInfo, "", fmt.Sprintf(format, args...), nil, l.opts.Out)
+}
+
+// Successf logs a finished step next to progress spinners, with a check mark
+func (l *Logger) Successf(format string, args ...interface{}) {
+	l.log(LevelInfo, l.Paint(Success, "✓")+" ", fmt.Sprintf(format, args...), nil, l.opts.Err)
+}
+
+// Warnf logs a problem flashdoc works around
+func (l *Logger) Warnf(format string, args ...interface{}) {
+	l.log(LevelWarn, l.Paint(Warning, "Warning:")+" ", fmt.Sprintf(format, args...), nil, l.opts.Err)
+}
+
+// Errorf logs a failure
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	l.log(LevelError, l.Paint(Failure, "Error:")+" ", fmt.Sprintf(format, args...), nil, l.opts.Err)
+}
+
+// Failf logs a failed step next to progress spinners, with a cross
+func (l *Logger) Failf(format string, args ...interface{}) {
+	l.log(LevelError, l.Paint(Failure, "✗")+" ", fmt.Sprintf(format, args...), nil, l.opts.Err)
+}
+
+// Resultf logs what the user asked for, such as the server URL, even with --quiet
+func (l *Logger) Resultf(format string, args ...interface{}) {
+	l.log(LevelResult, "", fmt.Sprintf(format, args...), nil, l.opts.Out)
+}
+
+// Result logs what the user asked for along with its data. Text output shows text line by
+// line; JSON output is one line with text's first line as msg and fields next to it.
+func (l *Logger) Result(text string, fields map[string]interface{}) {
+	if l.opts.Format == FormatJSON {
+		msg, _, _ := strings.Cut(text, "\n")
+		l.log(LevelResult, "", msg, fields, l.opts.Out)
+		return
+	}
+	for _, line := range strings.Split(text, "\n") {
+		l.log(LevelResult, "", line, nil, l.opts.Out)
+	}
+}
+
+// Print logs a line of subprocess output at level, without a prefix
+func (l *Logger) Print(level Level, line string) {
+	out := l.opts.Out
+	if level >= LevelWarn && level != LevelResult {
+		out = l.opts.Err
+	}
+	l.log(level, "", line, nil, out)
+}
+
+// log writes a message: in text format with its prefix, in JSON as a line of its own
+// that also holds fields
+func (l *Logger) log(level Level, prefix, msg string, fields map[string]interface{}, out io.Writer) {
+	if !l.Enabled(level) {
+		return
+	}
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.opts.Format == FormatJSON {
+		line, _ := json.Marshal(struct {
+			Time  string `json:"time"`
+			Level string `json:"level"`
+			Msg   string `json:"msg"`
+		}{now.Format(time.RFC3339), level.String(), leadingSymbols.ReplaceAllString(strings.TrimSpace(msg), "")})
+		if extra, err := json.Marshal(fields); err == nil && len(fields) > 0 {
+			// Splice the fields into the object, after time, level and msg
+			line = append(append(line[:len(line)-1], ','), extra[1:]...)
+		}
+		fmt.Fprintf(out, "%s\n", line)
+		return
+	}
+
+	// Keep blank lines that separate blocks of output ahead of the timestamp
+	lead := ""
+	if trimmed := strings.TrimLeft(msg, "\n"); trimmed != msg {
+		lead, msg = msg[:len(msg)-len(trimmed)], trimmed
+	}
+	if l.opts.Timestamps {
+		prefix = l.Paint(Dim, now.Format("[15:04:05]")) + " " + prefix
+	}
+	fmt.Fprintf(out, "%s%s%s\n", lead, prefix, msg)
+}
+
+// Package-level functions log through the default logger
+
+// Debugf logs details of internal operations
+func Debugf(format string, args ...interface{}) { Default().Debugf(format, args...) }
+
+// Infof logs the progress of the run
+func Infof(format string, args ...interface{}) { Default().Infof(format, args...) }
+
+// Successf logs a finished step with a check mark
+func Successf(format string, args ...interface{}) { Default().Successf(format, args...) }
+
+// Warnf logs a problem flashdoc works around
+func Warnf(format string, args ...interface{}) { Default().Warnf(format, args...) }
+
+// Errorf logs a failure
+func Errorf(format string, args ...interface{}) { Default().Errorf(format, args...) }
+
+// Resultf logs what the user asked for, even with --quiet
+func Resultf(format string, args ...interface{}) { Default().Resultf(format, args...) }
+
+// Result logs what the user asked for along with its data
+func Result(text string, fields map[string]interface{}) { Default().Result(text, fields) }
+
+// isTerminal reports whether w is an interactive terminal
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// colorAllowed applies the NO_COLOR convention (https://no-color.org) and dumb terminals
+func colorAllowed(lookupEnv func(string) (string, bool)) bool {
+	if value, ok := lookupEnv("NO_COLOR"); ok && value != "" {
+		return false
+	}
+	if term, _ := lookupEnv("TERM"); term == "dumb" {
+		return false
+	}
+	return true
+}
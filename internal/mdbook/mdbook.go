@@ -0,0 +1,279 @@
+package mdbook
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/heidene/flashdoc/internal/template"
+	"github.com/pelletier/go-toml/v2"
+)
+
+const (
+	// ConfigName is the mdBook config file
+	ConfigName = "book.toml"
+	// SummaryName is the table of contents of mdBook and GitBook books
+	SummaryName = "SUMMARY.md"
+)
+
+// Book is an mdBook or GitBook book: its title and the table of contents in SUMMARY.md
+type Book struct {
+	Path     string     // Path of book.toml, empty for a SUMMARY.md without one (GitBook)
+	Title    string     // [book] title
+	SrcDir   string     // Directory holding SUMMARY.md and the pages
+	Summary  string     // Path of SUMMARY.md
+	Chapters []*Chapter // Entries of SUMMARY.md; part titles hold the chapters below them
+}
+
+// Chapter is an entry of SUMMARY.md: a page, an external link, or a part title or draft
+// chapter that only groups the entries nested below it
+type Chapter struct {
+	Title    string
+	Page     string // Slash-separated path in the source directory
+	URL      string // External link
+	Children []*Chapter
+}
+
+// configFile is the part of book.toml flashdoc uses
+type configFile struct {
+	Book struct {
+		Title string `toml:"title"`
+		Src   string `toml:"src"`
+	} `toml:"book"`
+}
+
+var (
+	summaryHeading = regexp.MustCompile(`^#{1,6}[ \t]+(.*?)[ \t#]*$`)
+	summaryItem    = regexp.MustCompile(`^([ \t]*)[-*+][ \t]+(.*)$`)
+	summaryLink    = regexp.MustCompile(`^\[(.*)\]\((.*)\)$`)
+)
+
+// Find returns the book sourceDir is the root or src directory of: the one a book.toml in
+// sourceDir configures, or one in the parent whose src is sourceDir. A SUMMARY.md without
+// a book.toml, as GitBook lays books out, makes sourceDir a book too. It returns nil
+// without an error when sourceDir holds no book.
+func Find(sourceDir string) (*Book, error) {
+	abs, err := filepath.Abs(sourceDir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dir := range []string{abs, filepath.Dir(abs)} {
+		configPath := filepath.Join(dir, ConfigName)
+		if _, err := os.Stat(configPath); err != nil {
+			continue
+		}
+		book, err := Load(configPath)
+		if err != nil {
+			return nil, err
+		}
+		if dir == abs || book.SrcDir == abs {
+			return book, nil
+		}
+	}
+
+	summaryPath := filepath.Join(abs, SummaryName)
+	if _, err := os.Stat(summaryPath); err != nil {
+		return nil, nil
+	}
+	book := &Book{SrcDir: abs, Summary: summaryPath}
+	if err := book.readSummary(); err != nil {
+		return nil, err
+	}
+	return book, nil
+}
+
+// Load reads a book.toml file and the SUMMARY.md of its src directory
+func Load(configPath string) (*Book, error) {
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", configPath, err)
+	}
+
+	var file configFile
+	if err := toml.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("%s: %w", configPath, err)
+	}
+
+	srcDir := file.Book.Src
+	if srcDir == "" {
+		srcDir = "src"
+	}
+	if !filepath.IsAbs(srcDir) {
+		srcDir = filepath.Join(filepath.Dir(configPath), srcDir)
+	}
+	srcDir, err = filepath.Abs(srcDir)
+	if err != nil {
+		return nil, err
+	}
+
+	book := &Book{
+		Path:    configPath,
+		Title:   file.Book.Title,
+		SrcDir:  srcDir,
+		Summary: filepath.Join(srcDir, SummaryName),
+	}
+	if err := book.readSummary(); err != nil {
+		return nil, err
+	}
+	return book, nil
+}
+
+// readSummary parses the book's SUMMARY.md into its chapters
+func (b *Book) readSummary() error {
+	content, err := os.ReadFile(b.Summary)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", b.Summary, err)
+	}
+	chapters, err := parseSummary(string(content))
+	if err != nil {
+		return fmt.Errorf("%s:%w", b.Summary, err)
+	}
+	b.Chapters = chapters
+	return nil
+}
+
+// Reload re-reads book.toml and SUMMARY.md after they changed. On error the book is kept
+// as it was.
+func (b *Book) Reload() error {
+	if b.Path == "" {
+		return b.readSummary()
+	}
+	book, err := Load(b.Path)
+	if err != nil {
+		return err
+	}
+	*b = *book
+	return nil
+}
+
+// parseSummary reads the entries of a SUMMARY.md: an optional title heading, links above
+// and below the numbered chapters, nested lists of [Title](page.md) items, and headings
+// that start a new part. Other lines, like --- separators, are skipped.
+func parseSummary(content string) ([]*Chapter, error) {
+	type level struct {
+		indent  int
+		chapter *Chapter
+	}
+
+	var chapters []*Chapter
+	var part *Chapter // Part title the numbered chapters go in, nil before the first
+	var levels []level
+	titled := false
+
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if heading := summaryHeading.FindStringSubmatch(trimmed); heading != nil {
+			// The first heading before any chapter is the title of the summary itself
+			if !titled && len(chapters) == 0 {
+				titled = true
+				continue
+			}
+			part = &Chapter{Title: heading[1]}
+			chapters = append(chapters, part)
+			levels = nil
+			continue
+		}
+
+		if item := summaryItem.FindStringSubmatch(line); item != nil {
+			link := summaryLink.FindStringSubmatch(strings.TrimSpace(item[2]))
+			if link == nil {
+				return nil, fmt.Errorf("%d: list items must be links like [Title](page.md), got %q", i+1, trimmed)
+			}
+			chapter := newChapter(link[1], link[2])
+			indent := len(strings.ReplaceAll(item[1], "\t", "    "))
+
+			for len(levels) > 0 && levels[len(levels)-1].indent >= indent {
+				levels = levels[:len(levels)-1]
+			}
+			switch {
+			case len(levels) > 0:
+				parent := levels[len(levels)-1].chapter
+				parent.Children = append(parent.Children, chapter)
+			case part != nil:
+				part.Children = append(part.Children, chapter)
+			default:
+				chapters = append(chapters, chapter)
+			}
+			levels = append(levels, level{indent: indent, chapter: chapter})
+			continue
+		}
+
+		// Prefix and suffix chapters are links outside the list
+		if link := summaryLink.FindStringSubmatch(trimmed); link != nil {
+			chapters = append(chapters, newChapter(link[1], link[2]))
+			part, levels = nil, nil
+		}
+	}
+	return chapters, nil
+}
+
+// newChapter makes the entry for a link; an empty target is a draft chapter
+func newChapter(title, target string) *Chapter {
+	target = strings.TrimSpace(target)
+	if strings.Contains(target, "://") {
+		return &Chapter{Title: title, URL: target}
+	}
+	if i := strings.Index(target, "#"); i != -1 {
+		target = target[:i]
+	}
+	if unescaped, err := url.PathUnescape(target); err == nil {
+		target = unescaped
+	}
+	if target == "" {
+		return &Chapter{Title: title}
+	}
+	return &Chapter{Title: title, Page: path.Clean(strings.TrimPrefix(target, "./"))}
+}
+
+// Pages returns the pages SUMMARY.md lists, in reading order
+func (b *Book) Pages() []string {
+	var pages []string
+	var walk func(chapters []*Chapter)
+	walk = func(chapters []*Chapter) {
+		for _, chapter := range chapters {
+			if chapter.Page != "" {
+				pages = append(pages, chapter.Page)
+			}
+			walk(chapter.Children)
+		}
+	}
+	walk(b.Chapters)
+	return pages
+}
+
+// Sidebar translates the summary into a Starlight sidebar. Chapters with nested chapters
+// become groups that start with the chapter's own page. Titles in the summary win over
+// the pages' own; pages that weren't published and groups left empty are dropped.
+func (b *Book) Sidebar(page template.PageFunc) []template.SidebarItem {
+	return sidebarItems(b.Chapters, page)
+}
+
+// sidebarItems translates chapters into sidebar items
+func sidebarItems(chapters []*Chapter, page template.PageFunc) []template.SidebarItem {
+	var items []template.SidebarItem
+	for _, chapter := range chapters {
+		var own []template.SidebarItem
+		switch {
+		case chapter.URL != "":
+			own = append(own, template.SidebarItem{Label: chapter.Title, Link: chapter.URL})
+		case chapter.Page != "":
+			if item, ok := template.PageItem(page, chapter.Page, chapter.Title); ok {
+				own = append(own, item)
+			}
+		}
+
+		children := sidebarItems(chapter.Children, page)
+		if len(children) == 0 {
+			items = append(items, own...)
+			continue
+		}
+		items = append(items, template.SidebarItem{Label: chapter.Title, Items: append(own, children...)})
+	}
+	return items
+}
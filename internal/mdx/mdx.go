@@ -0,0 +1,132 @@
+package mdx
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// IsMDX checks if a filename has the .mdx extension
+func IsMDX(filename string) bool {
+	return strings.EqualFold(filepath.Ext(filename), ".mdx")
+}
+
+// SplitESM splits the import/export statements at the start of MDX content from the rest.
+// Blank lines between statements belong to the ESM block, so rest starts with the first
+// line of markdown (or a frontmatter block written below the imports).
+func SplitESM(content string) (esm, rest string) {
+	offset := 0
+	for offset < len(content) {
+		line := lineAt(content, offset)
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			offset += len(line)
+		case isStatementStart(trimmed):
+			offset += statementLength(content[offset:])
+		default:
+			return content[:offset], content[offset:]
+		}
+	}
+	return content, ""
+}
+
+// importSpec matches the module specifier of an import or re-export statement
+var importSpec = regexp.MustCompile(`(\bfrom\s*|\bimport\s*)(['"])([^'"\n]+)(['"])`)
+
+// RewriteImports calls rewrite for the module specifier of every import at the start of
+// MDX content, after the frontmatter if there is one, and replaces it with the result.
+// line is the 1-based line of the specifier in content.
+func RewriteImports(content string, rewrite func(spec string, line int) string) string {
+	head := frontmatterBlock(content)
+	esm, body := SplitESM(content[len(head):])
+
+	firstLine := strings.Count(head, "\n") + 1
+	esm = replaceSubmatch(esm, func(spec string, offset int) string {
+		return rewrite(spec, firstLine+strings.Count(esm[:offset], "\n"))
+	})
+
+	return head + esm + body
+}
+
+// replaceSubmatch replaces the specifier group of every importSpec match
+func replaceSubmatch(esm string, replace func(spec string, offset int) string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range importSpec.FindAllStringSubmatchIndex(esm, -1) {
+		start, end := m[6], m[7]
+		b.WriteString(esm[last:start])
+		b.WriteString(replace(esm[start:end], start))
+		last = end
+	}
+	b.WriteString(esm[last:])
+	return b.String()
+}
+
+// frontmatterBlock returns the leading YAML (---) or TOML (+++) frontmatter block including
+// its delimiters, or ""
+func frontmatterBlock(content string) string {
+	delim := strings.TrimSpace(lineAt(content, 0))
+	if delim != "---" && delim != "+++" {
+		return ""
+	}
+
+	offset := len(lineAt(content, 0))
+	for offset < len(content) {
+		line := lineAt(content, offset)
+		offset += len(line)
+		if strings.TrimSpace(line) == delim {
+			return content[:offset]
+		}
+	}
+	return ""
+}
+
+// isStatementStart reports whether a trimmed line starts an import or export statement
+func isStatementStart(line string) bool {
+	for _, keyword := range []string{"import", "export"} {
+		if rest, ok := strings.CutPrefix(line, keyword); ok {
+			if rest == "" || strings.ContainsAny(rest[:1], " \t{*'\"") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// statementLength returns the length of the statement starting content, through the end of
+// the line where its brackets are balanced again. Multi-line imports and exported objects span
+// several lines; brackets inside string literals are not counted.
+func statementLength(content string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '{' || c == '(' || c == '[':
+			depth++
+		case c == '}' || c == ')' || c == ']':
+			depth--
+		case c == '\n' && depth <= 0:
+			return i + 1
+		}
+	}
+	return len(content)
+}
+
+// lineAt returns the line starting at offset, including its newline
+func lineAt(content string, offset int) string {
+	if i := strings.IndexByte(content[offset:], '\n'); i >= 0 {
+		return content[offset : offset+i+1]
+	}
+	return content[offset:]
+}
@@ -0,0 +1,252 @@
+package mkdocs
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/heidene/flashdoc/internal/template"
+	"gopkg.in/yaml.v3"
+)
+
+// FileNames are the MkDocs config file names, in the order they're looked for
+var FileNames = []string{"mkdocs.yml", "mkdocs.yaml"}
+
+// Project is the part of an MkDocs config flashdoc uses
+type Project struct {
+	Path     string    // Path of mkdocs.yml
+	SiteName string    // site_name
+	DocsDir  string    // docs_dir, resolved against the config's directory
+	RepoURL  string    // repo_url
+	ExtraCSS []string  // extra_css, resolved against the docs directory
+	Nav      []NavItem // nav; empty lists every page
+}
+
+// NavItem is an entry of the MkDocs nav: a page, an external link or a section of entries
+type NavItem struct {
+	Title    string    // Empty for pages listed without a title
+	Page     string    // Slash-separated path in the docs directory
+	URL      string    // External link
+	Children []NavItem // Section entries
+}
+
+// IsSection reports whether the item is a section of other entries
+func (n NavItem) IsSection() bool {
+	return n.Page == "" && n.URL == ""
+}
+
+// configFile is the content of mkdocs.yml. Theme and plugin settings are ignored, so
+// Python tags like !!python/name in them don't matter.
+type configFile struct {
+	SiteName string      `yaml:"site_name"`
+	DocsDir  string      `yaml:"docs_dir"`
+	RepoURL  string      `yaml:"repo_url"`
+	ExtraCSS []string    `yaml:"extra_css"`
+	Nav      interface{} `yaml:"nav"`
+}
+
+// Find looks for an MkDocs config in sourceDir, then in its parent. A config in the
+// parent only counts if its docs_dir is sourceDir. It returns nil without an error when
+// there is none.
+func Find(sourceDir string) (*Project, error) {
+	abs, err := filepath.Abs(sourceDir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dir := range []string{abs, filepath.Dir(abs)} {
+		for _, name := range FileNames {
+			configPath := filepath.Join(dir, name)
+			if _, err := os.Stat(configPath); err != nil {
+				continue
+			}
+			project, err := Load(configPath)
+			if err != nil {
+				return nil, err
+			}
+			if dir == abs || project.DocsDir == abs {
+				return project, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// Load reads an MkDocs config file
+func Load(configPath string) (*Project, error) {
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", configPath, err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", configPath, err)
+	}
+	resolveEnv(&doc)
+
+	var file configFile
+	if err := doc.Decode(&file); err != nil {
+		return nil, fmt.Errorf("%s: %w", configPath, err)
+	}
+
+	docsDir := file.DocsDir
+	if docsDir == "" {
+		docsDir = "docs"
+	}
+	if !filepath.IsAbs(docsDir) {
+		docsDir = filepath.Join(filepath.Dir(configPath), docsDir)
+	}
+	docsDir, err = filepath.Abs(docsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	project := &Project{
+		Path:     configPath,
+		SiteName: file.SiteName,
+		DocsDir:  docsDir,
+		RepoURL:  file.RepoURL,
+	}
+	for _, css := range file.ExtraCSS {
+		// Stylesheets can also be URLs, which Starlight can't bundle
+		if !strings.Contains(css, "://") {
+			project.ExtraCSS = append(project.ExtraCSS, filepath.Join(docsDir, filepath.FromSlash(css)))
+		}
+	}
+	if file.Nav != nil {
+		project.Nav, err = parseNav(file.Nav)
+		if err != nil {
+			return nil, fmt.Errorf("%s: nav: %w", configPath, err)
+		}
+	}
+	return project, nil
+}
+
+// resolveEnv replaces the !ENV tags below node with their values, like MkDocs does:
+// !ENV NAME is the variable's value and !ENV [NAME, OTHER, default] the value of the first
+// variable that is set, else the default. Values are typed like any other YAML scalar.
+func resolveEnv(node *yaml.Node) {
+	if node.Tag != "!ENV" {
+		for _, child := range node.Content {
+			resolveEnv(child)
+		}
+		return
+	}
+
+	names := []*yaml.Node{node}
+	var fallback *yaml.Node
+	if node.Kind == yaml.SequenceNode {
+		names = node.Content
+		if len(names) > 1 {
+			names, fallback = names[:len(names)-1], names[len(names)-1]
+		}
+	}
+
+	for _, name := range names {
+		if value, ok := os.LookupEnv(name.Value); ok {
+			*node = yaml.Node{Kind: yaml.ScalarNode, Value: value, Line: node.Line, Column: node.Column}
+			return
+		}
+	}
+	if fallback != nil {
+		*node = *fallback
+		return
+	}
+	*node = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Line: node.Line, Column: node.Column}
+}
+
+// parseNav reads the entries of a nav list: "page.md", {Title: page.md},
+// {Title: https://...} or {Section: [entries]}
+func parseNav(value interface{}) ([]NavItem, error) {
+	entries, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("must be a list of entries")
+	}
+
+	items := make([]NavItem, 0, len(entries))
+	for _, entry := range entries {
+		switch e := entry.(type) {
+		case string:
+			items = append(items, navTarget("", e))
+		case map[string]interface{}:
+			if len(e) != 1 {
+				return nil, fmt.Errorf("entries must have one title, got %d", len(e))
+			}
+			for title, target := range e {
+				switch t := target.(type) {
+				case string:
+					items = append(items, navTarget(title, t))
+				case []interface{}:
+					children, err := parseNav(t)
+					if err != nil {
+						return nil, err
+					}
+					items = append(items, NavItem{Title: title, Children: children})
+				default:
+					return nil, fmt.Errorf("%s: must be a page, a link or a list of entries", title)
+				}
+			}
+		default:
+			return nil, fmt.Errorf("entries must be a page or a mapping of a title to a page")
+		}
+	}
+	return items, nil
+}
+
+// navTarget makes the entry for a page path or link
+func navTarget(title, target string) NavItem {
+	if strings.Contains(target, "://") || strings.HasPrefix(target, "/") {
+		return NavItem{Title: title, URL: target}
+	}
+	return NavItem{Title: title, Page: path.Clean(strings.TrimPrefix(target, "./"))}
+}
+
+// SocialIcon returns the Starlight social icon for the repository URL's host, empty for
+// hosts without one
+func (p *Project) SocialIcon() string {
+	u, err := url.Parse(p.RepoURL)
+	if err != nil {
+		return ""
+	}
+	host := strings.TrimPrefix(u.Hostname(), "www.")
+	for _, icon := range []string{"github", "gitlab", "bitbucket"} {
+		if strings.HasPrefix(host, icon+".") {
+			return icon
+		}
+	}
+	return ""
+}
+
+// Sidebar translates the nav into a Starlight sidebar. Titles in the nav win over the
+// pages' own; pages that weren't published and sections left empty are dropped.
+func (p *Project) Sidebar(page template.PageFunc) []template.SidebarItem {
+	return sidebarItems(p.Nav, page)
+}
+
+// sidebarItems translates nav entries into sidebar items
+func sidebarItems(nav []NavItem, page template.PageFunc) []template.SidebarItem {
+	var items []template.SidebarItem
+	for _, entry := range nav {
+		switch {
+		case entry.IsSection():
+			if children := sidebarItems(entry.Children, page); len(children) > 0 {
+				items = append(items, template.SidebarItem{Label: entry.Title, Items: children})
+			}
+		case entry.URL != "":
+			label := entry.Title
+			if label == "" {
+				label = entry.URL
+			}
+			items = append(items, template.SidebarItem{Label: label, Link: entry.URL})
+		default:
+			if item, ok := template.PageItem(page, entry.Page, entry.Title); ok {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
@@ -2,36 +2,102 @@ package pkgmanager
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
+	"strconv"
+	"strings"
 )
 
 // PackageManager represents a Node.js package manager
 type PackageManager string
 
 const (
-	Pnpm PackageManager = "pnpm"
-	Bun  PackageManager = "bun"
-	Npm  PackageManager = "npm"
+	Pnpm      PackageManager = "pnpm"
+	Bun       PackageManager = "bun"
+	Npm       PackageManager = "npm"
+	Yarn      PackageManager = "yarn"       // Yarn classic (1.x)
+	YarnBerry PackageManager = "yarn-berry" // Yarn 2 and later
+	Deno      PackageManager = "deno"
 )
 
+// Auto selects the package manager by detection
+const Auto = "auto"
+
+// Names lists the package managers that can be selected, in detection order
+var Names = []string{"pnpm", "bun", "npm", "yarn", "deno"}
+
+// Command is a package manager invocation
+type Command struct {
+	Args []string // Program and arguments
+	Env  []string // Extra environment variables (KEY=value)
+}
+
+// String returns the command line
+func (c Command) String() string {
+	return strings.Join(c.Args, " ")
+}
+
+// Cmd prepares the command to run in dir, with the current environment plus c.Env
+func (c Command) Cmd(dir string) *exec.Cmd {
+	cmd := exec.Command(c.Args[0], c.Args[1:]...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), c.Env...)
+	return cmd
+}
+
 // Detect finds the best available package manager
 func Detect() (PackageManager, error) {
-	// Check for pnpm first (fastest)
-	if isAvailable("pnpm") {
-		return Pnpm, nil
+	for _, name := range Names {
+		if isAvailable(name) {
+			return resolve(PackageManager(name)), nil
+		}
 	}
 
-	// Check for bun
-	if isAvailable("bun") {
-		return Bun, nil
+	return "", fmt.Errorf("no package manager found (tried: %s)", strings.Join(Names, ", "))
+}
+
+// Valid checks if name selects a package manager: one of Names or "auto"
+func Valid(name string) bool {
+	if name == Auto {
+		return true
 	}
+	for _, known := range Names {
+		if name == known {
+			return true
+		}
+	}
+	return false
+}
 
-	// Check for npm (most common, usually installed with Node.js)
-	if isAvailable("npm") {
-		return Npm, nil
+// Select returns the named package manager, or detects one when name is empty or "auto".
+// A named package manager must be installed.
+func Select(name string) (PackageManager, error) {
+	if name == "" || name == Auto {
+		return Detect()
 	}
+	if !Valid(name) {
+		return "", fmt.Errorf("unsupported package manager: %s (supported: %s)", name, strings.Join(Names, ", "))
+	}
+	if !isAvailable(name) {
+		return "", fmt.Errorf("package manager %s not found in PATH", name)
+	}
+	return resolve(PackageManager(name)), nil
+}
 
-	return "", fmt.Errorf("no package manager found (tried: pnpm, bun, npm)")
+// resolve tells Yarn classic and Yarn berry apart by their version
+func resolve(pm PackageManager) PackageManager {
+	if pm != Yarn {
+		return pm
+	}
+	out, err := exec.Command("yarn", "--version").Output()
+	if err != nil {
+		return pm
+	}
+	major, _, _ := strings.Cut(strings.TrimSpace(string(out)), ".")
+	if n, err := strconv.Atoi(major); err == nil && n >= 2 {
+		return YarnBerry
+	}
+	return pm
 }
 
 // isAvailable checks if a command is available in PATH
@@ -40,32 +106,56 @@ func isAvailable(command string) bool {
 	return err == nil
 }
 
-// InstallCommand returns the install command for the package manager
-func (pm PackageManager) InstallCommand() []string {
+// Binary returns the program that runs the package manager
+func (pm PackageManager) Binary() string {
 	switch pm {
-	case Pnpm:
-		return []string{"pnpm", "install"}
-	case Bun:
-		return []string{"bun", "install"}
-	case Npm:
-		return []string{"npm", "install"}
+	case Pnpm, Bun, Deno:
+		return string(pm)
+	case Yarn, YarnBerry:
+		return "yarn"
 	default:
-		return []string{"npm", "install"}
+		return "npm"
 	}
 }
 
-// BuildCommand returns the build command for the package manager
-func (pm PackageManager) BuildCommand() []string {
+// env returns the environment every command of the package manager needs
+func (pm PackageManager) env() []string {
+	if pm == YarnBerry {
+		// Install a node_modules directory the run workspaces can link to instead of
+		// Plug'n'Play, and allow creating the lockfile on CI
+		return []string{"YARN_NODE_LINKER=node-modules", "YARN_ENABLE_IMMUTABLE_INSTALLS=false"}
+	}
+	return nil
+}
+
+// InstallCommand returns the install command for the package manager
+func (pm PackageManager) InstallCommand() Command {
+	return Command{Args: []string{pm.Binary(), "install"}, Env: pm.env()}
+}
+
+// RunCommand returns the command that runs a package.json script with extra arguments
+func (pm PackageManager) RunCommand(script string, args ...string) Command {
+	cmdArgs := []string{pm.Binary(), "run", script}
 	switch pm {
-	case Pnpm:
-		return []string{"pnpm", "run", "build"}
-	case Bun:
-		return []string{"bun", "run", "build"}
-	case Npm:
-		return []string{"npm", "run", "build"}
-	default:
-		return []string{"npm", "run", "build"}
+	case Deno:
+		cmdArgs = []string{"deno", "task", script}
+	case Pnpm, Npm:
+		// npm and pnpm need -- to pass arguments on to the script
+		if len(args) > 0 {
+			cmdArgs = append(cmdArgs, "--")
+		}
 	}
+	return Command{Args: append(cmdArgs, args...), Env: pm.env()}
+}
+
+// BuildCommand returns the build command for the package manager
+func (pm PackageManager) BuildCommand() Command {
+	return pm.RunCommand("build")
+}
+
+// DevCommand returns the command that starts the Astro dev server on port
+func (pm PackageManager) DevCommand(port int) Command {
+	return pm.RunCommand("dev", "--port", strconv.Itoa(port))
 }
 
 // String returns the string representation of the package manager
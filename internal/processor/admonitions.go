@@ -0,0 +1,152 @@
+package processor
+
+import (
+	"regexp"
+	"strings"
+)
+
+// admonitionStart matches the first line of an MkDocs admonition: !!! type "Title", or
+// ??? and ???+ for collapsible ones (pymdownx.details)
+var admonitionStart = regexp.MustCompile(`^(?:!!!|\?\?\?\+?)[ \t]+([A-Za-z][\w-]*)[^"]*(?:"(.*)")?[ \t]*$`)
+
+// asideTypes maps the MkDocs admonition types to the Starlight aside that shows them
+var asideTypes = map[string]string{
+	"note": "note", "abstract": "note", "summary": "note", "tldr": "note", "info": "note",
+	"todo": "note", "seealso": "note", "quote": "note", "cite": "note",
+	"tip": "tip", "hint": "tip", "important": "tip", "success": "tip", "check": "tip",
+	"done": "tip", "question": "tip", "help": "tip", "faq": "tip", "example": "tip",
+	"warning": "caution", "caution": "caution", "attention": "caution", "bug": "caution",
+	"danger": "danger", "error": "danger", "failure": "danger", "fail": "danger", "missing": "danger",
+}
+
+// convertAdmonitions rewrites MkDocs admonitions into Starlight asides:
+//
+//	!!! warning "Heads up"          :::caution[Heads up]
+//	    Indented body.         ->   Indented body.
+//	                                :::
+//
+// Types without an aside of the same name keep their name as the title. The closing
+// ::: takes the place of the blank line after the body where there is one; the line map
+// covers the ones it's added for. Admonitions inside code blocks are left alone.
+func convertAdmonitions(content string) (string, lineMap) {
+	if !strings.Contains(content, "!!!") && !strings.Contains(content, "???") {
+		return content, nil
+	}
+
+	lines := strings.Split(content, "\n")
+	out := make([]string, 0, len(lines))
+	origins := make(lineMap, 0, len(lines))
+	fence := ""
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		if marker := fenceMarker(line); fence == "" && marker != "" {
+			fence = marker
+		} else if fence != "" {
+			if strings.HasPrefix(strings.TrimSpace(line), fence) {
+				fence = ""
+			}
+			out, origins = append(out, line), append(origins, i+1)
+			continue
+		}
+
+		m := admonitionStart.FindStringSubmatch(line)
+		if m == nil {
+			out, origins = append(out, line), append(origins, i+1)
+			continue
+		}
+
+		// The body is the indented lines that follow, up to the last one with text
+		end := i + 1
+		for j := i + 1; j < len(lines); j++ {
+			if strings.TrimSpace(lines[j]) == "" {
+				continue
+			}
+			if !strings.HasPrefix(lines[j], "    ") && !strings.HasPrefix(lines[j], "\t") {
+				break
+			}
+			end = j + 1
+		}
+
+		body := make([]string, 0, end-i-1)
+		for _, bodyLine := range lines[i+1 : end] {
+			body = append(body, dedent(bodyLine))
+		}
+		inner, innerLines := convertAdmonitions(strings.Join(body, "\n"))
+
+		// Nested asides need fewer colons than the one around them
+		colons := strings.Repeat(":", max(3, maxColonFence(inner)+1))
+		out, origins = append(out, colons+asideOpening(strings.ToLower(m[1]), m[2])), append(origins, i+1)
+		if inner != "" {
+			for k, innerLine := range strings.Split(inner, "\n") {
+				out, origins = append(out, innerLine), append(origins, i+1+innerLines.origin(k+1))
+			}
+		}
+
+		i = end - 1
+		if end < len(lines) && strings.TrimSpace(lines[end]) == "" && end+1 < len(lines) {
+			// Replace the blank line after the body with the closing fence
+			i = end
+		}
+		out, origins = append(out, colons), append(origins, i+1)
+	}
+
+	return strings.Join(out, "\n"), origins
+}
+
+// asideOpening returns the aside type and title following the opening colons
+func asideOpening(kind, title string) string {
+	aside, ok := asideTypes[kind]
+	if !ok {
+		aside = "note"
+	}
+	if title == "" && aside != kind {
+		title = strings.ToUpper(kind[:1]) + kind[1:]
+	}
+	if title == "" {
+		return aside
+	}
+	return aside + "[" + title + "]"
+}
+
+// fenceMarker returns the backticks or tildes opening a fenced code block on line, or ""
+func fenceMarker(line string) string {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return ""
+	}
+	for _, char := range []string{"`", "~"} {
+		n := len(trimmed) - len(strings.TrimLeft(trimmed, char))
+		if n >= 3 {
+			return strings.Repeat(char, n)
+		}
+	}
+	return ""
+}
+
+// maxColonFence returns the length of the longest run of colons opening a line in content
+func maxColonFence(content string) int {
+	longest := 0
+	for _, line := range strings.Split(content, "\n") {
+		n := len(line) - len(strings.TrimLeft(line, ":"))
+		if n >= 3 {
+			longest = max(longest, n)
+		}
+	}
+	return longest
+}
+
+// dedent removes one level of indentation: a tab or up to four spaces
+func dedent(line string) string {
+	if strings.HasPrefix(line, "\t") {
+		return line[1:]
+	}
+	for i := 0; i < 4; i++ {
+		if !strings.HasPrefix(line, " ") {
+			break
+		}
+		line = line[1:]
+	}
+	return line
+}
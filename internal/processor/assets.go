@@ -0,0 +1,145 @@
+package processor
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/heidene/flashdoc/internal/links"
+	"github.com/heidene/flashdoc/internal/scanner"
+)
+
+// MissingAsset is a reference to a local file that doesn't exist in the source directory
+type MissingAsset struct {
+	Page   string // Source-relative path of the page containing the reference
+	Line   int    // Line of the reference in the page
+	Target string // Destination as written in the page
+	Reason string
+}
+
+func (m MissingAsset) String() string {
+	return fmt.Sprintf("%s:%d: %s %s", m.Page, m.Line, m.Reason, m.Target)
+}
+
+// rewriteAsset copies a local file referenced by a page into the workspace and returns
+// the new destination when the copy doesn't sit at the same relative location as in the source.
+//
+// Markdown images stay next to the page so Astro's image pipeline can optimise them.
+// Everything else (downloads, HTML <img> tags, links to images) is served from public/
+// and linked by absolute path, since relative URLs don't survive Starlight's page routing.
+func (p *Processor) rewriteAsset(pagePath, relPath string, ref links.Ref) (string, bool, error) {
+	publicURL := "/" + links.EscapePath(filepath.ToSlash(relPath))
+	_, suffix := links.Split(ref.Dest)
+	nextToPage := ref.Kind == links.Inline && ref.Image && scanner.IsImageFile(relPath)
+
+	if _, err := os.Stat(filepath.Join(p.sourceDir, relPath)); err != nil {
+		p.reportMissing(pagePath, ref, "missing asset")
+		// Astro fails the whole build on a missing content image, so leave it to the browser instead
+		if nextToPage {
+			return publicURL + suffix, true, nil
+		}
+		return "", false, nil
+	}
+
+	if nextToPage || p.opts.PublicDir == "" {
+		dst := filepath.Join(p.targetDir, docsDir(filepath.Dir(relPath)), filepath.Base(relPath))
+		if err := p.copyAsset(relPath, dst); err != nil {
+			return "", false, err
+		}
+
+		// A numbered directory losing its prefix can change the path from the page
+		before, err := filepath.Rel(filepath.Dir(pagePath), relPath)
+		if err != nil {
+			return "", false, nil
+		}
+		rel, err := filepath.Rel(filepath.Dir(p.targetPath(pagePath)), dst)
+		if err != nil || rel == before {
+			return "", false, nil
+		}
+		dest := links.EscapePath(filepath.ToSlash(rel))
+		if !strings.HasPrefix(dest, ".") {
+			dest = "./" + dest
+		}
+		return dest + suffix, true, nil
+	}
+
+	return publicURL + suffix, true, p.copyAsset(relPath, filepath.Join(p.opts.PublicDir, relPath))
+}
+
+// reportMissing records a reference that couldn't be resolved
+func (p *Processor) reportMissing(pagePath string, ref links.Ref, reason string) {
+	p.missing = append(p.missing, MissingAsset{
+		Page:   filepath.ToSlash(pagePath),
+		Line:   p.lines.origin(ref.Line),
+		Target: ref.Dest,
+		Reason: reason,
+	})
+}
+
+// copyAsset copies a source-relative asset to dst, once per run
+func (p *Processor) copyAsset(relPath, dst string) error {
+	if p.assets == nil {
+		p.assets = make(map[string][]string)
+	}
+	for _, copied := range p.assets[relPath] {
+		if copied == dst {
+			return nil
+		}
+	}
+
+	if err := copyFile(filepath.Join(p.sourceDir, relPath), dst); err != nil {
+		return fmt.Errorf("failed to copy asset %s: %w", relPath, err)
+	}
+
+	p.assets[relPath] = append(p.assets[relPath], dst)
+	return nil
+}
+
+// syncAsset refreshes or removes the copies of a changed asset
+func (p *Processor) syncAsset(relPath string) error {
+	src := filepath.Join(p.sourceDir, relPath)
+	_, statErr := os.Stat(src)
+
+	for _, dst := range p.assets[relPath] {
+		if os.IsNotExist(statErr) {
+			if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove asset %s: %w", relPath, err)
+			}
+			continue
+		}
+		if err := copyFile(src, dst); err != nil {
+			return fmt.Errorf("failed to copy asset %s: %w", relPath, err)
+		}
+	}
+
+	if os.IsNotExist(statErr) {
+		delete(p.assets, relPath)
+	}
+	return nil
+}
+
+// copyFile copies a file, creating the destination directory as needed
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
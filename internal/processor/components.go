@@ -0,0 +1,126 @@
+package processor
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/heidene/flashdoc/internal/mdx"
+	"github.com/heidene/flashdoc/internal/scanner"
+)
+
+// componentPath returns the path of a source-relative path inside the components directory
+func (p *Processor) componentPath(relPath string) (string, bool) {
+	if p.opts.Components == "" {
+		return "", false
+	}
+	sub, err := filepath.Rel(filepath.Clean(p.opts.Components), filepath.Clean(relPath))
+	if err != nil || sub == ".." || strings.HasPrefix(sub, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return sub, true
+}
+
+// copyComponents copies the components directory into the workspace so MDX imports resolve
+func (p *Processor) copyComponents() error {
+	if p.opts.Components == "" || p.opts.ComponentsTarget == "" {
+		return nil
+	}
+
+	src := filepath.Join(p.sourceDir, p.opts.Components)
+	if err := os.RemoveAll(p.opts.ComponentsTarget); err != nil {
+		return fmt.Errorf("failed to clear components: %w", err)
+	}
+	if _, err := os.Stat(src); os.IsNotExist(err) {
+		return nil
+	}
+
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return os.MkdirAll(filepath.Join(p.opts.ComponentsTarget, rel), 0755)
+		}
+		return copyFile(path, filepath.Join(p.opts.ComponentsTarget, rel))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to copy components from %s: %w", p.opts.Components, err)
+	}
+	return nil
+}
+
+// rewriteImports points relative imports in an MDX page at the workspace copy of the
+// components directory. Imports of other pages follow them to where they are published,
+// as number prefixes are dropped from their names; any other relative import is reported
+// since the file isn't copied into the workspace. Without a workspace copy to point at, as
+// in the native renderer, component imports are only checked.
+func (p *Processor) rewriteImports(pagePath, content string) string {
+	return mdx.RewriteImports(content, func(spec string, line int) string {
+		if !strings.HasPrefix(spec, "./") && !strings.HasPrefix(spec, "../") {
+			return spec
+		}
+
+		relPath := filepath.Clean(filepath.Join(filepath.Dir(pagePath), filepath.FromSlash(spec)))
+		if scanner.IsMarkdownFile(relPath) {
+			return p.rewritePageImport(pagePath, relPath, spec, line)
+		}
+
+		sub, ok := p.componentPath(relPath)
+		if !ok {
+			reason := "import outside the components directory:"
+			if p.opts.Components == "" {
+				reason = "relative import without a components directory:"
+			}
+			p.reportImport(pagePath, spec, line, reason)
+			return spec
+		}
+		if p.opts.ComponentsTarget == "" {
+			return spec
+		}
+		return relativeImport(p.targetPath(pagePath), filepath.Join(p.opts.ComponentsTarget, sub), spec)
+	})
+}
+
+// rewritePageImport points an import of another page at the page's published path
+func (p *Processor) rewritePageImport(pagePath, relPath, spec string, line int) string {
+	if _, err := os.Stat(filepath.Join(p.sourceDir, relPath)); err != nil {
+		p.reportImport(pagePath, spec, line, "import of missing page")
+		return spec
+	}
+	if p.opts.Ignore.Ignored(relPath, false) {
+		p.reportImport(pagePath, spec, line, "import of excluded page")
+		return spec
+	}
+	return relativeImport(p.targetPath(pagePath), p.targetPath(relPath), spec)
+}
+
+// reportImport records an import at line of the transformed page that won't resolve
+func (p *Processor) reportImport(pagePath, spec string, line int, reason string) {
+	p.missing = append(p.missing, MissingAsset{
+		Page:   filepath.ToSlash(pagePath),
+		Line:   p.lines.origin(line),
+		Target: spec,
+		Reason: reason,
+	})
+}
+
+// relativeImport returns the import specifier of target from the page written to pageTarget,
+// keeping spec when it already points there
+func relativeImport(pageTarget, target, spec string) string {
+	dest, err := filepath.Rel(filepath.Dir(pageTarget), target)
+	if err != nil || dest == filepath.Clean(filepath.FromSlash(spec)) {
+		return spec
+	}
+	dest = filepath.ToSlash(dest)
+	if !strings.HasPrefix(dest, ".") {
+		dest = "./" + dest
+	}
+	return dest
+}
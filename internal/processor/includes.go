@@ -0,0 +1,128 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// includeDirective matches mdBook's {{#include file}}, where the file can be followed by
+// :anchor or a :start:end line range. A backslash before it keeps the directive as text.
+var includeDirective = regexp.MustCompile(`(\\?)\{\{#include[ \t]+([^}\s]+)[ \t]*\}\}`)
+
+// anchorLine matches the ANCHOR: name and ANCHOR_END: name markers in included files
+var anchorLine = regexp.MustCompile(`\bANCHOR(_END)?:[ \t]*([\w-]+)`)
+
+// maxIncludeDepth stops files that include each other, like mdBook does
+const maxIncludeDepth = 10
+
+// expandIncludes replaces mdBook include directives in a page with the files they name,
+// resolved against the directory of the file containing the directive. Includes are
+// expanded inside code blocks too, where they are most often used. Missing files are
+// reported and their directives left in place. The lines of an included file map to the
+// line of its directive.
+func (p *Processor) expandIncludes(pagePath, content string) (string, lineMap) {
+	return p.expandIncludesFrom(pagePath, pagePath, content, 0, 0)
+}
+
+// expandIncludesFrom expands the include directives of filePath, a source-relative file
+// included depth levels below the page by a directive at line of the page
+func (p *Processor) expandIncludesFrom(pagePath, filePath, content string, depth, line int) (string, lineMap) {
+	if !strings.Contains(content, "{{#include") {
+		return content, nil
+	}
+
+	var w lineWriter
+	last := 0
+	lineAt := func(offset int) int {
+		if depth > 0 {
+			return line
+		}
+		return strings.Count(content[:offset], "\n") + 1
+	}
+
+	for _, m := range includeDirective.FindAllStringSubmatchIndex(content, -1) {
+		w.write(content[last:m[0]], lineAt(last), depth > 0)
+		last = m[1]
+
+		directive := content[m[0]:m[1]]
+		at := lineAt(m[0])
+		if m[3] > m[2] {
+			// Escaped: drop the backslash and keep the directive as written
+			w.write(directive[1:], at, false)
+			continue
+		}
+
+		spec := content[m[4]:m[5]]
+		target, selection, _ := strings.Cut(spec, ":")
+		relPath := filepath.Join(filepath.Dir(filePath), filepath.FromSlash(target))
+		included, err := os.ReadFile(filepath.Join(p.sourceDir, relPath))
+		if err != nil || depth >= maxIncludeDepth {
+			reason := "missing include"
+			if err == nil {
+				reason = "include nested too deeply:"
+			}
+			p.missing = append(p.missing, MissingAsset{
+				Page:   filepath.ToSlash(pagePath),
+				Line:   at,
+				Target: spec,
+				Reason: reason,
+			})
+			w.write(directive, at, false)
+			continue
+		}
+
+		text := selectLines(strings.TrimSuffix(string(included), "\n"), selection)
+		expanded, _ := p.expandIncludesFrom(pagePath, relPath, text, depth+1, at)
+		w.write(expanded, at, true)
+	}
+	w.write(content[last:], lineAt(last), depth > 0)
+	return w.out.String(), w.lines
+}
+
+// selectLines returns the part of an included file a directive asks for: everything, a
+// single line (:3), a line range (:3:10, :3:, ::10) or the lines between the anchor
+// markers of a name (:setup). Lines with anchor markers are always left out.
+func selectLines(content, selection string) string {
+	lines := strings.Split(content, "\n")
+
+	start, end := 1, len(lines)
+	anchor := ""
+	if selection != "" {
+		from, to, isRange := strings.Cut(selection, ":")
+		n, err := strconv.Atoi(from)
+		switch {
+		case err != nil && from != "":
+			anchor = from
+		case !isRange:
+			start, end = n, n
+		default:
+			if from != "" {
+				start = n
+			}
+			if n, err := strconv.Atoi(to); err == nil {
+				end = n
+			}
+		}
+	}
+	start = max(start, 1)
+	end = min(end, len(lines))
+
+	var selected []string
+	inside := anchor == ""
+	for i := start; i <= end; i++ {
+		line := lines[i-1]
+		if m := anchorLine.FindStringSubmatch(line); m != nil {
+			if m[2] == anchor {
+				inside = m[1] == ""
+			}
+			continue
+		}
+		if inside {
+			selected = append(selected, line)
+		}
+	}
+	return strings.Join(selected, "\n")
+}
@@ -0,0 +1,66 @@
+package processor
+
+import "strings"
+
+// lineMap records, for every line of transformed content, the 1-based line of the content
+// it was made from. A nil map stands for content whose lines didn't move.
+type lineMap []int
+
+// origin returns the line that line of the transformed content came from. Lines past the
+// end follow the last one.
+func (m lineMap) origin(line int) int {
+	if len(m) == 0 || line < 1 {
+		return line
+	}
+	if line > len(m) {
+		return m[len(m)-1] + line - len(m)
+	}
+	return m[line-1]
+}
+
+// then returns the map of content transformed by m and then by next
+func (m lineMap) then(next lineMap) lineMap {
+	if m == nil {
+		return next
+	}
+	if next == nil {
+		return m
+	}
+	composed := make(lineMap, len(next))
+	for i, line := range next {
+		composed[i] = m.origin(line)
+	}
+	return composed
+}
+
+// lineWriter builds transformed content, recording where each of its lines came from
+type lineWriter struct {
+	out     strings.Builder
+	lines   lineMap
+	midLine bool // The last write didn't end with a newline
+}
+
+// write appends text whose first line is line of the original content. Text spliced in
+// from elsewhere is fixed: all of its lines map to line.
+func (w *lineWriter) write(text string, line int, fixed bool) {
+	if text == "" {
+		return
+	}
+	if !w.midLine {
+		w.lines = append(w.lines, line)
+	}
+	n := 0
+	for i := 0; i < len(text)-1; i++ {
+		if text[i] != '\n' {
+			continue
+		}
+		n++
+		if fixed {
+			w.lines = append(w.lines, line)
+		} else {
+			w.lines = append(w.lines, line+n)
+		}
+	}
+	w.out.WriteString(text)
+	w.midLine = !strings.HasSuffix(text, "\n")
+}
@@ -0,0 +1,81 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/heidene/flashdoc/internal/links"
+	"github.com/heidene/flashdoc/internal/scanner"
+)
+
+// processLinks rewrites the local references in a page: links to other markdown
+// pages become the routes Starlight generates for them, and referenced assets
+// are copied into the workspace (see rewriteAsset).
+func (p *Processor) processLinks(pagePath, content string) (string, error) {
+	var copyErr error
+
+	refs := links.Find(content)
+	rewritten := links.Replace(content, refs, func(ref links.Ref) (string, bool) {
+		if copyErr != nil || !links.IsLocal(ref.Dest) {
+			return "", false
+		}
+
+		linkPath := links.Path(ref.Dest)
+		if linkPath == "" {
+			return "", false
+		}
+
+		relPath := filepath.Clean(filepath.Join(filepath.Dir(pagePath), filepath.FromSlash(linkPath)))
+		if relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+			p.reportMissing(pagePath, ref, "reference outside the source directory:")
+			return "", false
+		}
+
+		info, err := os.Stat(filepath.Join(p.sourceDir, relPath))
+		switch {
+		case err == nil && info.IsDir():
+			return p.rewriteDirLink(relPath, ref)
+		case scanner.IsMarkdownFile(relPath):
+			return p.rewritePageLink(pagePath, relPath, ref, err == nil)
+		}
+
+		dest, ok, err := p.rewriteAsset(pagePath, relPath, ref)
+		copyErr = err
+		return dest, ok
+	})
+
+	return rewritten, copyErr
+}
+
+// rewritePageLink maps a link to a markdown file onto the page's route, keeping any anchor
+func (p *Processor) rewritePageLink(pagePath, relPath string, ref links.Ref, exists bool) (string, bool) {
+	if !exists {
+		p.reportMissing(pagePath, ref, "link to missing page")
+		return "", false
+	}
+	if p.opts.Ignore.Ignored(relPath, false) {
+		p.reportMissing(pagePath, ref, "link to excluded page")
+		return "", false
+	}
+
+	_, suffix := links.Split(ref.Dest)
+	return p.Route(relPath) + suffix, true
+}
+
+// rewriteDirLink maps a link to a directory onto its index page's route
+func (p *Processor) rewriteDirLink(relPath string, ref links.Ref) (string, bool) {
+	_, suffix := links.Split(ref.Dest)
+	return p.Route(filepath.Join(relPath, "index.md")) + suffix, true
+}
+
+// Route returns the site URL Starlight generates for a source-relative markdown path.
+// It follows the processor's own renames (README.md -> index.md, 01-intro.md -> intro.md),
+// then Astro's slugging of each path segment; index pages are served at their directory.
+func (p *Processor) Route(relPath string) string {
+	rel, err := filepath.Rel(p.targetDir, p.targetPath(relPath))
+	if err != nil {
+		rel = relPath
+	}
+	return links.Route(filepath.ToSlash(rel))
+}
@@ -0,0 +1,83 @@
+package processor
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/heidene/flashdoc/internal/frontmatter"
+)
+
+// page records where a processed page came from, to map build errors back to the source,
+// and how it's listed in the sidebar
+type page struct {
+	source  string  // Source-relative path
+	head    int     // Lines of the processed page's frontmatter block
+	shift   int     // Transformed lines minus processed lines
+	lines   lineMap // Source line of each transformed line, nil if none moved
+	sidebar sidebarEntry
+}
+
+// recordPage remembers the source of a processed page. transformed is the page before
+// its frontmatter was injected, with p.lines mapping its lines to the source. Injecting
+// the frontmatter only changes the top of a page (frontmatter, the H1), so lines past
+// the frontmatter keep their distance from the end of the transformed page.
+func (p *Processor) recordPage(relPath, transformed, processed string) {
+	if p.pages == nil {
+		p.pages = make(map[string]page)
+	}
+
+	docsPath := p.docsPath(relPath)
+	head := 0
+	fm, body, err := frontmatter.Parse(processed)
+	if err == nil && body != processed {
+		head = lineCount(strings.TrimSuffix(processed, body))
+	}
+	if fm == nil {
+		fm = &frontmatter.Frontmatter{}
+	}
+
+	p.pages[docsPath] = page{
+		source:  relPath,
+		head:    head,
+		shift:   lineCount(transformed) - lineCount(processed),
+		lines:   p.lines,
+		sidebar: newSidebarEntry(docsPath, fm),
+	}
+}
+
+// docsPath returns the slash-separated path of a source file's page in the target directory
+func (p *Processor) docsPath(relPath string) string {
+	rel, err := filepath.Rel(p.targetDir, p.targetPath(relPath))
+	if err != nil {
+		return filepath.ToSlash(relPath)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// SourceLocation maps a page path relative to the target directory, and a line in it, to
+// the source file (joined with the source directory) and line it was processed from.
+// Lines inside the generated frontmatter map to line 1; line 0 stays 0.
+func (p *Processor) SourceLocation(docsPath string, line int) (string, int, bool) {
+	pg, ok := p.pages[filepath.ToSlash(docsPath)]
+	if !ok {
+		return "", 0, false
+	}
+
+	source := filepath.Join(p.sourceDir, pg.source)
+	switch {
+	case line <= 0:
+		return source, 0, true
+	case line <= pg.head:
+		return source, 1, true
+	}
+	return source, max(pg.lines.origin(line+pg.shift), 1), true
+}
+
+// lineCount returns the number of lines in content, counting an unterminated last line
+func lineCount(content string) int {
+	n := strings.Count(content, "\n")
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		n++
+	}
+	return n
+}
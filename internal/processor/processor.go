@@ -3,25 +3,61 @@ package processor
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/heidene/flashdoc/internal/frontmatter"
+	"github.com/heidene/flashdoc/internal/ignore"
+	"github.com/heidene/flashdoc/internal/logging"
+	"github.com/heidene/flashdoc/internal/mdx"
 	"github.com/heidene/flashdoc/internal/scanner"
 )
 
+// Options configures optional processing behaviour
+type Options struct {
+	Ignore       *ignore.Matcher // Source paths to skip; nil skips the defaults only
+	SidebarOrder []string        // Source paths in sidebar order; listed pages get a sidebar.order
+	PublicDir    string          // Workspace public/ directory for linked assets; empty keeps them next to the page
+	Titles       string          // Title strategy for pages without a title (see frontmatter.Options)
+
+	Components       string // Source-relative directory of MDX components; its files are never published as pages
+	ComponentsTarget string // Workspace directory the components are copied to; empty skips copying
+
+	FixFrontmatter bool // Repair or drop frontmatter values Starlight rejects instead of only reporting them
+	Admonitions    bool // Convert MkDocs admonitions (!!! note) to Starlight asides
+	Includes       bool // Expand mdBook {{#include file}} directives
+}
+
 // Processor handles markdown file processing and copying
 type Processor struct {
 	sourceDir   string
 	targetDir   string
+	opts        Options
 	filescopied int
+	assets      map[string][]string // Source-relative asset path -> copies in the workspace
+	missing     []MissingAsset
+	invalid     []InvalidFrontmatter
+	pages       map[string]page      // Target-relative page path -> where it came from
+	lines       lineMap              // Source lines of the page being processed, after includes and admonitions
+	categories  map[string]*Category // Source-relative directory -> its metadata, nil if it has none
 }
 
 // New creates a new processor
 func New(sourceDir, targetDir string) *Processor {
+	return NewWithOptions(sourceDir, targetDir, Options{})
+}
+
+// NewWithOptions creates a new processor with optional behaviour configured
+func NewWithOptions(sourceDir, targetDir string, opts Options) *Processor {
+	if opts.Ignore == nil {
+		opts.Ignore = ignore.New()
+	}
 	return &Processor{
 		sourceDir: sourceDir,
 		targetDir: targetDir,
+		opts:      opts,
 	}
 }
 
@@ -29,17 +65,24 @@ func New(sourceDir, targetDir string) *Processor {
 func (p *Processor) Process() error {
 	// Scan for markdown files
 	s := scanner.New(p.sourceDir)
+	s.SetIgnore(p.opts.Ignore)
 	files, err := s.Scan()
 	if err != nil {
 		return fmt.Errorf("failed to scan source directory: %w", err)
 	}
 
+	files = p.withoutComponents(files)
 	if len(files) == 0 {
 		return fmt.Errorf("no markdown files found in %s", p.sourceDir)
 	}
 
-	fmt.Printf("Found %d markdown files\n", len(files))
-	fmt.Printf("Processing %d files...\n", len(files))
+	if err := p.copyComponents(); err != nil {
+		return err
+	}
+
+	logging.Infof("Found %d markdown files", len(files))
+	logging.Infof("Processing %d files...", len(files))
+	start := time.Now()
 
 	// Process each file
 	for _, file := range files {
@@ -49,7 +92,12 @@ func (p *Processor) Process() error {
 		p.filescopied++
 	}
 
-	fmt.Printf("Copied %d files successfully\n", p.filescopied)
+	logging.Infof("Copied %d files successfully", p.filescopied)
+	if len(p.assets) > 0 {
+		logging.Infof("Copied %d assets", len(p.assets))
+	}
+	logging.Debugf("processed %d files in %s", p.filescopied, time.Since(start).Round(time.Microsecond))
+	p.warn(0, 0)
 
 	return nil
 }
@@ -62,15 +110,30 @@ func (p *Processor) processFile(file scanner.MarkdownFile) error {
 		return fmt.Errorf("failed to read file: %w", err)
 	}
 
-	// Determine target filename (rename README.md -> index.md)
-	targetFilename := filepath.Base(file.Path)
-	if strings.ToUpper(targetFilename) == "README.MD" {
-		targetFilename = "index.md"
+	// Build target path
+	targetPath := p.targetPath(file.Path)
+	targetDir := filepath.Dir(targetPath)
+
+	// Expand includes and convert admonitions; p.lines maps the lines they move back for
+	// reports and SourceLocation
+	prepared := p.Prepare(file.Path, string(content))
+	p.lines = prepared.lines
+
+	// Rewrite links to other pages and copy referenced images and files
+	rewritten, err := p.processLinks(file.Path, prepared.Content)
+	if err != nil {
+		return err
 	}
 
-	// Build target path
-	targetDir := filepath.Join(p.targetDir, filepath.Dir(file.Path))
-	targetPath := filepath.Join(targetDir, targetFilename)
+	// Point component imports at the workspace copy of the components directory
+	if mdx.IsMDX(file.Path) {
+		rewritten = p.rewriteImports(file.Path, rewritten)
+	}
+
+	// Read the sidebar metadata of the directories above the page
+	if err := p.loadCategories(file.Path); err != nil {
+		return err
+	}
 
 	// Get parent directory for title generation
 	parentDir := filepath.Dir(file.Path)
@@ -79,11 +142,29 @@ func (p *Processor) processFile(file scanner.MarkdownFile) error {
 	}
 
 	// Inject frontmatter
-	processed, err := frontmatter.Inject(string(content), filepath.Base(file.Path), parentDir)
+	fmOpts := frontmatter.Options{
+		SidebarOrder: p.sidebarOrder(file.Path),
+		Titles:       p.opts.Titles,
+	}
+	processed, err := frontmatter.InjectWithOptions(rewritten, filepath.Base(file.Path), parentDir, fmOpts)
 	if err != nil {
 		return fmt.Errorf("failed to inject frontmatter: %w", err)
 	}
 
+	// Catch values Starlight would reject before Astro fails the build on them
+	processed, err = p.validateFrontmatter(file.Path, processed)
+	if err != nil {
+		return fmt.Errorf("failed to repair frontmatter: %w", err)
+	}
+
+	// Names that only differ by an ordering prefix (01-intro.md, intro.md) publish the same page
+	if other, ok := p.pages[p.docsPath(file.Path)]; ok && other.source != file.Path {
+		if _, err := os.Stat(filepath.Join(p.sourceDir, other.source)); err == nil {
+			return fmt.Errorf("%s and %s are both published as %s; rename one of them",
+				filepath.ToSlash(other.source), filepath.ToSlash(file.Path), p.docsPath(file.Path))
+		}
+	}
+
 	// Create target directory
 	if err := os.MkdirAll(targetDir, 0755); err != nil {
 		return fmt.Errorf("failed to create target directory: %w", err)
@@ -93,10 +174,271 @@ func (p *Processor) processFile(file scanner.MarkdownFile) error {
 	if err := os.WriteFile(targetPath, []byte(processed), 0644); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
+	p.recordPage(file.Path, rewritten, processed)
+
+	return nil
+}
+
+// Prepared is a page's content after the transforms that move its lines, before its links
+// are rewritten
+type Prepared struct {
+	Content string
+	lines   lineMap
+}
+
+// SourceLine returns the line of the source file that a line of Content came from
+func (pr Prepared) SourceLine(line int) int {
+	return pr.lines.origin(line)
+}
+
+// Prepare expands the mdBook includes and converts the MkDocs admonitions of a page's
+// content, as enabled in the options. Includes that can't be expanded are reported.
+func (p *Processor) Prepare(relPath, content string) Prepared {
+	var lines lineMap
+
+	// Expand includes first, so included text is processed like the page's own
+	if p.opts.Includes {
+		var included lineMap
+		content, included = p.expandIncludes(relPath, content)
+		lines = lines.then(included)
+	}
+
+	// Unindent admonitions, so the links in them aren't taken for code
+	if p.opts.Admonitions {
+		var converted lineMap
+		content, converted = convertAdmonitions(content)
+		lines = lines.then(converted)
+	}
+
+	return Prepared{Content: content, lines: lines}
+}
+
+// Sync brings the target directory up to date for a single changed source path.
+// The path is relative to the source directory and may name a markdown file or a
+// directory; paths that no longer exist in the source are removed from the target.
+func (p *Processor) Sync(relPath string) error {
+	if _, ok := p.componentPath(relPath); ok {
+		return p.copyComponents()
+	}
+
+	if IsMetaFile(relPath) {
+		if dir := filepath.ToSlash(filepath.Dir(relPath)); dir != "." {
+			return p.loadCategory(dir)
+		}
+		return nil
+	}
+
+	fullPath := filepath.Join(p.sourceDir, relPath)
+
+	// Report broken references and invalid frontmatter found while re-processing, after
+	// dropping those of the pages being re-processed or removed
+	p.forget(relPath)
+	defer p.warn(len(p.missing), len(p.invalid))
+
+	info, err := os.Stat(fullPath)
+	if os.IsNotExist(err) {
+		return p.remove(relPath)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to access %s: %w", relPath, err)
+	}
+	if p.opts.Ignore.Ignored(relPath, info.IsDir()) {
+		return nil
+	}
+
+	if !info.IsDir() {
+		if !scanner.IsMarkdownFile(relPath) {
+			return p.syncAsset(relPath)
+		}
+		if err := p.processFile(scanner.MarkdownFile{Path: relPath, FullPath: fullPath}); err != nil {
+			return fmt.Errorf("failed to copy %s: %w", relPath, err)
+		}
+		return nil
+	}
+
+	// A directory appeared (or was moved in) - process everything below it
+	s := scanner.New(p.sourceDir)
+	s.SetIgnore(p.opts.Ignore)
+	files, err := s.ScanDir(relPath)
+	if err != nil {
+		return fmt.Errorf("failed to scan %s: %w", relPath, err)
+	}
+	for _, file := range p.withoutComponents(files) {
+		if err := p.processFile(file); err != nil {
+			return fmt.Errorf("failed to copy %s: %w", file.Path, err)
+		}
+	}
+
+	return nil
+}
+
+// SetSidebarOrder changes the configured sidebar order (see Options). Pages processed
+// before keep their position until they are synced again; Sync(".") re-processes them all.
+func (p *Processor) SetSidebarOrder(order []string) {
+	p.opts.SidebarOrder = order
+}
+
+// withoutComponents drops the files inside the components directory
+func (p *Processor) withoutComponents(files []scanner.MarkdownFile) []scanner.MarkdownFile {
+	if p.opts.Components == "" {
+		return files
+	}
+	pages := files[:0]
+	for _, file := range files {
+		if _, ok := p.componentPath(file.Path); !ok {
+			pages = append(pages, file)
+		}
+	}
+	return pages
+}
+
+// forget drops the reports of the page at a source path, or of the pages below it
+func (p *Processor) forget(relPath string) {
+	page := filepath.ToSlash(relPath)
+	covers := func(other string) bool {
+		return page == "." || other == page || strings.HasPrefix(other, page+"/")
+	}
+
+	missing := p.missing[:0]
+	for _, m := range p.missing {
+		if !covers(m.Page) {
+			missing = append(missing, m)
+		}
+	}
+	p.missing = missing
+
+	invalid := p.invalid[:0]
+	for _, f := range p.invalid {
+		if !covers(f.Page) {
+			invalid = append(invalid, f)
+		}
+	}
+	p.invalid = invalid
+}
+
+// remove deletes the processed output for a source path that no longer exists
+func (p *Processor) remove(relPath string) error {
+	if _, ok := p.assets[relPath]; ok {
+		return p.syncAsset(relPath)
+	}
+
+	if scanner.IsMarkdownFile(relPath) {
+		// Leave the page alone if another source file publishes it now
+		docsPath := p.docsPath(relPath)
+		if pg, ok := p.pages[docsPath]; ok && pg.source != relPath {
+			return nil
+		}
+		delete(p.pages, docsPath)
+		if err := os.Remove(p.targetPath(relPath)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", relPath, err)
+		}
+		return nil
+	}
+
+	// Not a markdown file, so it may have been a directory
+	prefix := filepath.ToSlash(relPath) + "/"
+	var removed []string
+	for docsPath, pg := range p.pages {
+		if strings.HasPrefix(filepath.ToSlash(pg.source), prefix) {
+			delete(p.pages, docsPath)
+			removed = append(removed, docsPath)
+		}
+	}
+	for dir := range p.categories {
+		if dir+"/" == prefix || strings.HasPrefix(dir, prefix) {
+			delete(p.categories, dir)
+		}
+	}
+
+	// Another source directory may publish into the same place (01-guides and guides), so
+	// then only the pages of this one are removed
+	targetDir := filepath.Join(p.targetDir, docsDir(relPath))
+	targetPrefix := filepath.ToSlash(docsDir(relPath)) + "/"
+	for docsPath := range p.pages {
+		if strings.HasPrefix(docsPath, targetPrefix) {
+			for _, docsPath := range removed {
+				if err := os.Remove(filepath.Join(p.targetDir, filepath.FromSlash(docsPath))); err != nil && !os.IsNotExist(err) {
+					return fmt.Errorf("failed to remove %s: %w", relPath, err)
+				}
+			}
+			return nil
+		}
+	}
+
+	if info, err := os.Stat(targetDir); err == nil && info.IsDir() {
+		if err := os.RemoveAll(targetDir); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", relPath, err)
+		}
+	}
 
 	return nil
 }
 
+// sidebarOrder returns the configured sidebar position of a source file (1-based). Unlisted
+// files with a number prefix like "02-" follow the listed ones in prefix order; others get 0.
+func (p *Processor) sidebarOrder(relPath string) int {
+	relPath = filepath.ToSlash(relPath)
+	for i, entry := range p.opts.SidebarOrder {
+		if strings.TrimPrefix(filepath.ToSlash(entry), "./") == relPath {
+			return i + 1
+		}
+	}
+	if order, _ := frontmatter.NumberPrefix(path.Base(relPath)); order > 0 {
+		return len(p.opts.SidebarOrder) + order
+	}
+	return 0
+}
+
+// targetPath maps a source-relative markdown path to its location in the target directory
+func (p *Processor) targetPath(relPath string) string {
+	// Rename README.md -> index.md and README.mdx -> index.mdx
+	targetFilename := filepath.Base(relPath)
+	switch strings.ToUpper(targetFilename) {
+	case "README.MD":
+		targetFilename = "index.md"
+	case "README.MDX":
+		targetFilename = "index.mdx"
+	}
+
+	// Drop ordering prefixes (01-intro.md -> intro.md); sidebar.order keeps the position
+	if _, rest := frontmatter.NumberPrefix(targetFilename); strings.TrimSuffix(rest, filepath.Ext(rest)) != "" {
+		targetFilename = rest
+	}
+
+	return filepath.Join(p.targetDir, docsDir(filepath.Dir(relPath)), targetFilename)
+}
+
+// docsDir maps a source-relative directory to the one its pages are published in, dropping
+// the ordering prefix of every segment (02-guides/01-setup -> guides/setup). Categories
+// keeps the order of the prefixes.
+func docsDir(dir string) string {
+	segments := strings.Split(filepath.ToSlash(dir), "/")
+	for i, segment := range segments {
+		_, segments[i] = frontmatter.NumberPrefix(segment)
+	}
+	return filepath.FromSlash(strings.Join(segments, "/"))
+}
+
+// warn prints the missing asset references and invalid frontmatter recorded since the given indexes
+func (p *Processor) warn(missingFrom, invalidFrom int) {
+	for _, m := range p.missing[missingFrom:] {
+		logging.Warnf("%s", m)
+	}
+	for _, f := range p.invalid[invalidFrom:] {
+		logging.Warnf("%s", f)
+	}
+}
+
+// Missing returns the asset references that couldn't be resolved during processing
+func (p *Processor) Missing() []MissingAsset {
+	return p.missing
+}
+
+// Invalid returns the frontmatter values Starlight would reject, found during processing
+func (p *Processor) Invalid() []InvalidFrontmatter {
+	return p.invalid
+}
+
 // GetCopiedCount returns the number of files copied
 func (p *Processor) GetCopiedCount() int {
 	return p.filescopied
@@ -0,0 +1,52 @@
+package processor
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/heidene/flashdoc/internal/frontmatter"
+	"github.com/heidene/flashdoc/internal/schema"
+)
+
+// InvalidFrontmatter is a frontmatter value of a page that Starlight's docs schema rejects
+type InvalidFrontmatter struct {
+	Page string // Source-relative path of the page
+	schema.Problem
+	Fixed bool // The value was repaired or removed in the workspace copy of the page
+}
+
+func (f InvalidFrontmatter) String() string {
+	s := fmt.Sprintf("%s: frontmatter %s", f.Page, f.Problem)
+	switch {
+	case f.Fixed && f.Repaired != nil:
+		s += ", repaired"
+	case f.Fixed:
+		s += ", removed"
+	}
+	return s
+}
+
+// validateFrontmatter checks a processed page against the Starlight docs schema, so a bad
+// value is reported with its page and key instead of failing the Astro build. With
+// FixFrontmatter the page is returned with the values repaired or removed.
+func (p *Processor) validateFrontmatter(pagePath, content string) (string, error) {
+	values, ok := frontmatter.Values(content)
+	if !ok {
+		return content, nil
+	}
+
+	problems := schema.Validate(values)
+	for _, problem := range problems {
+		p.invalid = append(p.invalid, InvalidFrontmatter{
+			Page:    filepath.ToSlash(pagePath),
+			Problem: problem,
+			Fixed:   p.opts.FixFrontmatter,
+		})
+	}
+	if len(problems) == 0 || !p.opts.FixFrontmatter {
+		return content, nil
+	}
+
+	schema.Repair(values, problems)
+	return frontmatter.SetValues(content, values)
+}
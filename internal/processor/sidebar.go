@@ -0,0 +1,297 @@
+package processor
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/heidene/flashdoc/internal/frontmatter"
+	"github.com/heidene/flashdoc/internal/links"
+	"github.com/heidene/flashdoc/internal/template"
+	"gopkg.in/yaml.v3"
+)
+
+// MetaFiles are the directory metadata files, in order of precedence. _category_.json
+// is the Docusaurus format.
+var MetaFiles = []string{"_meta.yaml", "_meta.yml", "_category_.json"}
+
+// Category is the sidebar group of a directory, as set by its metadata file
+type Category struct {
+	Label     string          // Group label, empty uses the directory name
+	Order     *int            // Position among the directory's siblings; nil sorts after ordered items
+	Collapsed bool            // Start with the group collapsed
+	Badge     *template.Badge // Badge next to the label
+}
+
+// categoryFile is the content of a metadata file. Docusaurus calls the order "position".
+type categoryFile struct {
+	Label     string      `yaml:"label"`
+	Order     *int        `yaml:"order"`
+	Position  *int        `yaml:"position"`
+	Collapsed bool        `yaml:"collapsed"`
+	Badge     interface{} `yaml:"badge"`
+}
+
+// IsMetaFile checks if a path names a directory metadata file
+func IsMetaFile(relPath string) bool {
+	name := filepath.Base(relPath)
+	for _, meta := range MetaFiles {
+		if name == meta {
+			return true
+		}
+	}
+	return false
+}
+
+// loadCategories reads the metadata files of the directories above a page that haven't been read yet
+func (p *Processor) loadCategories(relPath string) error {
+	if p.categories == nil {
+		p.categories = make(map[string]*Category)
+	}
+	for dir := path.Dir(filepath.ToSlash(relPath)); dir != "."; dir = path.Dir(dir) {
+		if _, ok := p.categories[dir]; ok {
+			// Directories above it were read with it
+			return nil
+		}
+		if err := p.loadCategory(dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadCategory (re-)reads the metadata file of a source directory, recording nil if it has none
+func (p *Processor) loadCategory(dir string) error {
+	if p.categories == nil {
+		p.categories = make(map[string]*Category)
+	}
+	p.categories[dir] = nil
+
+	for _, name := range MetaFiles {
+		relPath := path.Join(dir, name)
+		content, err := os.ReadFile(filepath.Join(p.sourceDir, relPath))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", relPath, err)
+		}
+
+		category, err := parseCategory(content)
+		if err != nil {
+			return fmt.Errorf("%s: %w", relPath, err)
+		}
+		p.categories[dir] = category
+		return nil
+	}
+	return nil
+}
+
+// parseCategory reads a _meta.yaml or _category_.json file
+func parseCategory(content []byte) (*Category, error) {
+	var file categoryFile
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil, err
+	}
+
+	category := &Category{Label: file.Label, Order: file.Order, Collapsed: file.Collapsed}
+	if category.Order == nil {
+		category.Order = file.Position
+	}
+	if file.Badge != nil {
+		badge, ok := parseBadge(file.Badge)
+		if !ok {
+			return nil, fmt.Errorf("badge must be text or a mapping with text and variant")
+		}
+		if !template.ValidBadgeVariant(badge.Variant) {
+			return nil, fmt.Errorf("badge variant must be one of %s, got %q", strings.Join(template.BadgeVariants, ", "), badge.Variant)
+		}
+		category.Badge = badge
+	}
+	return category, nil
+}
+
+// parseBadge reads a badge given as its text or as a mapping with text and variant
+func parseBadge(value interface{}) (*template.Badge, bool) {
+	switch v := value.(type) {
+	case string:
+		return &template.Badge{Text: v}, v != ""
+	case map[string]interface{}:
+		text, _ := v["text"].(string)
+		variant, _ := v["variant"].(string)
+		return &template.Badge{Text: text, Variant: variant}, text != ""
+	}
+	return nil, false
+}
+
+// sidebarEntry is how a page is listed in the sidebar, read from its processed frontmatter
+type sidebarEntry struct {
+	label  string
+	link   string
+	order  *int // nil without sidebar.order
+	badge  *template.Badge
+	hidden bool // sidebar.hidden or a draft
+}
+
+// newSidebarEntry reads the sidebar settings of a processed page at docsPath
+func newSidebarEntry(docsPath string, fm *frontmatter.Frontmatter) sidebarEntry {
+	entry := sidebarEntry{label: fm.Title, link: links.Route(docsPath)}
+	if slug, ok := fm.Other["slug"].(string); ok && strings.Trim(slug, "/") != "" {
+		entry.link = "/" + strings.Trim(slug, "/") + "/"
+	}
+	if draft, ok := fm.Other["draft"].(bool); ok && draft {
+		entry.hidden = true
+	}
+
+	sidebar, _ := fm.Other["sidebar"].(map[string]interface{})
+	if label, ok := sidebar["label"].(string); ok && label != "" {
+		entry.label = label
+	}
+	if order, ok := sidebar["order"].(int); ok {
+		entry.order = &order
+	}
+	if hidden, ok := sidebar["hidden"].(bool); ok && hidden {
+		entry.hidden = true
+	}
+	if badge, ok := parseBadge(sidebar["badge"]); ok {
+		entry.badge = badge
+	}
+	if entry.label == "" {
+		entry.label = frontmatter.GenerateTitle(path.Base(docsPath), "")
+	}
+	return entry
+}
+
+// sidebarNode is a page link or directory group while the sidebar is arranged
+type sidebarNode struct {
+	item     template.SidebarItem
+	order    *int
+	key      string // File or directory name
+	children []*sidebarNode
+}
+
+// Sidebar returns an explicit Starlight sidebar for the processed pages, grouped by
+// directory and using the labels, order, collapsed state and badges of the directories'
+// metadata files. Items are sorted like Starlight's autogenerated sidebar: by order
+// (items without one last), then index pages, then by label. Without any metadata file or
+// numbered directory it returns nil, leaving the sidebar to Starlight.
+func (p *Processor) Sidebar() []template.SidebarItem {
+	categories := p.Categories()
+	if len(categories) == 0 {
+		return nil
+	}
+
+	root := &sidebarNode{}
+	groups := map[string]*sidebarNode{".": root}
+
+	var groupFor func(dir string) *sidebarNode
+	groupFor = func(dir string) *sidebarNode {
+		if group, ok := groups[dir]; ok {
+			return group
+		}
+		parent := groupFor(path.Dir(dir))
+		group := &sidebarNode{
+			item: template.SidebarItem{Label: template.GenerateTitle(path.Base(dir))},
+			key:  path.Base(dir),
+		}
+		if category := categories[dir]; category != nil {
+			if category.Label != "" {
+				group.item.Label = category.Label
+			}
+			group.order = category.Order
+			group.item.Collapsed = category.Collapsed
+			group.item.Badge = category.Badge
+		}
+		parent.children = append(parent.children, group)
+		groups[dir] = group
+		return group
+	}
+
+	docsPaths := make([]string, 0, len(p.pages))
+	for docsPath := range p.pages {
+		docsPaths = append(docsPaths, docsPath)
+	}
+	sort.Strings(docsPaths)
+
+	for _, docsPath := range docsPaths {
+		entry := p.pages[docsPath].sidebar
+		if entry.hidden {
+			continue
+		}
+		group := groupFor(path.Dir(docsPath))
+		group.children = append(group.children, &sidebarNode{
+			item:  template.SidebarItem{Label: entry.label, Link: entry.link, Badge: entry.badge},
+			order: entry.order,
+			key:   path.Base(docsPath),
+		})
+	}
+
+	return sidebarItems(root.children)
+}
+
+// PageLink returns the sidebar label and link of a processed page by its source-relative
+// path, or false if no page was published from it
+func (p *Processor) PageLink(relPath string) (label, link string, ok bool) {
+	pg, ok := p.pages[p.docsPath(filepath.FromSlash(relPath))]
+	if !ok {
+		return "", "", false
+	}
+	return pg.sidebar.label, pg.sidebar.link, true
+}
+
+// Categories returns the sidebar groups of the processed directories by the target-relative
+// path they're published at. A group has its directory's metadata, and the order of the
+// number prefix its path dropped (02-guides -> guides) unless the metadata sets one.
+// Directories with neither are left out.
+func (p *Processor) Categories() map[string]*Category {
+	categories := make(map[string]*Category)
+	for dir, category := range p.categories {
+		order, _ := frontmatter.NumberPrefix(path.Base(dir))
+		if category == nil && order == 0 {
+			continue
+		}
+		group := &Category{}
+		if category != nil {
+			*group = *category
+		}
+		if group.Order == nil && order > 0 {
+			group.Order = &order
+		}
+		categories[filepath.ToSlash(docsDir(dir))] = group
+	}
+	return categories
+}
+
+// sidebarItems sorts nodes recursively and returns them as sidebar items
+func sidebarItems(nodes []*sidebarNode) []template.SidebarItem {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		a, b := nodes[i], nodes[j]
+		if (a.order != nil) != (b.order != nil) {
+			return a.order != nil
+		}
+		if a.order != nil && *a.order != *b.order {
+			return *a.order < *b.order
+		}
+		if isIndex(a.key) != isIndex(b.key) {
+			return isIndex(a.key)
+		}
+		return strings.ToLower(a.item.Label) < strings.ToLower(b.item.Label)
+	})
+
+	items := make([]template.SidebarItem, len(nodes))
+	for i, node := range nodes {
+		items[i] = node.item
+		if node.item.Link == "" {
+			items[i].Items = sidebarItems(node.children)
+		}
+	}
+	return items
+}
+
+// isIndex checks if a file is a directory's index page
+func isIndex(name string) bool {
+	return strings.TrimSuffix(strings.ToLower(name), path.Ext(name)) == "index"
+}
@@ -7,38 +7,29 @@ import (
 	"time"
 
 	"github.com/briandowns/spinner"
-	"github.com/charmbracelet/lipgloss"
+	"github.com/heidene/flashdoc/internal/logging"
 )
 
-var (
-	// Styles
-	successStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#00ff00")).
-			Bold(true)
-
-	infoStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#00d7ff")).
-			Bold(true)
-
-	dimStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#666666"))
-)
-
-// Spinner provides a styled progress indicator
+// Spinner provides a styled progress indicator. It only animates on interactive terminals;
+// elsewhere (pipes, CI, --quiet, --verbose, JSON logs) just the outcome is logged.
 type Spinner struct {
 	spinner   *spinner.Spinner
+	log       *logging.Logger
 	startTime time.Time
 	message   string
+	running   bool
 }
 
-// New creates a new progress spinner
+// New creates a new progress spinner that reports through the default logger
 func New(message string) *Spinner {
+	log := logging.Default()
 	s := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
-	s.Suffix = " " + infoStyle.Render(message)
+	s.Suffix = " " + log.Paint(logging.Info, message)
 	s.Writer = os.Stderr
 
 	return &Spinner{
 		spinner:   s,
+		log:       log,
 		startTime: time.Now(),
 		message:   message,
 	}
@@ -47,39 +38,39 @@ func New(message string) *Spinner {
 // Start begins the spinner animation
 func (s *Spinner) Start() {
 	s.startTime = time.Now()
+	if !s.log.Interactive() {
+		s.log.Debugf("%s", s.message)
+		return
+	}
 	s.spinner.Start()
+	s.running = true
 }
 
 // Stop stops the spinner and shows a success message
 func (s *Spinner) Stop(successMessage string) {
-	s.spinner.Stop()
+	s.halt()
 	duration := time.Since(s.startTime)
-
-	fmt.Fprintf(os.Stderr, "\r%s %s %s\n",
-		successStyle.Render("✓"),
-		successMessage,
-		dimStyle.Render(fmt.Sprintf("(%s)", formatDuration(duration))),
-	)
+	s.log.Successf("%s %s", successMessage, s.log.Paint(logging.Dim, fmt.Sprintf("(%s)", formatDuration(duration))))
 }
 
 // StopWithError stops the spinner and shows an error message
 func (s *Spinner) StopWithError(errorMessage string) {
-	s.spinner.Stop()
-
-	errorStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#ff0000")).
-		Bold(true)
+	s.halt()
+	s.log.Failf("%s", errorMessage)
+}
 
-	fmt.Fprintf(os.Stderr, "\r%s %s\n",
-		errorStyle.Render("✗"),
-		errorMessage,
-	)
+// halt stops the animation if it is running
+func (s *Spinner) halt() {
+	if s.running {
+		s.spinner.Stop()
+		s.running = false
+	}
 }
 
 // Update changes the spinner message
 func (s *Spinner) Update(message string) {
 	s.message = message
-	s.spinner.Suffix = " " + infoStyle.Render(message)
+	s.spinner.Suffix = " " + s.log.Paint(logging.Info, message)
 }
 
 // formatDuration formats a duration in a human-readable way
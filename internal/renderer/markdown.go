@@ -0,0 +1,132 @@
+package renderer
+
+import (
+	"bytes"
+	"path"
+	"strconv"
+	"strings"
+
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/heidene/flashdoc/internal/links"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+)
+
+// Code highlighting styles for light and dark color schemes
+const (
+	lightCodeStyle = "github"
+	darkCodeStyle  = "github-dark"
+)
+
+// TOCEntry is a heading listed in a page's table of contents
+type TOCEntry struct {
+	ID    string
+	Text  string
+	Level int
+}
+
+// newMarkdown creates the CommonMark + GFM converter with class-based code highlighting
+func newMarkdown() goldmark.Markdown {
+	return goldmark.New(
+		goldmark.WithExtensions(
+			extension.GFM,
+			highlighting.NewHighlighting(
+				highlighting.WithStyle(lightCodeStyle),
+				highlighting.WithFormatOptions(chromahtml.WithClasses(true)),
+			),
+		),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+		goldmark.WithRendererOptions(html.WithUnsafe()),
+	)
+}
+
+// renderMarkdown converts a page body to HTML and collects its table of contents.
+// A leading H1 matching the page title is dropped since the layout renders the title.
+func renderMarkdown(md goldmark.Markdown, body, title, pageRel string) (string, []TOCEntry, error) {
+	source := []byte(body)
+	ctx := parser.NewContext(parser.WithIDs(newSlugIDs()))
+	doc := md.Parser().Parse(text.NewReader(source), parser.WithContext(ctx))
+
+	if first, ok := doc.FirstChild().(*ast.Heading); ok && first.Level == 1 &&
+		strings.TrimSpace(string(first.Text(source))) == title {
+		doc.RemoveChild(doc, first)
+	}
+
+	var toc []TOCEntry
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch node := n.(type) {
+		case *ast.Heading:
+			if node.Level == 2 || node.Level == 3 {
+				if id, ok := node.AttributeString("id"); ok {
+					toc = append(toc, TOCEntry{ID: string(id.([]byte)), Text: string(node.Text(source)), Level: node.Level})
+				}
+			}
+		case *ast.Image:
+			// Images are copied next to the page, but pages are served one directory deeper
+			if dest := string(node.Destination); links.IsLocal(dest) {
+				node.Destination = []byte(path.Join("/", path.Dir(pageRel), dest))
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+
+	var buf bytes.Buffer
+	if err := md.Renderer().Render(&buf, source, doc); err != nil {
+		return "", nil, err
+	}
+	return buf.String(), toc, nil
+}
+
+// highlightCSS returns the code highlighting stylesheet for light and dark mode
+func highlightCSS() (string, error) {
+	formatter := chromahtml.New(chromahtml.WithClasses(true))
+
+	var buf bytes.Buffer
+	if err := formatter.WriteCSS(&buf, styles.Get(lightCodeStyle)); err != nil {
+		return "", err
+	}
+	buf.WriteString("@media (prefers-color-scheme: dark) {\n")
+	if err := formatter.WriteCSS(&buf, styles.Get(darkCodeStyle)); err != nil {
+		return "", err
+	}
+	buf.WriteString("}\n")
+	return buf.String(), nil
+}
+
+// slugIDs generates heading ids like github-slugger so anchors match the Astro renderer
+type slugIDs struct {
+	seen map[string]int
+}
+
+func newSlugIDs() *slugIDs {
+	return &slugIDs{seen: make(map[string]int)}
+}
+
+// Generate returns a unique id for a heading's text
+func (s *slugIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	slug := links.Slugify(string(value))
+	if slug == "" {
+		slug = "heading"
+	}
+	if n, ok := s.seen[slug]; ok {
+		s.seen[slug] = n + 1
+		return []byte(slug + "-" + strconv.Itoa(n+1))
+	}
+	s.seen[slug] = 0
+	return []byte(slug)
+}
+
+// Put records an explicitly set id
+func (s *slugIDs) Put(value []byte) {
+	s.seen[string(value)] = 0
+}
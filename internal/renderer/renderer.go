@@ -0,0 +1,386 @@
+package renderer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/heidene/flashdoc/internal/frontmatter"
+	"github.com/heidene/flashdoc/internal/links"
+	"github.com/heidene/flashdoc/internal/logging"
+	"github.com/heidene/flashdoc/internal/mdx"
+	"github.com/heidene/flashdoc/internal/processor"
+	"github.com/heidene/flashdoc/internal/scanner"
+	"github.com/heidene/flashdoc/internal/template"
+	"github.com/heidene/flashdoc/internal/theme"
+	"github.com/yuin/goldmark"
+)
+
+//go:embed theme/*
+var themeFiles embed.FS
+
+// assetsDir is where the stylesheet and logo are written in the output directory
+const assetsDir = "_flashdoc"
+
+// Options configures the native renderer
+type Options struct {
+	Title     string            // Site title
+	Logo      string            // Logo image file to show in the header
+	Favicon   string            // Favicon file
+	CustomCSS []string          // Stylesheets appended to the theme's, in order
+	Accent    *theme.Accent     // Accent color, nil keeps the theme's
+	Social    map[string]string // Social icon name -> link
+	PublicDir string            // Directory of static files copied to the site root
+
+	Categories map[string]*processor.Category // Directory -> sidebar group label, order and collapsed state
+}
+
+// Renderer builds a static HTML site from processed markdown without Node.js
+type Renderer struct {
+	docsDir string
+	outDir  string
+	opts    Options
+	md      goldmark.Markdown
+	layout  *htmltemplate.Template
+	log     *logging.Logger
+}
+
+// page is a markdown page collected from the docs directory
+type page struct {
+	Rel          string // Slash-separated path in the docs directory
+	Route        string
+	Title        string
+	Description  string
+	SidebarLabel string
+	Order        *int // nil without sidebar.order
+	Body         string
+}
+
+// layoutData is passed to the page template
+type layoutData struct {
+	SiteTitle   string
+	Title       string
+	Description string
+	Content     htmltemplate.HTML
+	TOC         []TOCEntry
+	Sidebar     []*NavItem
+	Prev        *NavItem
+	Next        *NavItem
+	Logo        string
+	Favicon     string
+	Social      []socialLink
+}
+
+// socialLink is a header link to a social profile
+type socialLink struct {
+	Label string
+	URL   string
+}
+
+// New creates a renderer that reads processed pages from docsDir and writes HTML to outDir
+func New(docsDir, outDir string, opts Options, log *logging.Logger) *Renderer {
+	return &Renderer{
+		docsDir: docsDir,
+		outDir:  outDir,
+		opts:    opts,
+		md:      newMarkdown(),
+		log:     logging.Or(log),
+	}
+}
+
+// Build renders every page, replacing the previous contents of the output directory
+func (r *Renderer) Build() error {
+	r.log.Infof("🔨 Rendering site with the native renderer...")
+
+	if r.layout == nil {
+		layout, err := htmltemplate.ParseFS(themeFiles, "theme/layout.html")
+		if err != nil {
+			return fmt.Errorf("failed to load layout: %w", err)
+		}
+		r.layout = layout
+	}
+
+	if err := os.RemoveAll(r.outDir); err != nil {
+		return fmt.Errorf("failed to clean output directory: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Join(r.outDir, assetsDir), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	pages, err := r.collect()
+	if err != nil {
+		return err
+	}
+	if len(pages) == 0 {
+		return fmt.Errorf("no pages to render in %s", r.docsDir)
+	}
+
+	if r.opts.PublicDir != "" {
+		if err := copyTree(r.opts.PublicDir, r.outDir); err != nil {
+			return fmt.Errorf("failed to copy public files: %w", err)
+		}
+	}
+
+	if err := r.writeAssets(); err != nil {
+		return err
+	}
+
+	sidebar := buildSidebar(pages, r.opts.Categories)
+	order := flatten(sidebar)
+
+	for _, p := range pages {
+		if err := r.renderPage(p, sidebar, order); err != nil {
+			return fmt.Errorf("failed to render %s: %w", p.Rel, err)
+		}
+	}
+
+	if err := r.render404(sidebar); err != nil {
+		return fmt.Errorf("failed to render 404 page: %w", err)
+	}
+
+	r.log.Successf("Rendered %d pages", len(pages))
+	return nil
+}
+
+// collect reads the markdown pages and copies every other file (images next to pages) as-is
+func (r *Renderer) collect() ([]*page, error) {
+	var pages []*page
+
+	err := filepath.WalkDir(r.docsDir, func(fullPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(r.docsDir, fullPath)
+		if err != nil {
+			return err
+		}
+		rel := filepath.ToSlash(relPath)
+
+		if !scanner.IsMarkdownFile(rel) {
+			return copyFile(fullPath, filepath.Join(r.outDir, relPath))
+		}
+
+		content, err := os.ReadFile(fullPath)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", rel, err)
+		}
+
+		pages = append(pages, newPage(rel, string(content)))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pages: %w", err)
+	}
+
+	return pages, nil
+}
+
+// newPage builds a page from processed markdown, reading its title and sidebar settings
+func newPage(rel, content string) *page {
+	p := &page{Rel: rel, Route: links.Route(rel), Body: content}
+
+	fm, body, _ := frontmatter.Parse(content)
+	if fm != nil {
+		p.Body = body
+		p.Title = fm.Title
+		p.Description = fm.Description
+		if sidebar, ok := fm.Other["sidebar"].(map[string]interface{}); ok {
+			if order, ok := sidebar["order"].(int); ok {
+				p.Order = &order
+			}
+			if label, ok := sidebar["label"].(string); ok {
+				p.SidebarLabel = label
+			}
+		}
+	}
+
+	// Components can't be rendered without Astro; their tags pass through as raw HTML
+	if mdx.IsMDX(rel) {
+		_, p.Body = mdx.SplitESM(p.Body)
+	}
+
+	if p.Title == "" {
+		name := path.Base(rel)
+		p.Title = frontmatter.GenerateTitle(name, parentDir(rel))
+	}
+	if p.SidebarLabel == "" {
+		p.SidebarLabel = p.Title
+	}
+
+	return p
+}
+
+// renderPage writes a page to <route>/index.html
+func (r *Renderer) renderPage(p *page, sidebar, order []*NavItem) error {
+	content, toc, err := renderMarkdown(r.md, p.Body, p.Title, p.Rel)
+	if err != nil {
+		return err
+	}
+
+	data := r.baseData(p.Title, markCurrent(sidebar, p.Route))
+	data.Description = p.Description
+	data.Content = htmltemplate.HTML(content)
+	data.TOC = toc
+
+	for i, item := range order {
+		if item.URL != p.Route {
+			continue
+		}
+		if i > 0 {
+			data.Prev = order[i-1]
+		}
+		if i < len(order)-1 {
+			data.Next = order[i+1]
+		}
+		break
+	}
+
+	return r.writePage(filepath.Join(r.outDir, filepath.FromSlash(strings.Trim(p.Route, "/")), "index.html"), data)
+}
+
+// render404 writes the not found page served by the static server
+func (r *Renderer) render404(sidebar []*NavItem) error {
+	data := r.baseData("Page not found", sidebar)
+	data.Content = htmltemplate.HTML(`<p>This page doesn't exist. Try the navigation on the left.</p>`)
+	return r.writePage(filepath.Join(r.outDir, "404.html"), data)
+}
+
+// baseData fills in the site-wide template fields
+func (r *Renderer) baseData(title string, sidebar []*NavItem) layoutData {
+	data := layoutData{
+		SiteTitle: r.opts.Title,
+		Title:     title,
+		Sidebar:   sidebar,
+	}
+	if r.opts.Logo != "" {
+		data.Logo = "/" + assetsDir + "/" + filepath.Base(r.opts.Logo)
+	}
+	if r.opts.Favicon != "" {
+		data.Favicon = "/" + assetsDir + "/" + filepath.Base(r.opts.Favicon)
+	}
+	for _, icon := range sortedKeys(r.opts.Social) {
+		data.Social = append(data.Social, socialLink{Label: template.SocialLabel(icon), URL: r.opts.Social[icon]})
+	}
+	return data
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// writePage executes the layout into a file
+func (r *Renderer) writePage(target string, data layoutData) error {
+	var buf bytes.Buffer
+	if err := r.layout.ExecuteTemplate(&buf, "layout.html", data); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(target, buf.Bytes(), 0644)
+}
+
+// writeAssets writes the stylesheet with the highlighting styles, accent color and custom
+// stylesheets, the logo and the favicon
+func (r *Renderer) writeAssets() error {
+	style, err := themeFiles.ReadFile("theme/style.css")
+	if err != nil {
+		return fmt.Errorf("failed to read stylesheet: %w", err)
+	}
+
+	code, err := highlightCSS()
+	if err != nil {
+		return fmt.Errorf("failed to generate highlighting styles: %w", err)
+	}
+
+	css := append(style, []byte("\n"+code)...)
+	if r.opts.Accent != nil {
+		css = append(css, []byte("\n"+r.opts.Accent.NativeCSS())...)
+	}
+	for _, path := range r.opts.CustomCSS {
+		custom, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read stylesheet: %w", err)
+		}
+		css = append(css, []byte("\n")...)
+		css = append(css, custom...)
+	}
+	if err := os.WriteFile(filepath.Join(r.outDir, assetsDir, "style.css"), css, 0644); err != nil {
+		return fmt.Errorf("failed to write stylesheet: %w", err)
+	}
+
+	if r.opts.Logo != "" {
+		if err := copyFile(r.opts.Logo, filepath.Join(r.outDir, assetsDir, filepath.Base(r.opts.Logo))); err != nil {
+			return fmt.Errorf("failed to copy logo: %w", err)
+		}
+	}
+	if r.opts.Favicon != "" {
+		if err := copyFile(r.opts.Favicon, filepath.Join(r.outDir, assetsDir, filepath.Base(r.opts.Favicon))); err != nil {
+			return fmt.Errorf("failed to copy favicon: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// copyTree copies the files below src into dst
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(fullPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && fullPath == src {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(src, fullPath)
+		if err != nil {
+			return err
+		}
+		return copyFile(fullPath, filepath.Join(dst, rel))
+	})
+}
+
+// copyFile copies a file, creating the destination directory as needed
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
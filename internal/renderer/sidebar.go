@@ -0,0 +1,145 @@
+package renderer
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/heidene/flashdoc/internal/processor"
+	"github.com/heidene/flashdoc/internal/template"
+)
+
+// NavItem is a page link or a group of links in the sidebar
+type NavItem struct {
+	Label     string
+	URL       string // Empty for groups
+	Current   bool
+	Collapsed bool   // Group starts closed
+	Badge     string // Text shown next to the label
+	Children  []*NavItem
+	order     *int
+	key       string
+}
+
+// IsGroup reports whether the item is a directory group
+func (n *NavItem) IsGroup() bool {
+	return n.URL == ""
+}
+
+// buildSidebar arranges pages into groups mirroring the directory tree, labelled and
+// ordered by the processor's categories. Items are sorted by sidebar.order (pages without
+// one come last), then by label.
+func buildSidebar(pages []*page, categories map[string]*processor.Category) []*NavItem {
+	root := &NavItem{}
+	groups := map[string]*NavItem{"": root}
+
+	var groupFor func(dir string) *NavItem
+	groupFor = func(dir string) *NavItem {
+		if group, ok := groups[dir]; ok {
+			return group
+		}
+		parent := groupFor(parentDir(dir))
+		// Numbered directories lost their prefix; the category carries its order
+		group := &NavItem{Label: template.GenerateTitle(path.Base(dir)), key: path.Base(dir)}
+		if category := categories[dir]; category != nil {
+			if category.Label != "" {
+				group.Label = category.Label
+			}
+			group.order = category.Order
+			group.Collapsed = category.Collapsed
+			if category.Badge != nil {
+				group.Badge = category.Badge.Text
+			}
+		}
+		parent.Children = append(parent.Children, group)
+		groups[dir] = group
+		return group
+	}
+
+	for _, p := range pages {
+		dir := parentDir(p.Rel)
+		group := groupFor(dir)
+		group.Children = append(group.Children, &NavItem{
+			Label: p.SidebarLabel,
+			URL:   p.Route,
+			order: p.Order,
+			key:   path.Base(p.Rel),
+		})
+	}
+
+	sortNav(root.Children)
+	return root.Children
+}
+
+// parentDir returns the slash-separated directory of a path, empty for the root
+func parentDir(p string) string {
+	dir := path.Dir(p)
+	if dir == "." || dir == "/" {
+		return ""
+	}
+	return dir
+}
+
+// sortNav sorts items recursively: explicit order first, then index pages, then by label
+func sortNav(items []*NavItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if (a.order != nil) != (b.order != nil) {
+			return a.order != nil
+		}
+		if a.order != nil && *a.order != *b.order {
+			return *a.order < *b.order
+		}
+		if isIndex(a.key) != isIndex(b.key) {
+			return isIndex(a.key)
+		}
+		return strings.ToLower(a.Label) < strings.ToLower(b.Label)
+	})
+	for _, item := range items {
+		sortNav(item.Children)
+	}
+}
+
+// isIndex checks if a file is a directory's index page
+func isIndex(name string) bool {
+	return strings.TrimSuffix(strings.ToLower(name), path.Ext(name)) == "index"
+}
+
+// markCurrent returns a copy of the sidebar with the item for route marked as current.
+// Collapsed groups holding the current page are opened.
+func markCurrent(items []*NavItem, route string) []*NavItem {
+	marked := make([]*NavItem, len(items))
+	for i, item := range items {
+		copied := *item
+		copied.Current = item.URL == route
+		copied.Children = markCurrent(item.Children, route)
+		if copied.Collapsed && containsCurrent(copied.Children) {
+			copied.Collapsed = false
+		}
+		marked[i] = &copied
+	}
+	return marked
+}
+
+// containsCurrent reports whether the current page is among items or their children
+func containsCurrent(items []*NavItem) bool {
+	for _, item := range items {
+		if item.Current || containsCurrent(item.Children) {
+			return true
+		}
+	}
+	return false
+}
+
+// flatten lists the sidebar's pages in reading order
+func flatten(items []*NavItem) []*NavItem {
+	var pages []*NavItem
+	for _, item := range items {
+		if item.IsGroup() {
+			pages = append(pages, flatten(item.Children)...)
+		} else {
+			pages = append(pages, item)
+		}
+	}
+	return pages
+}
@@ -0,0 +1,209 @@
+package runlog
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/heidene/flashdoc/internal/logging"
+)
+
+const (
+	keepLines    = 200 // Lines of the current step kept in memory for the failure excerpt
+	searchLines  = 40  // How far back from the end the excerpt looks for the first error line
+	excerptLines = 20  // Most lines shown in a failure excerpt
+	contextLines = 10  // Lines shown when no error line was found
+)
+
+var (
+	ansiCode  = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+	errorLine = regexp.MustCompile(`(?i)\berror\b|\bfailed\b|\bERR!|✘|✗|^\s*\[\w*Error\]`)
+
+	// Workspace page paths in Astro errors, with an optional :line or :line:column
+	docsPath = regexp.MustCompile(`(?:[^\s"'(]*/)?src/content/docs/([^\s"'():]+\.mdx?)(?::(\d+)(?::(\d+))?)?`)
+	// Content collection entries, as in "docs → guide/intro.md frontmatter does not match"
+	docsEntry = regexp.MustCompile(`(docs → )([^\s"'():]+\.mdx?)`)
+)
+
+// Locator maps a page path relative to the workspace docs directory and a line in it to
+// the source file and line it was processed from. Line 0 means no line is known.
+type Locator func(page string, line int) (source string, sourceLine int, ok bool)
+
+// Log captures the output of the commands flashdoc runs (dependency installs, Astro builds)
+// in a per-run log file, optionally streaming it as debug messages as well
+type Log struct {
+	path    string
+	file    *os.File
+	verbose bool
+	locate  Locator
+
+	mu      sync.Mutex
+	lines   []string // Recent lines of the current step
+	partial string   // Unterminated last line
+}
+
+// Open creates (or appends to) the log file at path
+func Open(path string, verbose bool) (*Log, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+	return &Log{path: path, file: file, verbose: verbose}, nil
+}
+
+// Path returns the location of the log file
+func (l *Log) Path() string {
+	return l.path
+}
+
+// Verbose reports whether output is streamed
+func (l *Log) Verbose() bool {
+	return l != nil && l.verbose
+}
+
+// SetLocator sets how workspace page paths in failure excerpts map back to source files
+func (l *Log) SetLocator(locate Locator) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.locate = locate
+}
+
+// Step starts the output of a new command, which the next failure excerpt is taken from
+func (l *Log) Step(command string) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = nil
+	l.partial = ""
+	fmt.Fprintf(l.file, "\n==> %s\n", command)
+	if l.verbose {
+		logging.Debugf("running %s", command)
+	}
+}
+
+// Write logs command output, streaming complete lines in verbose mode. It is safe to use
+// as both stdout and stderr of a command.
+func (l *Log) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, err := l.file.Write(p); err != nil {
+		return 0, err
+	}
+	text := l.partial + string(p)
+	lines := strings.Split(text, "\n")
+	l.partial = lines[len(lines)-1]
+	for _, line := range lines[:len(lines)-1] {
+		line = strings.TrimRight(line, "\r")
+		l.lines = append(l.lines, line)
+		if l.verbose {
+			logging.Default().Print(logging.LevelDebug, line)
+		}
+	}
+	if len(l.lines) > keepLines {
+		l.lines = append(l.lines[:0], l.lines[len(l.lines)-keepLines:]...)
+	}
+	return len(p), nil
+}
+
+// Writer returns the log as a writer, or io.Discard for a nil log
+func (l *Log) Writer() io.Writer {
+	if l == nil {
+		return io.Discard
+	}
+	return l
+}
+
+// Excerpt returns the lines of the current step that explain a failure: the output from
+// the first error-looking line near the end, or else its last few lines. Workspace page
+// paths are replaced by the source files they came from.
+func (l *Log) Excerpt() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var lines []string
+	for _, line := range append(l.lines, l.partial) {
+		line = strings.TrimRight(ansiCode.ReplaceAllString(line, ""), " \t")
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	start := len(lines) - contextLines
+	for i := max(len(lines)-searchLines, 0); i < len(lines); i++ {
+		if errorLine.MatchString(lines[i]) {
+			start = i
+			break
+		}
+	}
+	start = max(start, 0)
+	lines = lines[start:min(start+excerptLines, len(lines))]
+
+	for i, line := range lines {
+		lines[i] = MapLocations(line, l.locate)
+	}
+	return lines
+}
+
+// Fail wraps err with the failure excerpt of the current step and the log file location
+func (l *Log) Fail(err error) error {
+	if l == nil {
+		return err
+	}
+	var b strings.Builder
+	for _, line := range l.Excerpt() {
+		b.WriteString("\n  " + line)
+	}
+	return fmt.Errorf("%w%s\nFull log: %s", err, b.String(), l.path)
+}
+
+// Close closes the log file
+func (l *Log) Close() error {
+	if l == nil {
+		return nil
+	}
+	return l.file.Close()
+}
+
+// MapLocations replaces workspace page paths (src/content/docs/guide/intro.md:12:3) in
+// a line of Astro output with the source file and line they were processed from. Paths
+// the locator doesn't know are kept.
+func MapLocations(line string, locate Locator) string {
+	if locate == nil {
+		return line
+	}
+
+	line = docsPath.ReplaceAllStringFunc(line, func(match string) string {
+		m := docsPath.FindStringSubmatch(match)
+		lineNo, _ := strconv.Atoi(m[2])
+		source, sourceLine, ok := locate(m[1], lineNo)
+		if !ok {
+			return match
+		}
+		if lineNo == 0 {
+			return source
+		}
+		if m[3] != "" {
+			return fmt.Sprintf("%s:%d:%s", source, sourceLine, m[3])
+		}
+		return fmt.Sprintf("%s:%d", source, sourceLine)
+	})
+
+	return docsEntry.ReplaceAllStringFunc(line, func(match string) string {
+		m := docsEntry.FindStringSubmatch(match)
+		if source, _, ok := locate(m[2], 0); ok {
+			return m[1] + source
+		}
+		return match
+	})
+}
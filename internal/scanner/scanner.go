@@ -3,10 +3,12 @@ package scanner
 import (
 	"fmt"
 	"io/fs"
-	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+
+	"github.com/heidene/flashdoc/internal/ignore"
+	"github.com/heidene/flashdoc/internal/logging"
 )
 
 // MarkdownFile represents a discovered markdown file
@@ -18,46 +20,56 @@ type MarkdownFile struct {
 // Scanner discovers markdown files in a directory
 type Scanner struct {
 	sourceDir string
+	ignore    *ignore.Matcher
 	files     []MarkdownFile
 }
 
-// New creates a new scanner for the given source directory
+// New creates a new scanner for the given source directory. Until SetIgnore is called
+// it skips the default ignored paths (dot-entries, node_modules, dist, build, vendor).
 func New(sourceDir string) *Scanner {
 	return &Scanner{
 		sourceDir: sourceDir,
+		ignore:    ignore.New(),
 		files:     make([]MarkdownFile, 0),
 	}
 }
 
+// SetIgnore sets the matcher deciding which source paths are not scanned
+func (s *Scanner) SetIgnore(m *ignore.Matcher) {
+	s.ignore = m
+}
+
 // Scan discovers all markdown files in the source directory
 func (s *Scanner) Scan() ([]MarkdownFile, error) {
-	err := filepath.WalkDir(s.sourceDir, func(path string, d fs.DirEntry, err error) error {
+	return s.ScanDir("")
+}
+
+// ScanDir discovers the markdown files below relDir, a directory relative to the source
+// directory. Paths of the returned files stay relative to the source directory.
+func (s *Scanner) ScanDir(relDir string) ([]MarkdownFile, error) {
+	root := filepath.Join(s.sourceDir, relDir)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			// Log warning but continue scanning
-			fmt.Fprintf(os.Stderr, "Warning: cannot access %s: %v\n", path, err)
+			logging.Warnf("cannot access %s: %v", path, err)
 			return nil
 		}
 
-		// Skip hidden files and directories
-		if strings.HasPrefix(d.Name(), ".") {
+		relPath, err := filepath.Rel(s.sourceDir, path)
+		if err != nil {
+			return err
+		}
+
+		// Skip ignored files and directories
+		if relPath != "." && s.ignore.Ignored(relPath, d.IsDir()) {
 			if d.IsDir() {
 				return fs.SkipDir
 			}
 			return nil
 		}
 
-		// Skip common exclude patterns
-		if d.IsDir() && s.shouldSkipDir(d.Name()) {
-			return fs.SkipDir
-		}
-
 		// Check if file is a markdown file
 		if !d.IsDir() && s.isMarkdownFile(d.Name()) {
-			relPath, err := filepath.Rel(s.sourceDir, path)
-			if err != nil {
-				return err
-			}
-
 			s.files = append(s.files, MarkdownFile{
 				Path:     relPath,
 				FullPath: path,
@@ -91,27 +103,47 @@ func (s *Scanner) Count() int {
 
 // isMarkdownFile checks if a filename has a markdown extension
 func (s *Scanner) isMarkdownFile(filename string) bool {
+	return IsMarkdownFile(filename)
+}
+
+// IsMarkdownFile checks if a filename has a markdown or MDX extension
+func IsMarkdownFile(filename string) bool {
 	ext := strings.ToLower(filepath.Ext(filename))
-	return ext == ".md" || ext == ".markdown" || ext == ".mdown" || ext == ".mkd"
-}
-
-// shouldSkipDir checks if a directory should be skipped
-func (s *Scanner) shouldSkipDir(dirname string) bool {
-	skipDirs := []string{
-		"node_modules",
-		"dist",
-		"build",
-		".obsidian",
-		".vscode",
-		".idea",
-		"vendor",
-	}
+	return ext == ".md" || ext == ".mdx" || ext == ".markdown" || ext == ".mdown" || ext == ".mkd"
+}
 
-	for _, skip := range skipDirs {
-		if dirname == skip {
-			return true
-		}
-	}
+// imageExtensions are the image formats Astro's image pipeline can process from content files
+var imageExtensions = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true,
+	".webp": true, ".avif": true, ".svg": true, ".tiff": true,
+}
+
+// assetExtensions are other static files docs commonly link to
+var assetExtensions = map[string]bool{
+	".pdf": true, ".zip": true, ".tar": true, ".gz": true, ".tgz": true,
+	".ico": true, ".bmp": true, ".mp4": true, ".webm": true, ".mp3": true,
+	".csv": true, ".json": true, ".yaml": true, ".yml": true, ".xml": true,
+	".drawio": true, ".excalidraw": true,
+}
 
-	return false
+// componentExtensions are the source files MDX pages import as components
+var componentExtensions = map[string]bool{
+	".astro": true, ".jsx": true, ".tsx": true, ".js": true, ".ts": true,
+	".vue": true, ".svelte": true, ".css": true,
+}
+
+// IsComponentFile checks if a filename is a component or script an MDX page may import
+func IsComponentFile(filename string) bool {
+	return componentExtensions[strings.ToLower(filepath.Ext(filename))]
+}
+
+// IsImageFile checks if a filename has an image extension Astro can optimise
+func IsImageFile(filename string) bool {
+	return imageExtensions[strings.ToLower(filepath.Ext(filename))]
+}
+
+// IsAssetFile checks if a filename is a static asset (image or downloadable file) docs may reference
+func IsAssetFile(filename string) bool {
+	ext := strings.ToLower(filepath.Ext(filename))
+	return imageExtensions[ext] || assetExtensions[ext]
 }
@@ -0,0 +1,473 @@
+package schema
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Problem is a frontmatter value the Starlight docs schema rejects
+type Problem struct {
+	Key string // Path of the value, e.g. sidebar.badge.variant or head[0].tag
+	Msg string // What is wrong with it
+	Fix string // How to fix it
+
+	// Repaired is a value the schema accepts that keeps the author's intent, e.g. 42 -> "42"
+	// for a title. It is nil when the only repair is removing the key.
+	Repaired interface{}
+}
+
+func (p Problem) String() string {
+	if p.Fix == "" {
+		return fmt.Sprintf("%s: %s", p.Key, p.Msg)
+	}
+	return fmt.Sprintf("%s: %s (%s)", p.Key, p.Msg, p.Fix)
+}
+
+// Values accepted by enum fields of the docs schema
+var (
+	templates     = []string{"doc", "splash"}
+	headTags      = []string{"title", "base", "link", "style", "meta", "script", "noscript", "template"}
+	badgeVariants = []string{"note", "danger", "success", "caution", "tip", "default"}
+	heroVariants  = []string{"primary", "secondary", "minimal"}
+)
+
+// Validate checks decoded frontmatter against the fields of Starlight's docsSchema(), which
+// the site's src/content.config.ts uses. Keys the schema doesn't know are ignored, as
+// Starlight ignores them too.
+func Validate(values map[string]interface{}) []Problem {
+	v := &validator{}
+
+	if _, ok := values["title"]; !ok {
+		v.fail("title", "is required", "add a title", nil)
+	}
+
+	for _, key := range sortedKeys(values) {
+		value := values[key]
+
+		switch key {
+		case "title", "description", "slug":
+			v.string(key, value)
+
+		case "editUrl":
+			if _, ok := value.(bool); !ok && v.string(key, value) {
+				if u, err := url.Parse(value.(string)); err != nil || u.Scheme == "" || u.Host == "" {
+					v.fail(key, fmt.Sprintf("must be a URL or a boolean, got %s", describe(value)), "use a full URL like https://github.com/you/repo/edit/main/docs/page.md, or false", nil)
+				}
+			}
+
+		case "head":
+			v.head(key, value)
+
+		case "tableOfContents":
+			v.tableOfContents(key, value)
+
+		case "template":
+			v.enum(key, value, templates)
+
+		case "hero":
+			v.hero(key, value)
+
+		case "lastUpdated":
+			switch value.(type) {
+			case bool, time.Time:
+			default:
+				v.fail(key, fmt.Sprintf("must be a date or a boolean, got %s", describe(value)), "write a date like 2024-01-05 without quotes, or true/false", nil)
+			}
+
+		case "prev", "next":
+			v.pagination(key, value)
+
+		case "pagefind", "draft":
+			v.boolean(key, value)
+
+		case "sidebar":
+			v.sidebar(key, value)
+
+		case "banner":
+			if banner, ok := v.mapping(key, value, "write it as banner: { content: Your message }"); ok {
+				if content, ok := banner["content"]; ok {
+					v.string(key+".content", content)
+				} else {
+					v.fail(key+".content", "is required", "add the banner text as content", nil)
+				}
+			}
+		}
+	}
+
+	return v.problems
+}
+
+// validator collects problems while checking values
+type validator struct {
+	problems []Problem
+}
+
+func (v *validator) fail(key, msg, fix string, repaired interface{}) {
+	v.problems = append(v.problems, Problem{Key: key, Msg: msg, Fix: fix, Repaired: repaired})
+}
+
+// string checks for a string. Numbers, booleans and dates are repaired into their text.
+func (v *validator) string(key string, value interface{}) bool {
+	switch value := value.(type) {
+	case string:
+		return true
+	case int, int64, float64, bool:
+		text := fmt.Sprint(value)
+		v.fail(key, fmt.Sprintf("must be a string, got %s", describe(value)), fmt.Sprintf("quote it: %s: %q", lastKey(key), text), text)
+	case time.Time:
+		text := formatDate(value)
+		v.fail(key, fmt.Sprintf("must be a string, got %s", describe(value)), fmt.Sprintf("quote it: %s: %q", lastKey(key), text), text)
+	default:
+		v.fail(key, fmt.Sprintf("must be a string, got %s", describe(value)), "", nil)
+	}
+	return false
+}
+
+// boolean checks for a boolean. Quoted "true" and "false" are repaired.
+func (v *validator) boolean(key string, value interface{}) bool {
+	if _, ok := value.(bool); ok {
+		return true
+	}
+	if s, ok := value.(string); ok {
+		if b, err := strconv.ParseBool(s); err == nil {
+			v.fail(key, fmt.Sprintf("must be a boolean, got %s", describe(value)), fmt.Sprintf("remove the quotes: %s: %v", lastKey(key), b), b)
+			return false
+		}
+	}
+	v.fail(key, fmt.Sprintf("must be a boolean, got %s", describe(value)), "use true or false", nil)
+	return false
+}
+
+// number checks for a number. Quoted numbers are repaired.
+func (v *validator) number(key string, value interface{}) bool {
+	switch value.(type) {
+	case int, int64, float64:
+		return true
+	}
+	if s, ok := value.(string); ok {
+		if n, err := strconv.Atoi(strings.TrimSpace(s)); err == nil {
+			v.fail(key, fmt.Sprintf("must be a number, got %s", describe(value)), fmt.Sprintf("remove the quotes: %s: %d", lastKey(key), n), n)
+			return false
+		}
+	}
+	v.fail(key, fmt.Sprintf("must be a number, got %s", describe(value)), "", nil)
+	return false
+}
+
+// enum checks for one of a fixed set of strings. A value differing only in case is repaired.
+func (v *validator) enum(key string, value interface{}, allowed []string) bool {
+	s, ok := value.(string)
+	if ok {
+		for _, a := range allowed {
+			if s == a {
+				return true
+			}
+		}
+		for _, a := range allowed {
+			if strings.EqualFold(s, a) {
+				v.fail(key, fmt.Sprintf("must be one of %s, got %q", strings.Join(allowed, ", "), s), fmt.Sprintf("did you mean %q?", a), a)
+				return false
+			}
+		}
+	}
+	v.fail(key, fmt.Sprintf("must be one of %s, got %s", strings.Join(allowed, ", "), describe(value)), "", nil)
+	return false
+}
+
+// mapping checks for a mapping and returns it
+func (v *validator) mapping(key string, value interface{}, fix string) (map[string]interface{}, bool) {
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		v.fail(key, fmt.Sprintf("must be a mapping, got %s", describe(value)), fix, nil)
+	}
+	return m, ok
+}
+
+// attrs checks an HTML attribute record: attribute names to strings, numbers or booleans
+func (v *validator) attrs(key string, value interface{}) {
+	attrs, ok := v.mapping(key, value, "write attributes as name: value pairs")
+	if !ok {
+		return
+	}
+	for _, name := range sortedKeys(attrs) {
+		switch attrs[name].(type) {
+		case string, int, int64, float64, bool, nil:
+		default:
+			v.fail(key+"."+name, fmt.Sprintf("must be a string, number or boolean, got %s", describe(attrs[name])), "", nil)
+		}
+	}
+}
+
+// head checks the extra <head> tags of a page
+func (v *validator) head(key string, value interface{}) {
+	tags, ok := value.([]interface{})
+	if !ok {
+		v.fail(key, fmt.Sprintf("must be a list of tags, got %s", describe(value)), "write each tag as - tag: meta", nil)
+		return
+	}
+	for i, item := range tags {
+		itemKey := fmt.Sprintf("%s[%d]", key, i)
+		tag, ok := v.mapping(itemKey, item, "write each tag as - tag: meta")
+		if !ok {
+			continue
+		}
+		if name, ok := tag["tag"]; ok {
+			v.enum(itemKey+".tag", name, headTags)
+		} else {
+			v.fail(itemKey+".tag", "is required", "name the element, e.g. tag: meta", nil)
+		}
+		if attrs, ok := tag["attrs"]; ok {
+			v.attrs(itemKey+".attrs", attrs)
+		}
+		if content, ok := tag["content"]; ok {
+			v.string(itemKey+".content", content)
+		}
+	}
+}
+
+// tableOfContents checks for a boolean or heading levels from 1 to 6
+func (v *validator) tableOfContents(key string, value interface{}) {
+	if _, ok := value.(bool); ok {
+		return
+	}
+	toc, ok := v.mapping(key, value, "use false, or { minHeadingLevel: 2, maxHeadingLevel: 3 }")
+	if !ok {
+		return
+	}
+
+	levels := make(map[string]int)
+	for _, name := range []string{"minHeadingLevel", "maxHeadingLevel"} {
+		level, ok := toc[name]
+		if !ok {
+			continue
+		}
+		n, isInt := level.(int)
+		if !isInt || n < 1 || n > 6 {
+			v.fail(key+"."+name, fmt.Sprintf("must be a whole number from 1 to 6, got %s", describe(level)), "", nil)
+			continue
+		}
+		levels[name] = n
+	}
+	if minLevel, ok := levels["minHeadingLevel"]; ok {
+		if maxLevel, ok := levels["maxHeadingLevel"]; ok && minLevel > maxLevel {
+			v.fail(key, "minHeadingLevel must not be greater than maxHeadingLevel", "", nil)
+		}
+	}
+}
+
+// hero checks the hero section of a splash page
+func (v *validator) hero(key string, value interface{}) {
+	hero, ok := v.mapping(key, value, "")
+	if !ok {
+		return
+	}
+
+	for _, name := range []string{"title", "tagline"} {
+		if s, ok := hero[name]; ok {
+			v.string(key+"."+name, s)
+		}
+	}
+
+	if image, ok := hero["image"]; ok {
+		img, ok := v.mapping(key+".image", image, "use { file: ../../assets/hero.png }")
+		if ok {
+			_, file := img["file"]
+			_, dark := img["dark"]
+			_, light := img["light"]
+			_, html := img["html"]
+			if !file && !(dark && light) && !html {
+				v.fail(key+".image", "must have file, dark and light, or html", "use { file: ../../assets/hero.png }", nil)
+			}
+		}
+	}
+
+	actions, ok := hero["actions"]
+	if !ok {
+		return
+	}
+	list, ok := actions.([]interface{})
+	if !ok {
+		v.fail(key+".actions", fmt.Sprintf("must be a list, got %s", describe(actions)), "write each action as - text: Get started", nil)
+		return
+	}
+	for i, item := range list {
+		itemKey := fmt.Sprintf("%s.actions[%d]", key, i)
+		action, ok := v.mapping(itemKey, item, "write each action as - text: Get started")
+		if !ok {
+			continue
+		}
+		for _, name := range []string{"text", "link"} {
+			if s, ok := action[name]; ok {
+				v.string(itemKey+"."+name, s)
+			} else {
+				v.fail(itemKey+"."+name, "is required", "", nil)
+			}
+		}
+		if variant, ok := action["variant"]; ok {
+			v.enum(itemKey+".variant", variant, heroVariants)
+		}
+		if icon, ok := action["icon"]; ok {
+			v.string(itemKey+".icon", icon)
+		}
+		if attrs, ok := action["attrs"]; ok {
+			v.attrs(itemKey+".attrs", attrs)
+		}
+	}
+}
+
+// pagination checks prev/next: a boolean, a label, or a link and label
+func (v *validator) pagination(key string, value interface{}) {
+	switch value.(type) {
+	case bool, string:
+		return
+	}
+	link, ok := v.mapping(key, value, "use false, a label, or { link: /page/, label: Page }")
+	if !ok {
+		return
+	}
+	for _, name := range sortedKeys(link) {
+		switch name {
+		case "link", "label":
+			v.string(key+"."+name, link[name])
+		default:
+			v.fail(key+"."+name, "is not allowed", "only link and label can be set", nil)
+		}
+	}
+}
+
+// sidebar checks the sidebar settings of a page
+func (v *validator) sidebar(key string, value interface{}) {
+	sidebar, ok := value.(map[string]interface{})
+	if !ok {
+		fix := "write sidebar settings as a mapping, e.g. sidebar: { label: Intro, order: 1 }"
+		var repaired interface{}
+		if s, isString := value.(string); isString {
+			fix = fmt.Sprintf("to set the sidebar label, write sidebar: { label: %q }", s)
+			repaired = map[string]interface{}{"label": s}
+		}
+		v.fail(key, fmt.Sprintf("must be a mapping, got %s", describe(value)), fix, repaired)
+		return
+	}
+
+	for _, name := range sortedKeys(sidebar) {
+		field := key + "." + name
+		switch name {
+		case "order":
+			v.number(field, sidebar[name])
+		case "label":
+			v.string(field, sidebar[name])
+		case "hidden":
+			v.boolean(field, sidebar[name])
+		case "attrs":
+			v.attrs(field, sidebar[name])
+		case "badge":
+			v.badge(field, sidebar[name])
+		}
+	}
+}
+
+// badge checks a sidebar badge: its text, or text with a variant and class
+func (v *validator) badge(key string, value interface{}) {
+	if _, ok := value.(string); ok {
+		return
+	}
+	badge, ok := v.mapping(key, value, "use the badge text, or { text: New, variant: tip }")
+	if !ok {
+		return
+	}
+	if text, ok := badge["text"]; ok {
+		v.string(key+".text", text)
+	} else {
+		v.fail(key+".text", "is required", "", nil)
+	}
+	if variant, ok := badge["variant"]; ok {
+		v.enum(key+".variant", variant, badgeVariants)
+	}
+	if class, ok := badge["class"]; ok {
+		v.string(key+".class", class)
+	}
+}
+
+// Repair applies problems to values: repairable values are replaced and the others removed.
+// Problems inside a list remove the whole list.
+func Repair(values map[string]interface{}, problems []Problem) {
+	for _, p := range problems {
+		path := p.Key
+		repaired := p.Repaired
+		if i := strings.IndexByte(path, '['); i >= 0 {
+			path, repaired = path[:i], nil
+		}
+
+		parts := strings.Split(path, ".")
+		m := values
+		for _, part := range parts[:len(parts)-1] {
+			next, ok := m[part].(map[string]interface{})
+			if !ok {
+				m = nil
+				break
+			}
+			m = next
+		}
+		if m == nil {
+			continue
+		}
+
+		last := parts[len(parts)-1]
+		if repaired != nil {
+			m[last] = repaired
+		} else {
+			delete(m, last)
+		}
+	}
+}
+
+// describe names a value's type for error messages
+func describe(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "nothing"
+	case string:
+		return fmt.Sprintf("string %q", v)
+	case bool:
+		return fmt.Sprintf("boolean %v", v)
+	case int, int64, uint64, float64:
+		return fmt.Sprintf("number %v", v)
+	case time.Time:
+		return "date " + formatDate(v)
+	case []interface{}:
+		return "a list"
+	case map[string]interface{}:
+		return "a mapping"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// formatDate writes a date as YAML would, without the time when it is midnight UTC
+func formatDate(t time.Time) string {
+	if t.Equal(t.Truncate(24*time.Hour)) && t.Location() == time.UTC {
+		return t.Format("2006-01-02")
+	}
+	return t.Format(time.RFC3339)
+}
+
+// lastKey returns the last segment of a key path
+func lastKey(key string) string {
+	if i := strings.LastIndexByte(key, '.'); i >= 0 {
+		return key[i+1:]
+	}
+	return key
+}
+
+// sortedKeys returns the keys of a mapping in sorted order, for stable reports
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,21 @@
+//go:build windows
+
+package server
+
+import (
+	"os/exec"
+	"strconv"
+)
+
+// setProcessGroup is a no-op on Windows; taskkill /T handles the process tree
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// terminate asks the process tree to exit
+func terminate(cmd *exec.Cmd) error {
+	return exec.Command("taskkill", "/T", "/PID", strconv.Itoa(cmd.Process.Pid)).Run()
+}
+
+// kill forcefully ends the process tree
+func kill(cmd *exec.Cmd) error {
+	return exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).Run()
+}
@@ -4,14 +4,30 @@ import (
 	"bufio"
 	"fmt"
 	"io"
-	"os"
 	"os/exec"
 	"regexp"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/heidene/flashdoc/internal/logging"
 	"github.com/heidene/flashdoc/internal/pkgmanager"
 )
 
+// stderrTailLines is how many stderr lines are kept to explain a crash
+const stderrTailLines = 20
+
+var (
+	// ansiPattern matches terminal color escape sequences in Astro's output
+	ansiPattern = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+
+	// localURLPattern matches Astro's "Local http://localhost:4321/" ready line
+	localURLPattern = regexp.MustCompile(`Local\s+(https?://(?:localhost|127\.0\.0\.1|\[::1\]):(\d+)/?)`)
+
+	// portInUsePattern matches Astro's notice that the requested port is taken
+	portInUsePattern = regexp.MustCompile(`[Pp]ort (\d+) is in use`)
+)
+
 // Server manages the Astro dev server
 type Server struct {
 	workspacePath  string
@@ -20,6 +36,11 @@ type Server struct {
 	cmd            *exec.Cmd
 	serverURL      string
 	ready          chan bool
+	done           chan struct{}
+	exitErr        error
+	stopping       bool
+	stderrTail     []string
+	mu             sync.Mutex
 }
 
 // New creates a new server manager
@@ -29,28 +50,19 @@ func New(workspacePath string, pm pkgmanager.PackageManager, port int) *Server {
 		packageManager: pm,
 		port:           port,
 		ready:          make(chan bool, 1),
+		done:           make(chan struct{}),
 	}
 }
 
 // Start starts the Astro dev server
 func (s *Server) Start() error {
-	fmt.Println("Dev server starting...")
-
-	// Build command based on package manager
-	var cmdArgs []string
-	switch s.packageManager {
-	case pkgmanager.Pnpm:
-		cmdArgs = []string{"pnpm", "run", "dev", "--", "--port", fmt.Sprintf("%d", s.port)}
-	case pkgmanager.Bun:
-		cmdArgs = []string{"bun", "run", "dev", "--port", fmt.Sprintf("%d", s.port)}
-	case pkgmanager.Npm:
-		cmdArgs = []string{"npm", "run", "dev", "--", "--port", fmt.Sprintf("%d", s.port)}
-	default:
-		cmdArgs = []string{"npm", "run", "dev", "--", "--port", fmt.Sprintf("%d", s.port)}
-	}
+	logging.Infof("Dev server starting...")
+
+	s.cmd = s.packageManager.DevCommand(s.port).Cmd(s.workspacePath)
+	s.cmd.Env = append(s.cmd.Env, "NODE_ENV=development")
 
-	s.cmd = exec.Command(cmdArgs[0], cmdArgs[1:]...)
-	s.cmd.Dir = s.workspacePath
+	// Run in its own process group so the package manager's children are stopped too
+	setProcessGroup(s.cmd)
 
 	// Set up pipes for output streaming
 	stdout, err := s.cmd.StdoutPipe()
@@ -69,8 +81,28 @@ func (s *Server) Start() error {
 	}
 
 	// Stream output and detect when server is ready
-	go s.streamOutput(stdout, false)
-	go s.streamOutput(stderr, true)
+	var streams sync.WaitGroup
+	streams.Add(2)
+	go func() {
+		defer streams.Done()
+		s.streamOutput(stdout, false)
+	}()
+	go func() {
+		defer streams.Done()
+		s.streamOutput(stderr, true)
+	}()
+
+	// Detect the process exiting, expectedly or not
+	go func() {
+		// Drain output before Wait closes the pipes
+		streams.Wait()
+		err := s.cmd.Wait()
+
+		s.mu.Lock()
+		s.exitErr = err
+		s.mu.Unlock()
+		close(s.done)
+	}()
 
 	return nil
 }
@@ -78,22 +110,27 @@ func (s *Server) Start() error {
 // streamOutput streams server output and detects readiness
 func (s *Server) streamOutput(reader io.Reader, isStderr bool) {
 	scanner := bufio.NewScanner(reader)
-	urlPattern := regexp.MustCompile(`Local\s+http://localhost:(\d+)`)
 
 	for scanner.Scan() {
 		line := scanner.Text()
 
-		// Print to appropriate stream
+		// Pass Astro's output through, unless --quiet
 		if isStderr {
-			fmt.Fprintln(os.Stderr, line)
+			logging.Default().Print(logging.LevelWarn, line)
+			s.rememberStderr(line)
 		} else {
-			fmt.Println(line)
+			logging.Default().Print(logging.LevelInfo, line)
+		}
+
+		if port, ok := ParsePortInUse(line); ok {
+			logging.Infof("Port %d in use, Astro is trying another one...", port)
 		}
 
 		// Detect server ready
-		if matches := urlPattern.FindStringSubmatch(line); matches != nil {
-			port := matches[1]
-			s.serverURL = fmt.Sprintf("http://localhost:%s", port)
+		if url, ok := ParseLocalURL(line); ok {
+			s.mu.Lock()
+			s.serverURL = url
+			s.mu.Unlock()
 
 			// Signal that server is ready
 			select {
@@ -101,23 +138,128 @@ func (s *Server) streamOutput(reader io.Reader, isStderr bool) {
 			default:
 			}
 
-			fmt.Printf("\nServer ready at %s\n", s.serverURL)
+			logging.Resultf("\n🚀 Server ready at %s", url)
 		}
 	}
 }
 
-// WaitReady waits for the server to be ready or times out
+// rememberStderr keeps the last few stderr lines to explain crashes
+func (s *Server) rememberStderr(line string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stderrTail = append(s.stderrTail, line)
+	if len(s.stderrTail) > stderrTailLines {
+		s.stderrTail = s.stderrTail[len(s.stderrTail)-stderrTailLines:]
+	}
+}
+
+// ParseLocalURL extracts the local server URL from a line of Astro dev output
+func ParseLocalURL(line string) (string, bool) {
+	matches := localURLPattern.FindStringSubmatch(ansiPattern.ReplaceAllString(line, ""))
+	if matches == nil {
+		return "", false
+	}
+	return matches[1], true
+}
+
+// ParsePortInUse extracts the requested port from Astro's "port is in use" notice
+func ParsePortInUse(line string) (int, bool) {
+	matches := portInUsePattern.FindStringSubmatch(ansiPattern.ReplaceAllString(line, ""))
+	if matches == nil {
+		return 0, false
+	}
+
+	var port int
+	if _, err := fmt.Sscanf(matches[1], "%d", &port); err != nil {
+		return 0, false
+	}
+	return port, true
+}
+
+// WaitReady waits for the server to be ready or times out.
+// It fails if the dev server exits before becoming ready.
 func (s *Server) WaitReady(timeout time.Duration) (string, error) {
 	select {
 	case <-s.ready:
-		return s.serverURL, nil
+		return s.GetURL(), nil
+	case <-s.done:
+		return "", fmt.Errorf("failed to start dev server: %s", s.exitReason())
 	case <-time.After(timeout):
 		// Fallback: assume server is ready
-		if s.serverURL == "" {
-			s.serverURL = fmt.Sprintf("http://localhost:%d", s.port)
+		logging.Warnf("server detection timed out, assuming ready")
+		return s.GetURL(), nil
+	}
+}
+
+// Done returns a channel that is closed when the dev server process exits
+func (s *Server) Done() <-chan struct{} {
+	return s.done
+}
+
+// Crashed reports whether the process exited without Stop being called
+func (s *Server) Crashed() bool {
+	select {
+	case <-s.done:
+	default:
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return !s.stopping
+}
+
+// CrashReason describes why the dev server exited, including its last stderr lines
+func (s *Server) CrashReason() string {
+	return s.exitReason()
+}
+
+// exitReason formats the exit status and recent stderr output
+func (s *Server) exitReason() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reason := "process exited"
+	if s.exitErr != nil {
+		reason = s.exitErr.Error()
+	}
+	if len(s.stderrTail) > 0 {
+		reason += "\n" + strings.Join(s.stderrTail, "\n")
+	}
+	return reason
+}
+
+// Stop terminates the dev server, escalating to a kill if it doesn't exit within 5 seconds
+func (s *Server) Stop() error {
+	if s.cmd == nil || s.cmd.Process == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	s.stopping = true
+	s.mu.Unlock()
+
+	select {
+	case <-s.done:
+		return nil
+	default:
+	}
+
+	logging.Infof("Stopping dev server...")
+	if err := terminate(s.cmd); err != nil {
+		return fmt.Errorf("failed to stop dev server: %w", err)
+	}
+
+	select {
+	case <-s.done:
+		return nil
+	case <-time.After(5 * time.Second):
+		if err := kill(s.cmd); err != nil {
+			return fmt.Errorf("failed to kill dev server: %w", err)
 		}
-		fmt.Println("Server detection timed out, assuming ready")
-		return s.serverURL, nil
+		<-s.done
+		return nil
 	}
 }
 
@@ -134,7 +276,11 @@ func (s *Server) Wait() error {
 	if s.cmd == nil {
 		return nil
 	}
-	return s.cmd.Wait()
+	<-s.done
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.exitErr
 }
 
 // IsRunning checks if the server is still running
@@ -143,13 +289,19 @@ func (s *Server) IsRunning() bool {
 		return false
 	}
 
-	// Try to signal with signal 0 to check if process exists
-	err := s.cmd.Process.Signal(os.Signal(nil))
-	return err == nil
+	select {
+	case <-s.done:
+		return false
+	default:
+		return true
+	}
 }
 
 // GetURL returns the server URL
 func (s *Server) GetURL() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	if s.serverURL == "" {
 		return fmt.Sprintf("http://localhost:%d", s.port)
 	}
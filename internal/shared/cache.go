@@ -0,0 +1,330 @@
+package shared
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// PackageManagerFile records the package manager that installed the shared project
+	PackageManagerFile = ".stardoc-pm"
+	// OwnerFile marks a run directory with the PID and host of the flashdoc process using it
+	OwnerFile = ".flashdoc-owner"
+)
+
+// RunInfo describes a run directory
+type RunInfo struct {
+	ID      string
+	Path    string
+	Size    int64
+	ModTime time.Time
+	InUse   bool // Owned by a running flashdoc process, never removed
+}
+
+// CacheInfo summarizes the shared install and run directories
+type CacheInfo struct {
+	Dir            string
+	SharedSize     int64
+	Installed      bool   // node_modules is present
+	TemplateHash   string // Hash of the installed template, empty if unknown
+	PackageManager string // Package manager that ran the install, empty if unknown
+	Runs           []RunInfo
+	LastUsed       time.Time // Most recent run, or the install when there are no runs
+}
+
+// RunsSize returns the total size of all run directories
+func (c *CacheInfo) RunsSize() int64 {
+	var total int64
+	for _, run := range c.Runs {
+		total += run.Size
+	}
+	return total
+}
+
+// Info collects sizes, install details and run directories
+func (m *Manager) Info() (*CacheInfo, error) {
+	info := &CacheInfo{Dir: m.GetStardocDir()}
+
+	size, err := dirSize(m.GetSharedDir())
+	if err != nil {
+		return nil, fmt.Errorf("failed to measure shared directory: %w", err)
+	}
+	info.SharedSize = size
+
+	if _, err := os.Stat(filepath.Join(m.GetSharedDir(), "node_modules")); err == nil {
+		info.Installed = true
+	}
+	if data, err := os.ReadFile(m.GetVersionFilePath()); err == nil {
+		info.TemplateHash = strings.TrimSpace(string(data))
+		if stat, err := os.Stat(m.GetVersionFilePath()); err == nil {
+			info.LastUsed = stat.ModTime()
+		}
+	}
+	info.PackageManager = m.InstalledPackageManager()
+
+	info.Runs, err = m.Runs()
+	if err != nil {
+		return nil, err
+	}
+	for _, run := range info.Runs {
+		if run.ModTime.After(info.LastUsed) {
+			info.LastUsed = run.ModTime
+		}
+	}
+
+	return info, nil
+}
+
+// SavePackageManager records the package manager that installed the shared project
+func (m *Manager) SavePackageManager(name string) error {
+	if err := os.WriteFile(filepath.Join(m.GetSharedDir(), PackageManagerFile), []byte(name), 0644); err != nil {
+		return fmt.Errorf("failed to write package manager file: %w", err)
+	}
+	return nil
+}
+
+// InstalledPackageManager returns the package manager that installed the shared project,
+// or an empty string if it isn't known
+func (m *Manager) InstalledPackageManager() string {
+	data, err := os.ReadFile(filepath.Join(m.GetSharedDir(), PackageManagerFile))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// installFiles are the files a package manager leaves in the shared project, removed
+// before another package manager installs into it
+var installFiles = []string{
+	"node_modules",
+	PackageManagerFile,
+	"package-lock.json",
+	"pnpm-lock.yaml",
+	"bun.lock",
+	"bun.lockb",
+	"yarn.lock",
+	".yarn",
+	".pnp.cjs",
+	".pnp.loader.mjs",
+	"deno.lock",
+}
+
+// CleanInstall removes the dependencies and lockfiles of the shared project, so the next
+// install starts from scratch
+func (m *Manager) CleanInstall() error {
+	for _, name := range installFiles {
+		if err := os.RemoveAll(filepath.Join(m.GetSharedDir(), name)); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Runs lists the run directories, oldest first
+func (m *Manager) Runs() ([]RunInfo, error) {
+	entries, err := os.ReadDir(m.GetRunsDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read runs directory: %w", err)
+	}
+
+	var runs []RunInfo
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		stat, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		runPath := filepath.Join(m.GetRunsDir(), entry.Name())
+		size, err := dirSize(runPath)
+		if err != nil {
+			continue
+		}
+
+		runs = append(runs, RunInfo{
+			ID:      entry.Name(),
+			Path:    runPath,
+			Size:    size,
+			ModTime: stat.ModTime(),
+			InUse:   RunInUse(runPath),
+		})
+	}
+
+	sort.Slice(runs, func(i, j int) bool { return runs[i].ModTime.Before(runs[j].ModTime) })
+	return runs, nil
+}
+
+// MarkRunInUse records the current process as the owner of a run directory so that
+// prune, clear and old-run cleanup from other invocations leave it alone
+func MarkRunInUse(runDir string) error {
+	hostname, _ := os.Hostname()
+	owner := fmt.Sprintf("%d %s\n", os.Getpid(), hostname)
+	if err := os.WriteFile(filepath.Join(runDir, OwnerFile), []byte(owner), 0644); err != nil {
+		return fmt.Errorf("failed to mark run directory: %w", err)
+	}
+	return nil
+}
+
+// RunInUse reports whether the process that owns a run directory is still running.
+// Runs owned by another host can't be checked and count as in use.
+func RunInUse(runDir string) bool {
+	data, err := os.ReadFile(filepath.Join(runDir, OwnerFile))
+	if err != nil {
+		return false
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return false
+	}
+	pid, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return false
+	}
+
+	if len(fields) > 1 {
+		if hostname, _ := os.Hostname(); hostname != fields[1] {
+			return true
+		}
+	}
+	return processAlive(pid)
+}
+
+// PruneRuns removes run directories older than maxAge that are not in use
+func (m *Manager) PruneRuns(maxAge time.Duration) ([]RunInfo, error) {
+	runs, err := m.Runs()
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []RunInfo
+	now := time.Now()
+	for _, run := range runs {
+		if run.InUse || now.Sub(run.ModTime) <= maxAge {
+			continue
+		}
+		if err := os.RemoveAll(run.Path); err != nil {
+			return removed, fmt.Errorf("failed to remove run %s: %w", run.ID, err)
+		}
+		removed = append(removed, run)
+	}
+
+	return removed, nil
+}
+
+// PruneLogs removes run logs last written more than maxAge ago
+func (m *Manager) PruneLogs(maxAge time.Duration) error {
+	entries, err := os.ReadDir(m.GetLogsDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read logs directory: %w", err)
+	}
+
+	now := time.Now()
+	for _, entry := range entries {
+		stat, err := entry.Info()
+		if err != nil || entry.IsDir() || now.Sub(stat.ModTime()) <= maxAge {
+			continue
+		}
+		if err := os.Remove(filepath.Join(m.GetLogsDir(), entry.Name())); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove log %s: %w", entry.Name(), err)
+		}
+	}
+	return nil
+}
+
+// ClearResult reports what Clear removed and what it kept
+type ClearResult struct {
+	Removed      []RunInfo
+	Kept         []RunInfo // Runs in use
+	SharedFreed  int64     // Bytes freed by removing the shared install, 0 if kept
+	SharedKeptBy string    // Why the shared install was kept, empty if removed
+}
+
+// Clear removes every idle run and the shared install. The shared install is kept while
+// any run is in use (runs link to its node_modules) or an install holds the lock.
+func (m *Manager) Clear() (*ClearResult, error) {
+	result := &ClearResult{}
+
+	// A negative age matches every idle run
+	removed, err := m.PruneRuns(-1)
+	result.Removed = removed
+	if err != nil {
+		return result, err
+	}
+
+	runs, err := m.Runs()
+	if err != nil {
+		return result, err
+	}
+	result.Kept = runs
+
+	switch {
+	case len(runs) > 0:
+		result.SharedKeptBy = fmt.Sprintf("%d run(s) still in use", len(runs))
+		return result, nil
+	case m.LockHeld():
+		result.SharedKeptBy = "an install is in progress"
+		return result, nil
+	}
+
+	size, err := dirSize(m.GetSharedDir())
+	if err != nil {
+		return result, fmt.Errorf("failed to measure shared directory: %w", err)
+	}
+	if err := os.RemoveAll(m.GetSharedDir()); err != nil {
+		return result, fmt.Errorf("failed to remove shared directory: %w", err)
+	}
+	result.SharedFreed = size
+
+	return result, nil
+}
+
+// dirSize returns the total size of the files below dir, without following symlinks
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// FormatSize formats a byte count for display, e.g. "12.3 MB"
+func FormatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
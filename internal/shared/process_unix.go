@@ -0,0 +1,15 @@
+//go:build !windows
+
+package shared
+
+import (
+	"errors"
+	"syscall"
+)
+
+// processAlive checks if a process with the given PID exists
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	// EPERM means the process exists but belongs to another user
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
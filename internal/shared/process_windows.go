@@ -0,0 +1,16 @@
+//go:build windows
+
+package shared
+
+import "os"
+
+// processAlive checks if a process with the given PID exists; FindProcess opens
+// a handle to the process on Windows and fails if it has exited
+func processAlive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	p.Release()
+	return true
+}
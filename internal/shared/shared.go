@@ -1,13 +1,16 @@
 package shared
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"time"
 
+	"github.com/gofrs/flock"
 	"github.com/google/uuid"
 )
 
@@ -18,15 +21,24 @@ const (
 	SharedDir = "shared"
 	// RunsDir is the subdirectory for individual run workspaces
 	RunsDir = "runs"
+	// LogsDir is the subdirectory for the install and build logs of each run
+	LogsDir = "logs"
 	// VersionFile stores the hash for cache invalidation
 	VersionFile = ".stardoc-version"
 	// LockFile prevents concurrent installs
 	LockFile = ".lock"
 )
 
+// lockRetryDelay is how often AcquireLock retries while another install holds the lock
+const lockRetryDelay = 250 * time.Millisecond
+
+// ErrLockTimeout is returned when another install holds the lock for longer than the wait timeout
+var ErrLockTimeout = errors.New("timed out waiting for another flashdoc install")
+
 // Manager handles shared project directory operations
 type Manager struct {
 	homeDir string
+	lock    *flock.Flock // Install lock while held by this process
 }
 
 // NewManager creates a new shared project manager
@@ -56,6 +68,17 @@ func (m *Manager) GetRunsDir() string {
 	return filepath.Join(m.GetStardocDir(), RunsDir)
 }
 
+// GetLogsDir returns the path to ~/.stardoc/logs/
+func (m *Manager) GetLogsDir() string {
+	return filepath.Join(m.GetStardocDir(), LogsDir)
+}
+
+// GetLogPath returns the path to the log of a specific run. Logs live outside the run
+// directory so they outlast its cleanup.
+func (m *Manager) GetLogPath(runID string) string {
+	return filepath.Join(m.GetLogsDir(), runID+".log")
+}
+
 // GetVersionFilePath returns the path to ~/.stardoc/shared/.stardoc-version
 func (m *Manager) GetVersionFilePath() string {
 	return filepath.Join(m.GetSharedDir(), VersionFile)
@@ -82,6 +105,7 @@ func (m *Manager) EnsureDirectories() error {
 		m.GetStardocDir(),
 		m.GetSharedDir(),
 		m.GetRunsDir(),
+		m.GetLogsDir(),
 	}
 
 	for _, dir := range dirs {
@@ -115,6 +139,17 @@ func (m *Manager) IsSharedProjectCurrent(expectedHash string) (bool, error) {
 	return currentHash == expectedHash, nil
 }
 
+// IsInstalledWith checks that the shared project is current and was installed by
+// packageManager. Installs from before the package manager was recorded match any.
+func (m *Manager) IsInstalledWith(expectedHash, packageManager string) (bool, error) {
+	current, err := m.IsSharedProjectCurrent(expectedHash)
+	if err != nil || !current {
+		return false, err
+	}
+	installed := m.InstalledPackageManager()
+	return installed == "" || installed == packageManager, nil
+}
+
 // SaveVersion saves the current version hash to disk
 func (m *Manager) SaveVersion(hash string) error {
 	versionFile := m.GetVersionFilePath()
@@ -130,60 +165,86 @@ func ComputeHash(content string) string {
 	return hex.EncodeToString(hash[:])
 }
 
-// CleanupOldRuns removes run directories older than the specified duration
+// CleanupOldRuns removes run directories and logs older than the specified duration.
+// Runs still in use by another flashdoc process are kept.
 func (m *Manager) CleanupOldRuns(maxAge time.Duration) error {
-	runsDir := m.GetRunsDir()
+	if _, err := m.PruneRuns(maxAge); err != nil {
+		return err
+	}
+	return m.PruneLogs(maxAge)
+}
 
-	entries, err := os.ReadDir(runsDir)
+// TryLock takes the install lock if no other process holds it.
+// The OS releases the lock when its holder exits, so a killed install never leaves it stuck.
+func (m *Manager) TryLock() (bool, error) {
+	lock := flock.New(m.GetLockFilePath())
+	locked, err := lock.TryLock()
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil
-		}
-		return fmt.Errorf("failed to read runs directory: %w", err)
+		return false, fmt.Errorf("failed to lock %s: %w", m.GetLockFilePath(), err)
 	}
+	if locked {
+		m.lock = lock
+	}
+	return locked, nil
+}
 
-	now := time.Now()
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
-		}
-
-		runPath := filepath.Join(runsDir, entry.Name())
-		info, err := entry.Info()
-		if err != nil {
-			continue
-		}
+// AcquireLock takes the install lock, waiting up to timeout for another install to finish
+func (m *Manager) AcquireLock(timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
 
-		age := now.Sub(info.ModTime())
-		if age > maxAge {
-			os.RemoveAll(runPath)
+	lock := flock.New(m.GetLockFilePath())
+	locked, err := lock.TryLockContext(ctx, lockRetryDelay)
+	if !locked {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return ErrLockTimeout
 		}
+		return fmt.Errorf("failed to lock %s: %w", m.GetLockFilePath(), err)
 	}
 
+	m.lock = lock
 	return nil
 }
 
-// AcquireLock creates a lock file to prevent concurrent installs
-func (m *Manager) AcquireLock() error {
-	lockFile := m.GetLockFilePath()
-
-	// Try to create lock file exclusively
-	f, err := os.OpenFile(lockFile, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+// ReleaseLock releases the install lock. The lock file is kept, since removing it would
+// let a waiting process lock a new file while another one still holds the old one.
+func (m *Manager) ReleaseLock() error {
+	if m.lock == nil {
+		return nil
+	}
+	err := m.lock.Unlock()
+	m.lock = nil
 	if err != nil {
-		if os.IsExist(err) {
-			return fmt.Errorf("another installation is in progress")
-		}
-		return fmt.Errorf("failed to create lock file: %w", err)
+		return fmt.Errorf("failed to release lock: %w", err)
 	}
-	f.Close()
-
 	return nil
 }
 
-// ReleaseLock removes the lock file
-func (m *Manager) ReleaseLock() error {
-	lockFile := m.GetLockFilePath()
-	if err := os.Remove(lockFile); err != nil && !os.IsNotExist(err) {
+// LockHeld reports whether another install currently holds the lock
+func (m *Manager) LockHeld() bool {
+	if m.lock != nil {
+		return true
+	}
+	if _, err := os.Stat(m.GetLockFilePath()); err != nil {
+		return false
+	}
+
+	lock := flock.New(m.GetLockFilePath())
+	locked, err := lock.TryLock()
+	if err != nil {
+		return false
+	}
+	if locked {
+		_ = lock.Unlock()
+		return false
+	}
+	return true
+}
+
+// BreakLock removes the lock file so a new install can proceed even while a hung
+// process still holds the lock
+func (m *Manager) BreakLock() error {
+	if err := os.Remove(m.GetLockFilePath()); err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("failed to remove lock file: %w", err)
 	}
 	return nil
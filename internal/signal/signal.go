@@ -1,12 +1,13 @@
 package signal
 
 import (
-	"fmt"
 	"os"
 	"os/signal"
 	"sync"
 	"syscall"
 	"time"
+
+	"github.com/heidene/flashdoc/internal/logging"
 )
 
 // Handler manages signal handling for graceful shutdown
@@ -49,15 +50,15 @@ func (h *Handler) handleSignals() {
 			if currentCount == 1 {
 				// First signal - graceful shutdown
 				firstSignalTime = time.Now()
-				fmt.Fprintln(os.Stderr, "\nShutting down gracefully...")
-				fmt.Fprintln(os.Stderr, "Press Ctrl+C again to force exit")
+				logging.Infof("\n🛑 Shutting down gracefully...")
+				logging.Infof("Press Ctrl+C again to force exit")
 
 				go h.performCleanup()
 
 			} else if currentCount == 2 {
 				// Second signal within timeout - force exit
 				if time.Since(firstSignalTime) <= 1*time.Second {
-					fmt.Fprintln(os.Stderr, "Force stopping...")
+					logging.Warnf("force stopping...")
 					os.Exit(1)
 				} else {
 					// Reset if too much time has passed
@@ -65,13 +66,13 @@ func (h *Handler) handleSignals() {
 					h.forceCount = 1
 					h.mu.Unlock()
 					firstSignalTime = time.Now()
-					fmt.Fprintln(os.Stderr, "\nShutting down gracefully...")
-					fmt.Fprintln(os.Stderr, "Press Ctrl+C again to force exit")
+					logging.Infof("\n🛑 Shutting down gracefully...")
+					logging.Infof("Press Ctrl+C again to force exit")
 					go h.performCleanup()
 				}
 			} else {
 				// Multiple signals - force exit immediately
-				fmt.Fprintln(os.Stderr, "Force stopping...")
+				logging.Warnf("force stopping...")
 				os.Exit(1)
 			}
 
@@ -93,13 +94,13 @@ func (h *Handler) performCleanup() {
 	select {
 	case err := <-cleanupDone:
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Cleanup error: %v\n", err)
+			logging.Errorf("cleanup: %v", err)
 			os.Exit(1)
 		}
 		os.Exit(0)
 
 	case <-time.After(10 * time.Second):
-		fmt.Fprintln(os.Stderr, "Cleanup timeout - some resources may not have been cleaned up properly")
+		logging.Warnf("cleanup timed out, some resources may not have been cleaned up properly")
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,170 @@
+package staticserver
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+)
+
+// LiveReloadPath is the server-sent events endpoint browsers listen on for reload events
+const LiveReloadPath = "/__flashdoc/livereload"
+
+// liveReloadScript is injected into every served HTML page when live reload is enabled
+const liveReloadScript = `<script>
+(function () {
+  var source = new EventSource("` + LiveReloadPath + `");
+  source.addEventListener("reload", function () { window.location.reload(); });
+})();
+</script>`
+
+// liveReload tracks connected browsers and notifies them when the site is rebuilt
+type liveReload struct {
+	mu      sync.Mutex
+	clients map[chan struct{}]struct{}
+	done    chan struct{}
+	closed  bool
+}
+
+func newLiveReload() *liveReload {
+	return &liveReload{
+		clients: make(map[chan struct{}]struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// ServeHTTP streams reload events to a single browser tab
+func (l *liveReload) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	ch := make(chan struct{}, 1)
+	if !l.subscribe(ch) {
+		http.Error(w, "server shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	defer l.unsubscribe(ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	for {
+		select {
+		case <-ch:
+			fmt.Fprint(w, "event: reload\ndata: reload\n\n")
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		case <-l.done:
+			return
+		}
+	}
+}
+
+// broadcast sends a reload event to every connected client
+func (l *liveReload) broadcast() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for ch := range l.clients {
+		select {
+		case ch <- struct{}{}:
+		default:
+			// A reload is already pending for this client
+		}
+	}
+}
+
+// close disconnects all clients
+func (l *liveReload) close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.closed {
+		l.closed = true
+		close(l.done)
+	}
+}
+
+func (l *liveReload) subscribe(ch chan struct{}) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return false
+	}
+	l.clients[ch] = struct{}{}
+	return true
+}
+
+func (l *liveReload) unsubscribe(ch chan struct{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.clients, ch)
+}
+
+// inject wraps a file server so HTML pages include the live reload script
+func (l *liveReload) inject(root http.FileSystem, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		name := path.Clean("/" + r.URL.Path)
+		if strings.HasSuffix(r.URL.Path, "/") {
+			name = path.Join(name, "index.html")
+		}
+		if !strings.HasSuffix(name, ".html") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		f, err := root.Open(name)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		defer f.Close()
+
+		info, err := f.Stat()
+		if err != nil || info.IsDir() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		content, err := io.ReadAll(f)
+		if err != nil {
+			http.Error(w, "failed to read page", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-cache")
+		_, _ = w.Write(injectScript(content))
+	})
+}
+
+// injectScript inserts the live reload script before </body>, or appends it
+func injectScript(page []byte) []byte {
+	idx := bytes.LastIndex(bytes.ToLower(page), []byte("</body>"))
+	if idx == -1 {
+		return append(page, []byte(liveReloadScript)...)
+	}
+
+	result := make([]byte, 0, len(page)+len(liveReloadScript))
+	result = append(result, page[:idx]...)
+	result = append(result, liveReloadScript...)
+	result = append(result, page[idx:]...)
+	return result
+}
@@ -3,11 +3,11 @@ package staticserver
 import (
 	"context"
 	"fmt"
-	"io"
 	"net"
 	"net/http"
-	"os"
 	"time"
+
+	"github.com/heidene/flashdoc/internal/logging"
 )
 
 // Server wraps Go's HTTP file server for serving static sites
@@ -15,19 +15,16 @@ type Server struct {
 	distPath string
 	port     int
 	server   *http.Server
-	output   io.Writer
+	log      *logging.Logger
+	reload   *liveReload
 }
 
 // NewServer creates a new static file server
-func NewServer(distPath string, port int, output io.Writer) *Server {
-	if output == nil {
-		output = os.Stdout
-	}
-
+func NewServer(distPath string, port int, log *logging.Logger) *Server {
 	return &Server{
 		distPath: distPath,
 		port:     port,
-		output:   output,
+		log:      logging.Or(log),
 	}
 }
 
@@ -43,7 +40,12 @@ func (s *Server) Start() error {
 
 	// Create mux and handle all routes
 	mux := http.NewServeMux()
-	mux.Handle("/", fs)
+	if s.reload != nil {
+		mux.Handle(LiveReloadPath, s.reload)
+		mux.Handle("/", s.reload.inject(http.Dir(s.distPath), fs))
+	} else {
+		mux.Handle("/", fs)
+	}
 
 	// Create HTTP server
 	s.server = &http.Server{
@@ -53,9 +55,9 @@ func (s *Server) Start() error {
 
 	// Start server in goroutine
 	go func() {
-		fmt.Fprintf(s.output, "🚀 Server started at http://localhost:%d\n", s.port)
+		s.log.Resultf("🚀 Server started at http://localhost:%d", s.port)
 		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			fmt.Fprintf(s.output, "Server error: %v\n", err)
+			s.log.Errorf("server error: %v", err)
 		}
 	}()
 
@@ -73,6 +75,11 @@ func (s *Server) Stop() error {
 		return nil
 	}
 
+	// Release long-lived live reload connections so shutdown doesn't wait on them
+	if s.reload != nil {
+		s.reload.close()
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -80,10 +87,25 @@ func (s *Server) Stop() error {
 		return fmt.Errorf("server shutdown failed: %w", err)
 	}
 
-	fmt.Fprintf(s.output, "🛑 Server stopped\n")
+	s.log.Infof("🛑 Server stopped")
 	return nil
 }
 
+// EnableLiveReload makes served HTML pages reload themselves when Reload is called.
+// It must be called before Start.
+func (s *Server) EnableLiveReload() {
+	if s.reload == nil {
+		s.reload = newLiveReload()
+	}
+}
+
+// Reload tells all connected browser tabs to reload the current page
+func (s *Server) Reload() {
+	if s.reload != nil {
+		s.reload.broadcast()
+	}
+}
+
 // WaitReady waits for the server to be ready to accept connections
 func (s *Server) WaitReady(timeout time.Duration) error {
 	deadline := time.Now().Add(timeout)
@@ -0,0 +1,315 @@
+package template
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// StarlightConfig models the options passed to the Starlight integration in
+// astro.config.mjs. Fields are written under the name in their js tag; fields tagged
+// omitempty are left out while zero, so Starlight's defaults apply. A new option only
+// needs a new field.
+type StarlightConfig struct {
+	Title           string            `js:"title"`
+	Description     string            `js:"description,omitempty"`
+	Logo            *Logo             `js:"logo,omitempty"`
+	Favicon         string            `js:"favicon,omitempty"` // Path in the public directory, e.g. /favicon.svg
+	Social          []SocialLink      `js:"social,omitempty"`
+	Sidebar         []SidebarItem     `js:"sidebar,omitempty"` // Empty autogenerates the sidebar from the pages
+	CustomCSS       []string          `js:"customCss,omitempty"`
+	EditLink        *EditLink         `js:"editLink,omitempty"`
+	DefaultLocale   string            `js:"defaultLocale,omitempty"`
+	Locales         map[string]Locale `js:"locales,omitempty"` // Locale directory (or "root") -> locale
+	Pagination      *bool             `js:"pagination,omitempty"`
+	TableOfContents *TableOfContents  `js:"tableOfContents,omitempty"`
+}
+
+// Logo is the site logo. Src, Light and Dark are import paths relative to the workspace.
+type Logo struct {
+	Src           string `js:"src,omitempty"`
+	Light         string `js:"light,omitempty"`
+	Dark          string `js:"dark,omitempty"`
+	Alt           string `js:"alt,omitempty"`
+	ReplacesTitle bool   `js:"replacesTitle,omitempty"`
+}
+
+// SocialLink is an icon link in the site header
+type SocialLink struct {
+	Icon  string `js:"icon"`
+	Label string `js:"label"`
+	Href  string `js:"href"`
+}
+
+// SidebarItem is a link, a group of items or a group autogenerated from a directory
+type SidebarItem struct {
+	Label        string        `js:"label,omitempty"`
+	Badge        *Badge        `js:"badge,omitempty"`
+	Link         string        `js:"link,omitempty"`
+	Slug         string        `js:"slug,omitempty"`
+	Collapsed    bool          `js:"collapsed,omitempty"`
+	Items        []SidebarItem `js:"items,omitempty"`
+	Autogenerate *Autogenerate `js:"autogenerate,omitempty"`
+}
+
+// PageFunc returns the sidebar label and link of a source page, or false if the page
+// wasn't published. Navigation read from other tools (MkDocs nav, SUMMARY.md) uses it to
+// point at the processed pages.
+type PageFunc func(page string) (label, link string, ok bool)
+
+// PageItem returns the sidebar link to a source page, labelled title unless it's empty
+func PageItem(page PageFunc, relPath, title string) (SidebarItem, bool) {
+	label, link, ok := page(relPath)
+	if !ok {
+		return SidebarItem{}, false
+	}
+	if title != "" {
+		label = title
+	}
+	return SidebarItem{Label: label, Link: link}, true
+}
+
+// Badge is a short highlight shown next to a sidebar label
+type Badge struct {
+	Text    string `js:"text"`
+	Variant string `js:"variant,omitempty"` // One of BadgeVariants, empty for the default style
+}
+
+// BadgeVariants are the badge styles Starlight supports
+var BadgeVariants = []string{"default", "note", "tip", "success", "caution", "danger"}
+
+// ValidBadgeVariant checks if variant is one of BadgeVariants or empty
+func ValidBadgeVariant(variant string) bool {
+	if variant == "" {
+		return true
+	}
+	for _, known := range BadgeVariants {
+		if variant == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Autogenerate fills a sidebar group with the pages of a directory
+type Autogenerate struct {
+	Directory string `js:"directory"`
+}
+
+// EditLink adds an "Edit page" link to every page
+type EditLink struct {
+	BaseURL string `js:"baseUrl"`
+}
+
+// Locale is a language the site is translated to
+type Locale struct {
+	Label string `js:"label"`
+	Lang  string `js:"lang,omitempty"` // BCP-47 tag, defaults to the locale's directory name
+}
+
+// TableOfContents sets the headings listed in the table of contents on the right
+type TableOfContents struct {
+	Disabled        bool // Hide the table of contents
+	MinHeadingLevel int
+	MaxHeadingLevel int
+}
+
+// Validate checks the options Starlight would reject when building
+func (c *StarlightConfig) Validate() error {
+	if strings.TrimSpace(c.Title) == "" {
+		return fmt.Errorf("starlight config: title is required")
+	}
+	if c.DefaultLocale != "" {
+		if _, ok := c.Locales[c.DefaultLocale]; !ok {
+			return fmt.Errorf("starlight config: default locale %q is not one of the locales", c.DefaultLocale)
+		}
+	}
+	if toc := c.TableOfContents; toc != nil && !toc.Disabled {
+		if toc.MinHeadingLevel < 1 || toc.MaxHeadingLevel > 6 || toc.MinHeadingLevel > toc.MaxHeadingLevel {
+			return fmt.Errorf("starlight config: table of contents levels must satisfy 1 <= min <= max <= 6, got %d and %d",
+				toc.MinHeadingLevel, toc.MaxHeadingLevel)
+		}
+	}
+	for _, item := range c.Sidebar {
+		if err := item.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validate checks that a sidebar item is exactly one of a link, a group or an autogenerated group
+func (item SidebarItem) validate() error {
+	kinds := 0
+	for _, set := range []bool{item.Link != "", item.Slug != "", item.Items != nil, item.Autogenerate != nil} {
+		if set {
+			kinds++
+		}
+	}
+	if kinds != 1 {
+		return fmt.Errorf("starlight config: sidebar item %q needs exactly one of link, slug, items or autogenerate", item.Label)
+	}
+	if item.Slug == "" && item.Label == "" {
+		return fmt.Errorf("starlight config: sidebar items need a label")
+	}
+	if item.Badge != nil && !ValidBadgeVariant(item.Badge.Variant) {
+		return fmt.Errorf("starlight config: sidebar item %q has badge variant %q, want one of %s",
+			item.Label, item.Badge.Variant, strings.Join(BadgeVariants, ", "))
+	}
+	for _, child := range item.Items {
+		if err := child.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MarshalJS writes false for a disabled table of contents, the heading levels otherwise
+func (t TableOfContents) MarshalJS() string {
+	if t.Disabled {
+		return "false"
+	}
+	return fmt.Sprintf("{ minHeadingLevel: %d, maxHeadingLevel: %d }", t.MinHeadingLevel, t.MaxHeadingLevel)
+}
+
+// jsMarshaler is implemented by values with their own JavaScript form
+type jsMarshaler interface {
+	MarshalJS() string
+}
+
+// Lines renders the options as the properties of a JavaScript object literal, one per
+// line, each starting with indent
+func (c *StarlightConfig) Lines(indent string) []string {
+	var lines []string
+	for _, field := range jsFields(reflect.ValueOf(*c)) {
+		value := jsValue(field.value, indent)
+		lines = append(lines, indent+jsKey(field.name)+": "+value+",")
+	}
+	return lines
+}
+
+// jsField is a struct field to write, with its JavaScript name
+type jsField struct {
+	name  string
+	value reflect.Value
+}
+
+// jsFields returns the fields of a struct that are written, in declaration order
+func jsFields(v reflect.Value) []jsField {
+	var fields []jsField
+	for i := 0; i < v.NumField(); i++ {
+		tag, ok := v.Type().Field(i).Tag.Lookup("js")
+		if !ok {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if options == "omitempty" && isEmpty(v.Field(i)) {
+			continue
+		}
+		fields = append(fields, jsField{name, v.Field(i)})
+	}
+	return fields
+}
+
+// isEmpty reports whether an omitempty field is left out: zero values and empty lists and maps
+func isEmpty(v reflect.Value) bool {
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Map {
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+// jsValue renders a value as a JavaScript literal. Objects of plain values stay on one
+// line; objects holding other objects or lists span several, indented from indent.
+func jsValue(v reflect.Value, indent string) string {
+	if m, ok := v.Interface().(jsMarshaler); ok {
+		return m.MarshalJS()
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		return jsValue(v.Elem(), indent)
+	case reflect.String:
+		return jsString(v.String())
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+
+	case reflect.Slice:
+		items := make([]string, v.Len())
+		nested := false
+		for i := range items {
+			items[i] = jsValue(v.Index(i), indent+"  ")
+			nested = nested || isComposite(v.Index(i))
+		}
+		if !nested {
+			return "[" + strings.Join(items, ", ") + "]"
+		}
+		return "[\n" + indent + "  " + strings.Join(items, ",\n"+indent+"  ") + ",\n" + indent + "]"
+
+	case reflect.Map:
+		keys := make([]string, 0, v.Len())
+		for _, key := range v.MapKeys() {
+			keys = append(keys, key.String())
+		}
+		sort.Strings(keys)
+		fields := make([]jsField, len(keys))
+		for i, key := range keys {
+			fields[i] = jsField{key, v.MapIndex(reflect.ValueOf(key))}
+		}
+		return jsObject(fields, indent)
+
+	case reflect.Struct:
+		return jsObject(jsFields(v), indent)
+	}
+
+	panic(fmt.Sprintf("template: cannot write %s to astro.config.mjs", v.Type()))
+}
+
+// jsObject renders fields as an object literal
+func jsObject(fields []jsField, indent string) string {
+	nested := false
+	for _, field := range fields {
+		nested = nested || isComposite(field.value) || isList(field.value)
+	}
+
+	props := make([]string, len(fields))
+	for i, field := range fields {
+		props[i] = jsKey(field.name) + ": " + jsValue(field.value, indent+"  ")
+	}
+	if !nested {
+		return "{ " + strings.Join(props, ", ") + " }"
+	}
+	return "{\n" + indent + "  " + strings.Join(props, ",\n"+indent+"  ") + ",\n" + indent + "}"
+}
+
+// isComposite reports whether v is written as an object
+func isComposite(v reflect.Value) bool {
+	if _, ok := v.Interface().(jsMarshaler); ok {
+		return false
+	}
+	if v.Kind() == reflect.Pointer {
+		return isComposite(v.Elem())
+	}
+	return v.Kind() == reflect.Struct || v.Kind() == reflect.Map
+}
+
+// isList reports whether v is written as a list
+func isList(v reflect.Value) bool {
+	return v.Kind() == reflect.Slice
+}
+
+// identifier matches property names that need no quotes
+var identifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// jsKey writes a property name, quoting it unless it's an identifier
+func jsKey(name string) string {
+	if identifier.MatchString(name) {
+		return name
+	}
+	return jsString(name)
+}
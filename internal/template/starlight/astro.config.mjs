@@ -5,8 +5,7 @@ import starlight from '@astrojs/starlight';
 export default defineConfig({
   integrations: [
     starlight({
-      title: '{{SITE_TITLE}}',
-      defaultLocale: 'en',
+      // {{STARLIGHT_OPTIONS}}: flashdoc writes the site's options here
     }),
   ],
 });
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
@@ -59,8 +60,43 @@ func Extract(workspacePath string) error {
 	return nil
 }
 
-// GenerateConfig replaces the {{SITE_TITLE}} placeholder in astro.config.mjs
+// socialLabels maps Starlight social icon names to their display labels
+var socialLabels = map[string]string{
+	"github":    "GitHub",
+	"gitlab":    "GitLab",
+	"discord":   "Discord",
+	"x.com":     "X",
+	"twitter":   "Twitter",
+	"mastodon":  "Mastodon",
+	"linkedin":  "LinkedIn",
+	"youtube":   "YouTube",
+	"bluesky":   "Bluesky",
+	"email":     "Email",
+	"rss":       "RSS",
+	"slack":     "Slack",
+	"codeberg":  "Codeberg",
+	"instagram": "Instagram",
+}
+
+// OptionsMarker marks the line of astro.config.mjs that is replaced with the Starlight options
+const OptionsMarker = "{{STARLIGHT_OPTIONS}}"
+
+// titleMarker marks the options line in templates from before OptionsMarker, as part of
+// their title: '{{SITE_TITLE}}' line
+const titleMarker = "{{SITE_TITLE}}"
+
+// GenerateConfig writes Starlight options with just the title to astro.config.mjs
 func GenerateConfig(workspacePath, title string) error {
+	return WriteConfig(workspacePath, &StarlightConfig{Title: title})
+}
+
+// WriteConfig validates the Starlight options and writes them into astro.config.mjs in
+// place of the marker line, matching its indentation
+func WriteConfig(workspacePath string, cfg *StarlightConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("failed to generate config: %w", err)
+	}
+
 	configPath := filepath.Join(workspacePath, "astro.config.mjs")
 
 	content, err := os.ReadFile(configPath)
@@ -68,16 +104,79 @@ func GenerateConfig(workspacePath, title string) error {
 		return fmt.Errorf("failed to generate config: %w", err)
 	}
 
-	// Replace the placeholder
-	newContent := strings.ReplaceAll(string(content), "{{SITE_TITLE}}", title)
+	lines := strings.Split(string(content), "\n")
+	marker := -1
+	for i, line := range lines {
+		if strings.Contains(line, OptionsMarker) || strings.Contains(line, titleMarker) {
+			marker = i
+			break
+		}
+	}
+	if marker == -1 {
+		return fmt.Errorf("failed to generate config: %s has no %s line", configPath, OptionsMarker)
+	}
+
+	line := lines[marker]
+	options := cfg.Lines(line[:len(line)-len(strings.TrimLeft(line, " \t"))])
+	lines = append(lines[:marker], append(options, lines[marker+1:]...)...)
 
-	if err := os.WriteFile(configPath, []byte(newContent), 0644); err != nil {
+	if err := os.WriteFile(configPath, []byte(strings.Join(lines, "\n")), 0644); err != nil {
 		return fmt.Errorf("failed to generate config: %w", err)
 	}
 
 	return nil
 }
 
+// SocialLinks turns social icon names and links into header links, sorted by icon
+func SocialLinks(social map[string]string) []SocialLink {
+	icons := make([]string, 0, len(social))
+	for icon := range social {
+		icons = append(icons, icon)
+	}
+	sort.Strings(icons)
+
+	links := make([]SocialLink, len(icons))
+	for i, icon := range icons {
+		links[i] = SocialLink{Icon: icon, Label: SocialLabel(icon), Href: social[icon]}
+	}
+	return links
+}
+
+// SocialLabel returns the display label for a Starlight social icon name
+func SocialLabel(icon string) string {
+	if label, ok := socialLabels[icon]; ok {
+		return label
+	}
+	return icon
+}
+
+// jsString quotes a value as a single-quoted JavaScript string literal
+func jsString(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`, "\r", `\r`, "\t", `\t`,
+		"\u2028", `\u2028`, "\u2029", `\u2029`)
+	return "'" + replacer.Replace(value) + "'"
+}
+
+// CopyLogo copies a logo image into the workspace and returns its import path for astro.config.mjs
+func CopyLogo(workspacePath, logoPath string) (string, error) {
+	content, err := os.ReadFile(logoPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to copy logo: %w", err)
+	}
+
+	assetsDir := filepath.Join(workspacePath, "src", "assets")
+	if err := os.MkdirAll(assetsDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to copy logo: %w", err)
+	}
+
+	name := filepath.Base(logoPath)
+	if err := os.WriteFile(filepath.Join(assetsDir, name), content, 0644); err != nil {
+		return "", fmt.Errorf("failed to copy logo: %w", err)
+	}
+
+	return "./src/assets/" + name, nil
+}
+
 // GenerateTitle creates a title from a directory name
 func GenerateTitle(dirPath string) string {
 	// Get the base directory name
@@ -0,0 +1,247 @@
+package theme
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/heidene/flashdoc/internal/template"
+)
+
+// Conventional file names picked up from the root of the source directory
+var (
+	LogoNames      = []string{"logo.svg", "logo.png", "logo.webp", "logo.jpg"}
+	FaviconNames   = []string{"favicon.svg", "favicon.ico", "favicon.png"}
+	CustomCSSNames = []string{"custom.css"}
+)
+
+// Theme is how a site looks: a logo, a favicon, stylesheets and an accent color
+type Theme struct {
+	Logo      string   // Logo image file
+	Favicon   string   // Favicon file
+	CustomCSS []string // Stylesheets applied after the site's own
+	Accent    *Accent  // Accent colors, nil keeps the defaults
+}
+
+// Discover fills in what declared leaves out with conventional files from the root of the
+// source directory. Declared files always win.
+func Discover(sourceDir string, declared Theme) Theme {
+	t := declared
+	if t.Logo == "" {
+		t.Logo = findFile(sourceDir, LogoNames)
+	}
+	if t.Favicon == "" {
+		t.Favicon = findFile(sourceDir, FaviconNames)
+	}
+	if len(t.CustomCSS) == 0 {
+		if css := findFile(sourceDir, CustomCSSNames); css != "" {
+			t.CustomCSS = []string{css}
+		}
+	}
+	return t
+}
+
+// findFile returns the first of names that is a file in dir
+func findFile(dir string, names []string) string {
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
+
+// Apply copies the theme's files into the Astro workspace and adds them to the Starlight
+// config: the logo to src/assets, the favicon to public and the stylesheets, after the
+// accent colors, to src/styles
+func (t Theme) Apply(workspacePath string, cfg *template.StarlightConfig) error {
+	if t.Logo != "" {
+		logo, err := template.CopyLogo(workspacePath, t.Logo)
+		if err != nil {
+			return err
+		}
+		cfg.Logo = &template.Logo{Src: logo}
+	}
+
+	if t.Favicon != "" {
+		name := filepath.Base(t.Favicon)
+		if err := copyFile(t.Favicon, filepath.Join(workspacePath, "public", name)); err != nil {
+			return fmt.Errorf("failed to copy favicon: %w", err)
+		}
+		cfg.Favicon = "/" + name
+	}
+
+	stylesDir := filepath.Join(workspacePath, "src", "styles")
+	if t.Accent != nil {
+		if err := os.MkdirAll(stylesDir, 0755); err != nil {
+			return fmt.Errorf("failed to write accent colors: %w", err)
+		}
+		if err := os.WriteFile(filepath.Join(stylesDir, "flashdoc-accent.css"), []byte(t.Accent.StarlightCSS()), 0644); err != nil {
+			return fmt.Errorf("failed to write accent colors: %w", err)
+		}
+		cfg.CustomCSS = append(cfg.CustomCSS, "./src/styles/flashdoc-accent.css")
+	}
+
+	for i, css := range t.CustomCSS {
+		// Numbered, so stylesheets with the same name in different directories don't collide
+		name := fmt.Sprintf("%02d-%s", i+1, filepath.Base(css))
+		if err := copyFile(css, filepath.Join(stylesDir, name)); err != nil {
+			return fmt.Errorf("failed to copy stylesheet: %w", err)
+		}
+		cfg.CustomCSS = append(cfg.CustomCSS, "./src/styles/"+name)
+	}
+
+	return nil
+}
+
+// copyFile copies a file, creating the target directory
+func copyFile(src, dst string) error {
+	content, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(dst, content, 0644)
+}
+
+// Shades are the three accent colors of a color scheme: a background tint, the accent
+// itself and a high-contrast variant for text
+type Shades struct {
+	Low, Accent, High string
+}
+
+// Accent is an accent color with its shades for dark and light mode
+type Accent struct {
+	Name  string // Preset name or hex color it was made from
+	Dark  Shades
+	Light Shades
+}
+
+// Presets maps accent color presets to their base color
+var Presets = map[string]string{
+	"blue":   "#2563eb",
+	"indigo": "#4f46e5",
+	"purple": "#7c3aed",
+	"pink":   "#db2777",
+	"red":    "#dc2626",
+	"orange": "#ea580c",
+	"amber":  "#d97706",
+	"green":  "#16a34a",
+	"teal":   "#0d9488",
+	"cyan":   "#0891b2",
+	"slate":  "#475569",
+}
+
+// PresetNames returns the names of the accent color presets, sorted
+func PresetNames() []string {
+	names := make([]string, 0, len(Presets))
+	for name := range Presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseAccent makes an accent from a preset name or a hex color (#rgb or #rrggbb, the #
+// is optional). An empty value returns nil.
+func ParseAccent(value string) (*Accent, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	hex := value
+	if preset, ok := Presets[strings.ToLower(value)]; ok {
+		hex = preset
+	}
+	base, ok := parseHex(hex)
+	if !ok {
+		return nil, fmt.Errorf("invalid accent color %q (use a hex color like #2563eb or one of: %s)",
+			value, strings.Join(PresetNames(), ", "))
+	}
+
+	black, white := rgb{0, 0, 0}, rgb{255, 255, 255}
+	return &Accent{
+		Name: value,
+		Dark: Shades{
+			Low:    base.mix(black, 0.7).String(),
+			Accent: base.mix(white, 0.25).String(),
+			High:   base.mix(white, 0.75).String(),
+		},
+		Light: Shades{
+			Low:    base.mix(white, 0.8).String(),
+			Accent: base.String(),
+			High:   base.mix(black, 0.55).String(),
+		},
+	}, nil
+}
+
+// StarlightCSS sets Starlight's accent color variables; dark mode is Starlight's default
+func (a *Accent) StarlightCSS() string {
+	return fmt.Sprintf(`/* Accent color %s, generated by flashdoc */
+:root {
+  --sl-color-accent-low: %s;
+  --sl-color-accent: %s;
+  --sl-color-accent-high: %s;
+}
+
+:root[data-theme='light'] {
+  --sl-color-accent-low: %s;
+  --sl-color-accent: %s;
+  --sl-color-accent-high: %s;
+}
+`, a.Name, a.Dark.Low, a.Dark.Accent, a.Dark.High, a.Light.Low, a.Light.Accent, a.Light.High)
+}
+
+// NativeCSS sets the native renderer's accent variable; light mode is its default
+func (a *Accent) NativeCSS() string {
+	return fmt.Sprintf(`/* Accent color %s, generated by flashdoc */
+:root {
+  --accent: %s;
+}
+
+@media (prefers-color-scheme: dark) {
+  :root {
+    --accent: %s;
+  }
+}
+`, a.Name, a.Light.Accent, a.Dark.Accent)
+}
+
+// rgb is a color with 8-bit channels
+type rgb [3]float64
+
+// parseHex reads #rgb or #rrggbb, with or without the #
+func parseHex(value string) (rgb, bool) {
+	hex := strings.TrimPrefix(value, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return rgb{}, false
+	}
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return rgb{}, false
+	}
+	return rgb{float64(n >> 16 & 0xff), float64(n >> 8 & 0xff), float64(n & 0xff)}, true
+}
+
+// mix blends c with other, weight 0 keeping c and 1 giving other
+func (c rgb) mix(other rgb, weight float64) rgb {
+	var mixed rgb
+	for i := range c {
+		mixed[i] = c[i] + (other[i]-c[i])*weight
+	}
+	return mixed
+}
+
+// String returns the color as #rrggbb
+func (c rgb) String() string {
+	return fmt.Sprintf("#%02x%02x%02x", int(c[0]+0.5), int(c[1]+0.5), int(c[2]+0.5))
+}
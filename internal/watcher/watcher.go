@@ -0,0 +1,257 @@
+package watcher
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/heidene/flashdoc/internal/ignore"
+	"github.com/heidene/flashdoc/internal/scanner"
+)
+
+// DefaultDebounce is how long the watcher waits for further events before reporting a batch
+const DefaultDebounce = 200 * time.Millisecond
+
+// Watcher reports changes to markdown files, assets and components in a source directory
+type Watcher struct {
+	sourceDir string
+	ignore    *ignore.Matcher
+	debounce  time.Duration
+	fsw       *fsnotify.Watcher
+	files     map[string]string // Absolute path -> source-relative path of files watched on their own
+	changes   chan []string
+	errors    chan error
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// New creates a watcher for the given source directory
+func New(sourceDir string) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file watcher: %w", err)
+	}
+
+	return &Watcher{
+		sourceDir: sourceDir,
+		ignore:    ignore.New(),
+		debounce:  DefaultDebounce,
+		fsw:       fsw,
+		files:     make(map[string]string),
+		changes:   make(chan []string),
+		errors:    make(chan error, 1),
+		done:      make(chan struct{}),
+	}, nil
+}
+
+// SetIgnore sets the matcher deciding which source paths are not watched
+func (w *Watcher) SetIgnore(m *ignore.Matcher) {
+	w.ignore = m
+}
+
+// SetDebounce changes how long events are coalesced before being reported
+func (w *Watcher) SetDebounce(d time.Duration) {
+	w.debounce = d
+}
+
+// WatchFile also reports changes to a file that isn't watched as part of the source tree,
+// such as an ignored or neighbouring config file. Changes are reported by its path relative
+// to the source directory, e.g. ../book.toml. Call it before Start.
+func (w *Watcher) WatchFile(path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	rel, err := w.relPath(abs)
+	if err != nil {
+		return err
+	}
+	w.files[abs] = rel
+	return nil
+}
+
+// Start registers the source tree with the OS watcher and begins reporting changes
+func (w *Watcher) Start() error {
+	if err := w.addTree(w.sourceDir); err != nil {
+		return err
+	}
+	// Files are watched through their directory, as editors often replace them on save
+	for path := range w.files {
+		if err := w.fsw.Add(filepath.Dir(path)); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+	}
+
+	go w.loop()
+	return nil
+}
+
+// Changes returns a channel of batched changes, as paths relative to the source directory.
+// A path may refer to a file or directory that was created, modified or removed.
+func (w *Watcher) Changes() <-chan []string {
+	return w.changes
+}
+
+// Errors returns a channel of non-fatal watcher errors
+func (w *Watcher) Errors() <-chan error {
+	return w.errors
+}
+
+// Close stops watching and closes the changes channel
+func (w *Watcher) Close() error {
+	var err error
+	w.closeOnce.Do(func() {
+		close(w.done)
+		err = w.fsw.Close()
+	})
+	return err
+}
+
+// addTree adds a directory and all its non-skipped subdirectories to the watch list
+func (w *Watcher) addTree(root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Directory vanished or is unreadable - nothing to watch
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != root && w.skipDir(path) {
+			return fs.SkipDir
+		}
+		if err := w.fsw.Add(path); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+		return nil
+	})
+}
+
+// loop collects OS events and emits debounced batches
+func (w *Watcher) loop() {
+	defer close(w.changes)
+
+	pending := make(map[string]struct{})
+	var timer *time.Timer
+	var fire <-chan time.Time
+
+	for {
+		select {
+		case <-w.done:
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+
+		case event, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			rel, relevant := w.handleEvent(event)
+			if !relevant {
+				continue
+			}
+			pending[rel] = struct{}{}
+
+			if timer == nil {
+				timer = time.NewTimer(w.debounce)
+			} else {
+				if !timer.Stop() {
+					select {
+					case <-timer.C:
+					default:
+					}
+				}
+				timer.Reset(w.debounce)
+			}
+			fire = timer.C
+
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			select {
+			case w.errors <- err:
+			default:
+			}
+
+		case <-fire:
+			fire = nil
+			batch := make([]string, 0, len(pending))
+			for path := range pending {
+				batch = append(batch, path)
+			}
+			pending = make(map[string]struct{})
+
+			select {
+			case w.changes <- batch:
+			case <-w.done:
+				return
+			}
+		}
+	}
+}
+
+// handleEvent filters an OS event and returns the source-relative path it affects
+func (w *Watcher) handleEvent(event fsnotify.Event) (string, bool) {
+	if event.Op == fsnotify.Chmod {
+		return "", false
+	}
+	if abs, err := filepath.Abs(event.Name); err == nil {
+		if rel, ok := w.files[abs]; ok {
+			return rel, true
+		}
+	}
+
+	rel, err := filepath.Rel(w.sourceDir, event.Name)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", false
+	}
+
+	info, err := os.Stat(event.Name)
+	isDir := err == nil && info.IsDir()
+	if w.ignore.Ignored(rel, isDir) {
+		return "", false
+	}
+
+	if isDir {
+		// New directories need to be watched as well
+		if event.Has(fsnotify.Create) {
+			if err := w.addTree(event.Name); err != nil {
+				select {
+				case w.errors <- err:
+				default:
+				}
+			}
+			return rel, true
+		}
+		return "", false
+	}
+
+	// Removed or renamed paths may have been directories, so report them regardless of extension
+	if event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+		return rel, true
+	}
+
+	return rel, scanner.IsMarkdownFile(event.Name) || scanner.IsAssetFile(event.Name) || scanner.IsComponentFile(event.Name)
+}
+
+// relPath returns the path of an absolute path relative to the source directory
+func (w *Watcher) relPath(abs string) (string, error) {
+	sourceDir, err := filepath.Abs(w.sourceDir)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Rel(sourceDir, abs)
+}
+
+// skipDir reports whether a directory is excluded from watching
+func (w *Watcher) skipDir(path string) bool {
+	rel, err := filepath.Rel(w.sourceDir, path)
+	return err == nil && w.ignore.Ignored(rel, true)
+}
@@ -59,6 +59,9 @@ func (w *Workspace) createSymlinks() error {
 	symlinks := map[string]string{
 		"node_modules": filepath.Join(w.SharedDir, "node_modules"),
 		"package.json": filepath.Join(w.SharedDir, "package.json"),
+		// Yarn berry only runs scripts in a project it has installed
+		"yarn.lock": filepath.Join(w.SharedDir, "yarn.lock"),
+		".yarn":     filepath.Join(w.SharedDir, ".yarn"),
 	}
 
 	for linkName, target := range symlinks {
@@ -132,6 +135,16 @@ func (w *Workspace) GetDocsDir() string {
 	return filepath.Join(w.Path, "src", "content", "docs")
 }
 
+// GetComponentsDir returns the path MDX components from the source directory are copied to
+func (w *Workspace) GetComponentsDir() string {
+	return filepath.Join(w.Path, "src", "components")
+}
+
+// GetPublicDir returns the path to the public directory (static files served as-is)
+func (w *Workspace) GetPublicDir() string {
+	return filepath.Join(w.Path, "public")
+}
+
 // GetDistDir returns the path to the dist directory (build output)
 func (w *Workspace) GetDistDir() string {
 	return filepath.Join(w.Path, "dist")